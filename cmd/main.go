@@ -2,19 +2,37 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	_ "github.com/alishashelby/marketplace/docs"
 	"github.com/alishashelby/marketplace/internal/application/middleware"
 	"github.com/alishashelby/marketplace/internal/application/service"
 	"github.com/alishashelby/marketplace/internal/application/validator"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/blob"
+	"github.com/alishashelby/marketplace/internal/infrastructure/fetch"
+	"github.com/alishashelby/marketplace/internal/infrastructure/pubsub"
 	"github.com/alishashelby/marketplace/internal/infrastructure/repository/ad"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/category"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/conversation"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/favorite"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/image"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/notification"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/review"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/search"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/token"
 	"github.com/alishashelby/marketplace/internal/infrastructure/repository/user"
 	"github.com/alishashelby/marketplace/internal/presentation/controller"
+	"github.com/alishashelby/marketplace/internal/presentation/realtime"
 	"github.com/gorilla/mux"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -23,6 +41,19 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+	revocationCleanupInterval = 10 * time.Minute
+	adVerificationWorkers     = 4
+	adVerificationSweep       = 5 * time.Minute
+	adVerificationRetryDelay  = 2 * time.Second
+	savedSearchInterval       = 10 * time.Minute
+	outboxRelayInterval       = 5 * time.Second
+	outboxRetryDelay          = 10 * time.Second
+	shutdownTimeout           = 15 * time.Second
+	blobStoreS3               = "s3"
+	defaultMediaDir           = "media"
+)
+
 //	@title			Marketplace API
 //	@version		1.0
 //	@description	This is API for online marketplace.
@@ -60,7 +91,12 @@ func main() {
 
 	mongoDB := client.Database(os.Getenv("MONGO_DB"))
 
-	handler, err := registerRoutes(postgresDB, mongoDB)
+	events := pubsub.NewMemoryPubSub()
+	hub := realtime.NewHub(events)
+	adStream := realtime.NewAdStream(events)
+	go adStream.Run(context.Background())
+
+	handler, err := registerRoutes(postgresDB, mongoDB, events, hub, adStream)
 	if err != nil {
 		log.Printf("Error initializing routes: %s", err)
 		return
@@ -71,12 +107,41 @@ func main() {
 		addr = ":8080"
 	}
 
+	server := &http.Server{
+		Addr:    addr,
+		Handler: handler,
+	}
+
+	stop, stopCancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopCancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-stop.Done()
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer shutdownCancel()
+
+		// SSE responses never end on their own.
+		adStream.Shutdown()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Print("HTTP server shutdown error: ", err)
+		}
+		if err := hub.Shutdown(shutdownCtx); err != nil {
+			log.Print("WebSocket hub shutdown error: ", err)
+		}
+	}()
+
 	log.Printf("Listening on %s", addr)
-	err = http.ListenAndServe(addr, handler)
-	if err != nil {
+	err = server.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
 		log.Print(err)
 		return
 	}
+
+	<-stopped
+	log.Print("Server stopped")
 }
 
 func getDotEnvVariable(key string) (string, error) {
@@ -156,21 +221,152 @@ func connectMongo(ctx context.Context) (*mongo.Client, error) {
 	return client, nil
 }
 
-func registerRoutes(postgresDB *pgxpool.Pool, mongoDB *mongo.Database) (http.Handler, error) {
+func newBlobStore() (service.BlobStore, error) {
+	if os.Getenv("BLOB_STORE") != blobStoreS3 {
+		dir := os.Getenv("MEDIA_DIR")
+		if dir == "" {
+			dir = defaultMediaDir
+		}
+
+		return blob.NewLocalStore(dir)
+	}
+
+	return blob.NewS3Store(blob.S3Config{
+		Endpoint:  os.Getenv("S3_ENDPOINT"),
+		Region:    os.Getenv("S3_REGION"),
+		Bucket:    os.Getenv("S3_BUCKET"),
+		AccessKey: os.Getenv("S3_ACCESS_KEY"),
+		SecretKey: os.Getenv("S3_SECRET_KEY"),
+	})
+}
+
+func newImageFetcher() (*fetch.Client, error) {
+	config := fetch.Config{
+		Allowlist: splitList(os.Getenv("IMAGE_FETCH_ALLOWLIST")),
+	}
+
+	for _, value := range splitList(os.Getenv("IMAGE_FETCH_PORTS")) {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IMAGE_FETCH_PORTS entry %q: %w", value, err)
+		}
+		config.Ports = append(config.Ports, port)
+	}
+
+	return fetch.NewClient(config)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func registerRoutes(postgresDB *pgxpool.Pool, mongoDB *mongo.Database, events service.PubSub,
+	hub *realtime.Hub, adStream *realtime.AdStream) (http.Handler, error) {
 	jwtService, err := service.NewJWTService()
 	if err != nil {
 		return nil, err
 	}
 
+	keyController := controller.NewKeyController(jwtService)
+
 	userRepo := user.NewUserRepoPostgres(postgresDB)
-	userService := service.NewUserService(userRepo, jwtService)
+	revocationRepo := token.NewRevocationRepoPostgres(postgresDB)
+	revocationService := service.NewRevocationService(revocationRepo, jwtService.TTL())
+	go revocationService.RunCleanup(context.Background(), revocationCleanupInterval)
+
+	refreshTokenRepo := token.NewRefreshTokenRepoPostgres(postgresDB)
+	tokenService, err := service.NewTokenService(jwtService, refreshTokenRepo, userRepo, revocationService)
+	if err != nil {
+		return nil, err
+	}
+	userService := service.NewUserService(userRepo, tokenService)
 	userValidator := validator.NewUserValidator()
 	userController := controller.NewUserController(userService, userValidator)
 
+	categoryRepo := category.NewCategoryRepoPostgres(postgresDB)
+	categoryService := service.NewCategoryService(categoryRepo)
+	categoryController := controller.NewCategoryController(categoryService)
+
+	blobStore, err := newBlobStore()
+	if err != nil {
+		return nil, err
+	}
+
+	imageRepo := image.NewImageRepoMongoDB(mongoDB)
+	imageService := service.NewImageService(imageRepo, blobStore, os.Getenv("MEDIA_BASE_URL"))
+	imageController := controller.NewImageController(imageService, validator.NewImageValidator())
+
 	adRepo := ad.NewAdRepoMongoDB(mongoDB)
-	adService := service.NewAdService(adRepo)
-	adValidator := validator.NewAdValidator()
-	adController := controller.NewAdController(adService, userService, adValidator)
+	if err = adRepo.EnsureIndexes(); err != nil {
+		return nil, err
+	}
+	migrated, err := adRepo.MigrateImages()
+	if err != nil {
+		return nil, err
+	}
+	if migrated > 0 {
+		log.Printf("Migrated images of %d ads", migrated)
+	}
+	migrated, err = adRepo.MigrateStatus()
+	if err != nil {
+		return nil, err
+	}
+	if migrated > 0 {
+		log.Printf("Listed %d ads stored without a status", migrated)
+	}
+	migrated, err = adRepo.MigrateListedAt()
+	if err != nil {
+		return nil, err
+	}
+	if migrated > 0 {
+		log.Printf("Set the listing time of %d ads", migrated)
+	}
+
+	imageFetcher, err := newImageFetcher()
+	if err != nil {
+		return nil, err
+	}
+
+	adService := service.NewAdService(adRepo, events)
+	adValidator := validator.NewAdValidator(categoryService, imageService, imageFetcher)
+	verificationService := service.NewAdVerificationService(adRepo, imageService, adValidator, events,
+		adVerificationRetryDelay)
+	go verificationService.Run(context.Background(), adVerificationWorkers, adVerificationSweep)
+	favoriteRepo := favorite.NewFavoriteRepoPostgres(postgresDB)
+	favoriteService := service.NewFavoriteService(favoriteRepo)
+	outboxRelayService := service.NewOutboxRelayService(user.NewOutboxRepoPostgres(postgresDB), adRepo,
+		favoriteRepo, imageService, outboxRetryDelay)
+	go outboxRelayService.Run(context.Background(), outboxRelayInterval)
+
+	conversationService := service.NewConversationService(conversation.NewConversationRepoPostgres(postgresDB), events)
+	conversationController := controller.NewConversationController(conversationService, adService, userService,
+		validator.NewMessageValidator())
+
+	reviewService := service.NewReviewService(review.NewReviewRepoPostgres(postgresDB), conversationService)
+	reviewController := controller.NewReviewController(reviewService, userService, validator.NewReviewValidator())
+
+	adController := controller.NewAdController(adService, userService, categoryService, imageService,
+		verificationService, favoriteService, conversationService, reviewService, adValidator)
+
+	profileController := controller.NewProfileController(userService, adService, reviewService)
+
+	adminController := controller.NewAdminController(userService, adService, favoriteService)
+
+	notificationService := service.NewNotificationService(notification.NewNotificationRepoPostgres(postgresDB), events)
+	notificationController := controller.NewNotificationController(notificationService)
+	savedSearchService := service.NewSavedSearchService(search.NewSavedSearchRepoPostgres(postgresDB), adRepo,
+		categoryService, notificationService)
+	go savedSearchService.Run(context.Background(), savedSearchInterval)
+	savedSearchController := controller.NewSavedSearchController(savedSearchService, adValidator)
+
+	realtimeController := controller.NewRealtimeController(hub, adStream, adValidator)
 
 	r := mux.NewRouter()
 
@@ -178,17 +374,83 @@ func registerRoutes(postgresDB *pgxpool.Pool, mongoDB *mongo.Database) (http.Han
 
 	public := r.NewRoute().Subrouter()
 
+	optional := r.NewRoute().Subrouter()
+	optional.Use(func(next http.Handler) http.Handler {
+		return middleware.OptionalAuthMiddleware(jwtService, revocationService, next)
+	})
+
+	websocket := r.NewRoute().Subrouter()
+	websocket.Use(func(next http.Handler) http.Handler {
+		return middleware.WebSocketAuthMiddleware(jwtService, revocationService, next)
+	})
+
 	authorized := r.NewRoute().Subrouter()
 	authorized.Use(func(next http.Handler) http.Handler {
-		return middleware.AuthMiddleware(jwtService, next)
+		return middleware.AuthMiddleware(jwtService, revocationService, next)
 	})
 
+	moderation := authorized.PathPrefix("/api/admin/ads").Subrouter()
+	moderation.Use(middleware.RequireRole(entity.RoleModerator, entity.RoleAdmin))
+
+	admin := authorized.PathPrefix("/api/admin/users").Subrouter()
+	admin.Use(middleware.RequireRole(entity.RoleAdmin))
+
+	public.HandleFunc("/.well-known/jwks.json", keyController.GetJWKS).Methods(http.MethodGet)
 	public.HandleFunc("/api/register", userController.Register).Methods(http.MethodPost)
 	public.HandleFunc("/api/login", userController.Login).Methods(http.MethodPost)
+	public.HandleFunc("/api/token/refresh", userController.RefreshToken).Methods(http.MethodPost)
 	public.HandleFunc("/api/ads", adController.GetAllAds).Methods(http.MethodGet)
-
+	public.HandleFunc("/api/ads/stream", realtimeController.StreamAds).Methods(http.MethodGet)
+	public.HandleFunc("/api/categories", categoryController.GetCategories).Methods(http.MethodGet)
+	public.HandleFunc("/api/users/{username}", profileController.GetProfile).Methods(http.MethodGet)
+	public.HandleFunc("/api/users/{id}/reviews", reviewController.GetUserReviews).Methods(http.MethodGet)
+	public.HandleFunc(service.MediaPathPrefix+"{id}", imageController.GetMedia).Methods(http.MethodGet)
+	public.HandleFunc(service.MediaPathPrefix+"{id}/{variant}", imageController.GetMediaVariant).Methods(http.MethodGet)
+
+	optional.HandleFunc("/api/ads/{id}", adController.GetAdByID).Methods(http.MethodGet)
+	optional.HandleFunc("/api/users/{username}/ads", adController.GetUserAds).Methods(http.MethodGet)
+
+	websocket.HandleFunc("/api/ws", realtimeController.Connect).Methods(http.MethodGet)
+
+	authorized.HandleFunc("/api/logout", userController.Logout).Methods(http.MethodPost)
+	authorized.HandleFunc("/api/logout/all", userController.LogoutAll).Methods(http.MethodPost)
+	authorized.HandleFunc("/api/me", userController.UpdateMe).Methods(http.MethodPatch)
+	authorized.HandleFunc("/api/images", imageController.UploadImage).Methods(http.MethodPost)
 	authorized.HandleFunc("/api/publish", adController.CreateAd).Methods(http.MethodPost)
 	authorized.HandleFunc("/api/ads/", adController.GetAdsWithOwned).Methods(http.MethodGet)
+	authorized.HandleFunc("/api/ads/{id}", adController.UpdateAd).Methods(http.MethodPut)
+	authorized.HandleFunc("/api/ads/{id}", adController.PatchAd).Methods(http.MethodPatch)
+	authorized.HandleFunc("/api/ads/{id}", adController.DeleteAd).Methods(http.MethodDelete)
+	authorized.HandleFunc("/api/ads/{id}/publish", adController.PublishAd).Methods(http.MethodPost)
+	authorized.HandleFunc("/api/ads/{id}/favorite", adController.AddFavorite).Methods(http.MethodPost)
+	authorized.HandleFunc("/api/ads/{id}/favorite", adController.RemoveFavorite).Methods(http.MethodDelete)
+	authorized.HandleFunc("/api/me/favorites", adController.GetFavorites).Methods(http.MethodGet)
+	authorized.HandleFunc("/api/me/ads", adController.GetMyAds).Methods(http.MethodGet)
+	authorized.HandleFunc("/api/ads/{id}/conversations", conversationController.StartConversation).
+		Methods(http.MethodPost)
+	authorized.HandleFunc("/api/ads/{id}/reviews", reviewController.CreateReview).Methods(http.MethodPost)
+	authorized.HandleFunc("/api/me/conversations", conversationController.GetConversations).Methods(http.MethodGet)
+	authorized.HandleFunc("/api/conversations/{id}/messages", conversationController.GetMessages).
+		Methods(http.MethodGet)
+	authorized.HandleFunc("/api/conversations/{id}/messages", conversationController.SendMessage).
+		Methods(http.MethodPost)
+	authorized.HandleFunc("/api/conversations/{id}/read", conversationController.MarkConversationRead).
+		Methods(http.MethodPost)
+	authorized.HandleFunc("/api/me/searches", savedSearchController.CreateSavedSearch).Methods(http.MethodPost)
+	authorized.HandleFunc("/api/me/searches", savedSearchController.GetSavedSearches).Methods(http.MethodGet)
+	authorized.HandleFunc("/api/me/searches/{id}", savedSearchController.DeleteSavedSearch).Methods(http.MethodDelete)
+	authorized.HandleFunc("/api/me/notifications", notificationController.GetNotifications).Methods(http.MethodGet)
+	authorized.HandleFunc("/api/me/notifications/read", notificationController.MarkAllNotificationsRead).
+		Methods(http.MethodPost)
+	authorized.HandleFunc("/api/me/notifications/{id}/read", notificationController.MarkNotificationRead).
+		Methods(http.MethodPost)
+
+	moderation.HandleFunc("/{id}", adminController.DeleteAd).Methods(http.MethodDelete)
+
+	admin.HandleFunc("", adminController.GetUsers).Methods(http.MethodGet)
+	admin.HandleFunc("/{id}/ban", adminController.BanUser).Methods(http.MethodPost)
+	admin.HandleFunc("/{id}/unban", adminController.UnbanUser).Methods(http.MethodPost)
+	admin.HandleFunc("/{id}", adminController.DeleteUser).Methods(http.MethodDelete)
 
 	handler := middleware.LoggingMiddleware(r)
 	handler = middleware.PanicMiddleware(handler)
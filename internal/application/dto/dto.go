@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"math"
+	"strings"
 	"time"
 
 	"github.com/alishashelby/marketplace/internal/domain/entity"
@@ -12,34 +14,550 @@ type UserDTO struct {
 	Password string `json:"password" validate:"required,min=8,max=15,containsany=!@#?$&%,containsany=1234567890" example:"1234567&"`
 }
 
+type UsernameDTO struct {
+	Username string `json:"username" validate:"required,min=3,max=30,alpha" example:"alisha"`
+}
+
+type RefreshTokenDTO struct {
+	RefreshToken string `json:"refresh_token" validate:"required" example:"kq3tKx0bq2m1Yb8QjZ5sT9lJr7aWc4VdNf6Ee2Hg1Io"`
+}
+
+type LogoutDTO struct {
+	RefreshToken string `json:"refresh_token,omitempty" example:"kq3tKx0bq2m1Yb8QjZ5sT9lJr7aWc4VdNf6Ee2Hg1Io"`
+}
+
+type TokenResponse struct {
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.e30.signature"`
+	RefreshToken string `json:"refresh_token" example:"kq3tKx0bq2m1Yb8QjZ5sT9lJr7aWc4VdNf6Ee2Hg1Io"`
+}
+
+type UserResponse struct {
+	ID       uuid.UUID  `json:"id" swaggertype:"string" format:"uuid" example:"0b6c3f5e-8a4d-4e8b-9c1f-2d7a6e5b4c3a"`
+	Username string     `json:"username" example:"alisha"`
+	Role     string     `json:"role" example:"user"`
+	BannedAt *time.Time `json:"banned_at,omitempty" example:"2026-10-17T09:00:00Z"`
+}
+
+type ProfileResponse struct {
+	ID          uuid.UUID `json:"id" swaggertype:"string" format:"uuid" example:"0b6c3f5e-8a4d-4e8b-9c1f-2d7a6e5b4c3a"`
+	Username    string    `json:"username" example:"alisha"`
+	JoinedAt    time.Time `json:"joined_at" example:"2026-10-17T09:00:00Z"`
+	AdCount     int64     `json:"ad_count" example:"7"`
+	Rating      float64   `json:"rating" example:"4.5"`
+	ReviewCount int64     `json:"review_count" example:"12"`
+}
+
+type UserListResponse struct {
+	Items []*UserResponse `json:"items"`
+	Total int64           `json:"total" example:"42"`
+	Page  int             `json:"page" example:"1"`
+	Limit int             `json:"limit" example:"10"`
+}
+
+// The single image fields of older clients are ignored when images is given.
 type AdDTO struct {
-	Title    string  `json:"title" validate:"required,min=5,max=20" example:"Title of test ad"`
-	Text     string  `json:"text" validate:"required,min=20,max=1000" example:"This is the test ad. Check new image."`
-	ImageURL string  `json:"image_url" validate:"required,url" example:"https://upload.wikimedia.org/wikipedia/commons/c/c7/Tabby_cat_with_blue_eyes-3336579.jpg"`
-	Price    float64 `json:"price" validate:"required,gt=0" example:"1500.5"`
+	Title      string       `json:"title" validate:"required,min=5,max=20" example:"Title of test ad"`
+	Text       string       `json:"text" validate:"required,min=20,max=1000" example:"This is the test ad. Check new image."`
+	ImageURL   string       `json:"image_url,omitempty" validate:"required_without_all=ImageID Images,omitempty,url" example:"https://upload.wikimedia.org/wikipedia/commons/c/c7/Tabby_cat_with_blue_eyes-3336579.jpg"`
+	ImageID    *uuid.UUID   `json:"image_id,omitempty" swaggertype:"string" format:"uuid" example:"5f0c2b9e-1d7a-4c3e-8b6f-9a2e4d1c7b50"`
+	Images     []AdImageDTO `json:"images,omitempty" validate:"omitempty,max=10,dive"`
+	Cover      int          `json:"cover" validate:"gte=0" example:"0"`
+	Price      float64      `json:"price" validate:"required,gt=0" example:"1500.5"`
+	CategoryID int64        `json:"category_id" validate:"required,gt=0" example:"7"`
+	Draft      bool         `json:"draft,omitempty" example:"false"`
+}
+
+type AdImageDTO struct {
+	URL     string     `json:"url,omitempty" validate:"required_without=ImageID,omitempty,url" example:"https://upload.wikimedia.org/wikipedia/commons/c/c7/Tabby_cat_with_blue_eyes-3336579.jpg"`
+	ImageID *uuid.UUID `json:"image_id,omitempty" swaggertype:"string" format:"uuid" example:"5f0c2b9e-1d7a-4c3e-8b6f-9a2e4d1c7b50"`
 }
 
+type AdPatchDTO struct {
+	Title      *string      `json:"title,omitempty" example:"Title of test ad"`
+	Text       *string      `json:"text,omitempty" example:"This is the test ad. Check new image."`
+	ImageURL   *string      `json:"image_url,omitempty" example:"https://upload.wikimedia.org/wikipedia/commons/c/c7/Tabby_cat_with_blue_eyes-3336579.jpg"`
+	ImageID    *uuid.UUID   `json:"image_id,omitempty" swaggertype:"string" format:"uuid" example:"5f0c2b9e-1d7a-4c3e-8b6f-9a2e4d1c7b50"`
+	Images     []AdImageDTO `json:"images,omitempty"`
+	Cover      *int         `json:"cover,omitempty" example:"0"`
+	Price      *float64     `json:"price,omitempty" example:"1500.5"`
+	CategoryID *int64       `json:"category_id,omitempty" example:"7"`
+}
+
+// image_url and image_id point to the cover for clients without gallery support.
 type AdResponse struct {
-	Title     string    `json:"title" example:"Title of test ad"`
-	Text      string    `json:"text" example:"This is the test ad. Check new image."`
-	ImageURL  string    `json:"image_url" example:"https://upload.wikimedia.org/wikipedia/commons/c/c7/Tabby_cat_with_blue_eyes-3336579.jpg"`
-	Price     float64   `json:"price" example:"1500.5"`
-	Username  string    `json:"username" example:"alisha"`
-	IsOwner   bool      `json:"is_owner,omitempty" example:"true"`
-	CreatedAt time.Time `json:"created_at" example:"2025-08-11T19:14:03.187Z"`
+	ID              uuid.UUID         `json:"id" swaggertype:"string" format:"uuid" example:"7d4b2f8e-3c1a-4f5e-9b6d-2a8c0e1f4b3d"`
+	Title           string            `json:"title" example:"Title of test ad"`
+	Text            string            `json:"text" example:"This is the test ad. Check new image."`
+	ImageURL        string            `json:"image_url" example:"https://upload.wikimedia.org/wikipedia/commons/c/c7/Tabby_cat_with_blue_eyes-3336579.jpg"`
+	ImageID         *uuid.UUID        `json:"image_id,omitempty" swaggertype:"string" format:"uuid" example:"5f0c2b9e-1d7a-4c3e-8b6f-9a2e4d1c7b50"`
+	Thumbnails      *Thumbnails       `json:"thumbnails,omitempty"`
+	Images          []AdImageResponse `json:"images"`
+	Cover           int               `json:"cover" example:"0"`
+	Price           float64           `json:"price" example:"1500.5"`
+	CategoryID      int64             `json:"category_id,omitempty" example:"7"`
+	Username        string            `json:"username" example:"alisha"`
+	IsOwner         bool              `json:"is_owner,omitempty" example:"true"`
+	IsFavorite      bool              `json:"is_favorite,omitempty" example:"true"`
+	SellerRating    float64           `json:"seller_rating,omitempty" example:"4.5"`
+	SellerReviews   int64             `json:"seller_reviews,omitempty" example:"12"`
+	Status          entity.AdStatus   `json:"status,omitempty" enums:"draft,pending,active,rejected,expired" example:"active"`
+	RejectionReason string            `json:"rejection_reason,omitempty" example:"images[0].url: error - image is not available: 404"`
+	Stats           *AdStatsResponse  `json:"stats,omitempty"`
+	CreatedAt       time.Time         `json:"created_at" example:"2025-08-11T19:14:03.187Z"`
+	UpdatedAt       *time.Time        `json:"updated_at,omitempty" example:"2025-08-12T10:01:45.512Z"`
+}
+
+type AdStatsResponse struct {
+	Views     int64 `json:"views" example:"120"`
+	Favorites int64 `json:"favorites" example:"8"`
+	Messages  int64 `json:"messages" example:"5"`
+}
+
+type AdImageResponse struct {
+	URL        string      `json:"url" example:"/media/5f0c2b9e-1d7a-4c3e-8b6f-9a2e4d1c7b50"`
+	ImageID    *uuid.UUID  `json:"image_id,omitempty" swaggertype:"string" format:"uuid" example:"5f0c2b9e-1d7a-4c3e-8b6f-9a2e4d1c7b50"`
+	Thumbnails *Thumbnails `json:"thumbnails,omitempty"`
+}
+
+type Thumbnails struct {
+	Thumbnail string `json:"thumbnail,omitempty" example:"/media/5f0c2b9e-1d7a-4c3e-8b6f-9a2e4d1c7b50/thumbnail"`
+	Medium    string `json:"medium,omitempty" example:"/media/5f0c2b9e-1d7a-4c3e-8b6f-9a2e4d1c7b50/medium"`
+}
+
+type ImageResponse struct {
+	ID          uuid.UUID   `json:"id" swaggertype:"string" format:"uuid" example:"5f0c2b9e-1d7a-4c3e-8b6f-9a2e4d1c7b50"`
+	URL         string      `json:"url" example:"/media/5f0c2b9e-1d7a-4c3e-8b6f-9a2e4d1c7b50"`
+	ContentType string      `json:"content_type" example:"image/jpeg"`
+	Size        int64       `json:"size" example:"184320"`
+	Width       int         `json:"width" example:"1280"`
+	Height      int         `json:"height" example:"960"`
+	Thumbnails  *Thumbnails `json:"thumbnails,omitempty"`
+}
+
+type AdListResponse struct {
+	Items      []*AdResponse `json:"items"`
+	Total      int64         `json:"total" example:"42"`
+	Page       int           `json:"page" example:"1"`
+	Limit      int           `json:"limit" example:"10"`
+	HasNext    bool          `json:"has_next" example:"true"`
+	NextCursor string        `json:"next_cursor,omitempty" example:"eyJzIjoiY3JlYXRlZF9hdCJ9"`
+	Links      ListLinks     `json:"links"`
+}
+
+// SavedSearchDTO uses the names of the /api/ads query parameters.
+type SavedSearchDTO struct {
+	Name     string  `json:"name" validate:"required,max=50" example:"Cheap bikes"`
+	Query    string  `json:"q,omitempty" example:"bike"`
+	MinPrice float64 `json:"min_price,omitempty" example:"100"`
+	MaxPrice float64 `json:"max_price,omitempty" example:"10000.5"`
+	Category int64   `json:"category,omitempty" example:"7"`
+	SortBy   string  `json:"sort_by,omitempty" enums:"created_at,price,relevance" example:"price"`
+	OrderBy  int     `json:"order_by,omitempty" enums:"1,-1" example:"1"`
+}
+
+type SavedSearchResponse struct {
+	ID        uuid.UUID `json:"id" swaggertype:"string" format:"uuid" example:"3e9a7c1d-5b2f-4d8e-a6c0-1f4b8d2e7a95"`
+	Name      string    `json:"name" example:"Cheap bikes"`
+	Query     string    `json:"q,omitempty" example:"bike"`
+	MinPrice  float64   `json:"min_price,omitempty" example:"100"`
+	MaxPrice  float64   `json:"max_price,omitempty" example:"10000.5"`
+	Category  int64     `json:"category,omitempty" example:"7"`
+	SortBy    string    `json:"sort_by" example:"price"`
+	OrderBy   int       `json:"order_by" example:"1"`
+	CreatedAt time.Time `json:"created_at" example:"2026-10-17T09:00:00Z"`
+}
+
+type NotificationResponse struct {
+	ID            uuid.UUID  `json:"id" swaggertype:"string" format:"uuid" example:"9c2e4a6b-8d1f-4e3a-b5c7-0a2d4f6e8b1c"`
+	Type          string     `json:"type" enums:"search_match" example:"search_match"`
+	SavedSearchID *uuid.UUID `json:"saved_search_id,omitempty" swaggertype:"string" format:"uuid" example:"3e9a7c1d-5b2f-4d8e-a6c0-1f4b8d2e7a95"`
+	SearchName    string     `json:"search_name,omitempty" example:"Cheap bikes"`
+	AdID          *uuid.UUID `json:"ad_id,omitempty" swaggertype:"string" format:"uuid" example:"7d4b2f8e-3c1a-4f5e-9b6d-2a8c0e1f4b3d"`
+	AdTitle       string     `json:"ad_title,omitempty" example:"Title of test ad"`
+	CreatedAt     time.Time  `json:"created_at" example:"2026-10-17T09:10:00Z"`
+	ReadAt        *time.Time `json:"read_at,omitempty" example:"2026-10-17T09:30:00Z"`
+}
+
+type NotificationListResponse struct {
+	Items  []*NotificationResponse `json:"items"`
+	Total  int64                   `json:"total" example:"42"`
+	Unread int64                   `json:"unread" example:"3"`
+	Page   int                     `json:"page" example:"1"`
+	Limit  int                     `json:"limit" example:"10"`
+}
+
+type MessageDTO struct {
+	Text string `json:"text" validate:"required,max=2000" example:"Hi! Is the bike still available?"`
+}
+
+type ReviewDTO struct {
+	Rating int    `json:"rating" validate:"required,min=1,max=5" example:"5"`
+	Text   string `json:"text" validate:"required,max=2000" example:"Friendly seller, the bike was just as described."`
+}
+
+type ParticipantResponse struct {
+	ID       uuid.UUID `json:"id" swaggertype:"string" format:"uuid" example:"0b6c3f5e-8a4d-4e8b-9c1f-2d7a6e5b4c3a"`
+	Username string    `json:"username" example:"alisha"`
+}
+
+// ConversationResponse sets unread only in listings.
+type ConversationResponse struct {
+	ID            uuid.UUID            `json:"id" swaggertype:"string" format:"uuid" example:"4a7c9e1b-2d3f-4b5a-8c6d-7e8f9a0b1c2d"`
+	AdID          uuid.UUID            `json:"ad_id" swaggertype:"string" format:"uuid" example:"7d4b2f8e-3c1a-4f5e-9b6d-2a8c0e1f4b3d"`
+	AdTitle       string               `json:"ad_title" example:"Title of test ad"`
+	Buyer         *ParticipantResponse `json:"buyer"`
+	Seller        *ParticipantResponse `json:"seller"`
+	Unread        int64                `json:"unread" example:"2"`
+	CreatedAt     time.Time            `json:"created_at" example:"2026-10-17T09:00:00Z"`
+	LastMessageAt time.Time            `json:"last_message_at" example:"2026-10-17T09:30:00Z"`
+}
+
+type ConversationListResponse struct {
+	Items  []*ConversationResponse `json:"items"`
+	Total  int64                   `json:"total" example:"4"`
+	Unread int64                   `json:"unread" example:"3"`
+	Page   int                     `json:"page" example:"1"`
+	Limit  int                     `json:"limit" example:"10"`
+}
+
+type ReviewResponse struct {
+	ID        uuid.UUID            `json:"id" swaggertype:"string" format:"uuid" example:"2c4e6a8f-1b3d-4f5a-9c7e-0d2f4b6a8c1e"`
+	AdID      uuid.UUID            `json:"ad_id" swaggertype:"string" format:"uuid" example:"7d4b2f8e-3c1a-4f5e-9b6d-2a8c0e1f4b3d"`
+	AdTitle   string               `json:"ad_title" example:"Title of test ad"`
+	Reviewer  *ParticipantResponse `json:"reviewer"`
+	Rating    int                  `json:"rating" example:"5"`
+	Text      string               `json:"text" example:"Friendly seller, the bike was just as described."`
+	CreatedAt time.Time            `json:"created_at" example:"2026-10-17T12:00:00Z"`
+}
+
+type ReviewListResponse struct {
+	Items  []*ReviewResponse `json:"items"`
+	Total  int64             `json:"total" example:"12"`
+	Rating float64           `json:"rating" example:"4.5"`
+	Page   int               `json:"page" example:"1"`
+	Limit  int               `json:"limit" example:"10"`
+}
+
+type MessageResponse struct {
+	ID             uuid.UUID  `json:"id" swaggertype:"string" format:"uuid" example:"6e1f3a5c-7b9d-4c2e-a4f6-8b0d2c4e6a8f"`
+	ConversationID uuid.UUID  `json:"conversation_id" swaggertype:"string" format:"uuid" example:"4a7c9e1b-2d3f-4b5a-8c6d-7e8f9a0b1c2d"`
+	SenderID       uuid.UUID  `json:"sender_id" swaggertype:"string" format:"uuid" example:"0b6c3f5e-8a4d-4e8b-9c1f-2d7a6e5b4c3a"`
+	Text           string     `json:"text" example:"Hi! Is the bike still available?"`
+	CreatedAt      time.Time  `json:"created_at" example:"2026-10-17T09:30:00Z"`
+	ReadAt         *time.Time `json:"read_at,omitempty" example:"2026-10-17T09:35:00Z"`
+}
+
+type MessageListResponse struct {
+	Items []*MessageResponse `json:"items"`
+	Total int64              `json:"total" example:"12"`
+	Page  int                `json:"page" example:"1"`
+	Limit int                `json:"limit" example:"10"`
+}
+
+type AdStatusResponse struct {
+	ID              uuid.UUID       `json:"id" swaggertype:"string" format:"uuid" example:"7d4b2f8e-3c1a-4f5e-9b6d-2a8c0e1f4b3d"`
+	Title           string          `json:"title" example:"Title of test ad"`
+	Status          entity.AdStatus `json:"status" swaggertype:"string" enums:"pending,active,rejected" example:"active"`
+	RejectionReason string          `json:"rejection_reason,omitempty" example:"images[0].url: url resolves to a non-public address"`
+}
+
+type EventResponse struct {
+	Type string `json:"type" enums:"message.created,notification.created,ad.status_changed" example:"message.created"`
+	Data any    `json:"data"`
+}
+
+type ListLinks struct {
+	Prev string `json:"prev,omitempty" example:"/api/ads?limit=10&page=1"`
+	Next string `json:"next,omitempty" example:"/api/ads?limit=10&page=3"`
+}
+
+func NewTokenResponse(pair *entity.TokenPair) TokenResponse {
+	return TokenResponse{
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+	}
+}
+
+func NewUserResponse(user *entity.User) *UserResponse {
+	return &UserResponse{
+		ID:       user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+		BannedAt: user.BannedAt,
+	}
+}
+
+func NewProfileResponse(user *entity.User, adCount int64, rating *entity.Rating) *ProfileResponse {
+	return &ProfileResponse{
+		ID:          user.ID,
+		Username:    user.Username,
+		JoinedAt:    user.CreatedAt,
+		AdCount:     adCount,
+		Rating:      RoundRating(rating.Average()),
+		ReviewCount: rating.Count,
+	}
+}
+
+// Options applies the /api/ads defaults to the omitted sort parameters.
+func (d *SavedSearchDTO) Options() *entity.Options {
+	ops := &entity.Options{
+		Page:       1,
+		Limit:      entity.LimitDefaultValue,
+		SortBy:     d.SortBy,
+		OrderBy:    d.OrderBy,
+		MinPrice:   d.MinPrice,
+		MaxPrice:   d.MaxPrice,
+		Query:      strings.TrimSpace(d.Query),
+		CategoryID: d.Category,
+	}
+	if ops.SortBy == "" {
+		ops.SortBy = entity.SortByCreatedAt
+	}
+	if ops.OrderBy == 0 {
+		ops.OrderBy = entity.OrderByDesc
+	}
+
+	return ops
+}
+
+func NewSavedSearchResponse(search *entity.SavedSearch) *SavedSearchResponse {
+	return &SavedSearchResponse{
+		ID:        search.ID,
+		Name:      search.Name,
+		Query:     search.Query,
+		MinPrice:  search.MinPrice,
+		MaxPrice:  search.MaxPrice,
+		Category:  search.CategoryID,
+		SortBy:    search.SortBy,
+		OrderBy:   search.OrderBy,
+		CreatedAt: search.CreatedAt,
+	}
+}
+
+func NewNotificationResponse(notification *entity.Notification) *NotificationResponse {
+	return &NotificationResponse{
+		ID:            notification.ID,
+		Type:          notification.Type,
+		SavedSearchID: notification.SavedSearchID,
+		SearchName:    notification.SearchName,
+		AdID:          notification.AdID,
+		AdTitle:       notification.AdTitle,
+		CreatedAt:     notification.CreatedAt,
+		ReadAt:        notification.ReadAt,
+	}
+}
+
+func NewConversationResponse(conversation *entity.Conversation) *ConversationResponse {
+	return &ConversationResponse{
+		ID:      conversation.ID,
+		AdID:    conversation.AdID,
+		AdTitle: conversation.AdTitle,
+		Buyer: &ParticipantResponse{
+			ID:       conversation.BuyerID,
+			Username: conversation.BuyerUsername,
+		},
+		Seller: &ParticipantResponse{
+			ID:       conversation.SellerID,
+			Username: conversation.SellerUsername,
+		},
+		Unread:        conversation.Unread,
+		CreatedAt:     conversation.CreatedAt,
+		LastMessageAt: conversation.LastMessageAt,
+	}
+}
+
+func NewMessageResponse(message *entity.Message) *MessageResponse {
+	return &MessageResponse{
+		ID:             message.ID,
+		ConversationID: message.ConversationID,
+		SenderID:       message.SenderID,
+		Text:           message.Text,
+		CreatedAt:      message.CreatedAt,
+		ReadAt:         message.ReadAt,
+	}
+}
+
+func NewReviewResponse(review *entity.Review) *ReviewResponse {
+	return &ReviewResponse{
+		ID:      review.ID,
+		AdID:    review.AdID,
+		AdTitle: review.AdTitle,
+		Reviewer: &ParticipantResponse{
+			ID:       review.ReviewerID,
+			Username: review.ReviewerUsername,
+		},
+		Rating:    review.Rating,
+		Text:      review.Text,
+		CreatedAt: review.CreatedAt,
+	}
+}
+
+func RoundRating(average float64) float64 {
+	return math.Round(average*100) / 100
+}
+
+func NewEventResponse(event *entity.Event) *EventResponse {
+	var data any
+	switch payload := event.Payload.(type) {
+	case *entity.Message:
+		data = NewMessageResponse(payload)
+	case *entity.Notification:
+		data = NewNotificationResponse(payload)
+	case *entity.Ad:
+		data = &AdStatusResponse{
+			ID:              payload.ID,
+			Title:           payload.Title,
+			Status:          payload.Status,
+			RejectionReason: payload.RejectionReason,
+		}
+	default:
+		return nil
+	}
+
+	return &EventResponse{
+		Type: event.Type,
+		Data: data,
+	}
+}
+
+func NewImageResponse(image *entity.Image, url string, variantURLs map[string]string) *ImageResponse {
+	return &ImageResponse{
+		ID:          image.ID,
+		URL:         url,
+		ContentType: image.ContentType,
+		Size:        image.Size,
+		Width:       image.Width,
+		Height:      image.Height,
+		Thumbnails:  newThumbnails(variantURLs),
+	}
+}
+
+// For an uploaded image only its ID is set, since the URL is our own.
+func NewAdDTO(ad *entity.Ad) AdDTO {
+	images := make([]AdImageDTO, 0, len(ad.Images))
+	for _, image := range ad.Images {
+		if image.ImageID != nil {
+			images = append(images, AdImageDTO{ImageID: image.ImageID})
+			continue
+		}
+		images = append(images, AdImageDTO{URL: image.URL})
+	}
+
+	return AdDTO{
+		Title:      ad.Title,
+		Text:       ad.Text,
+		Images:     images,
+		Cover:      ad.Cover,
+		Price:      ad.Price,
+		CategoryID: ad.CategoryID,
+	}
+}
+
+func (d *AdDTO) ImageList() []AdImageDTO {
+	switch {
+	case len(d.Images) > 0:
+		return d.Images
+	case d.ImageID != nil:
+		return []AdImageDTO{{ImageID: d.ImageID}}
+	case d.ImageURL != "":
+		return []AdImageDTO{{URL: d.ImageURL}}
+	default:
+		return nil
+	}
+}
+
+// Apply returns a copy of adDTO with the fields present in the patch overwritten.
+func (p *AdPatchDTO) Apply(adDTO AdDTO) AdDTO {
+	if p.Title != nil {
+		adDTO.Title = *p.Title
+	}
+	if p.Text != nil {
+		adDTO.Text = *p.Text
+	}
+	// A single image replaces the whole gallery.
+	if p.ImageURL != nil {
+		adDTO.ImageURL = *p.ImageURL
+		adDTO.ImageID = nil
+		adDTO.Images = nil
+		adDTO.Cover = 0
+	}
+	if p.ImageID != nil {
+		adDTO.ImageID = p.ImageID
+		adDTO.ImageURL = ""
+		adDTO.Images = nil
+		adDTO.Cover = 0
+	}
+	if p.Images != nil {
+		adDTO.Images = p.Images
+	}
+	if p.Cover != nil {
+		adDTO.Cover = *p.Cover
+	}
+	if p.Price != nil {
+		adDTO.Price = *p.Price
+	}
+	if p.CategoryID != nil {
+		adDTO.CategoryID = *p.CategoryID
+	}
+
+	return adDTO
 }
 
 func NewAdResponse(ad *entity.Ad) *AdResponse {
-	return &AdResponse{
-		Title:     ad.Title,
-		Text:      ad.Text,
-		ImageURL:  ad.ImageURL,
-		Price:     ad.Price,
-		Username:  ad.Author.Username,
-		CreatedAt: ad.CreatedAt,
+	resp := &AdResponse{
+		ID:         ad.ID,
+		Title:      ad.Title,
+		Text:       ad.Text,
+		Images:     make([]AdImageResponse, 0, len(ad.Images)),
+		Cover:      ad.Cover,
+		Price:      ad.Price,
+		CategoryID: ad.CategoryID,
+		Username:   ad.Author.Username,
+		CreatedAt:  ad.CreatedAt,
+	}
+	for _, image := range ad.Images {
+		resp.Images = append(resp.Images, AdImageResponse{
+			URL:        image.URL,
+			ImageID:    image.ImageID,
+			Thumbnails: newThumbnails(image.Thumbnails),
+		})
+	}
+	if cover := ad.CoverImage(); cover != nil {
+		resp.ImageURL = cover.URL
+		resp.ImageID = cover.ImageID
+		resp.Thumbnails = newThumbnails(cover.Thumbnails)
+	}
+	if !ad.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &ad.UpdatedAt
+	}
+
+	return resp
+}
+
+func newThumbnails(urls map[string]string) *Thumbnails {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	return &Thumbnails{
+		Thumbnail: urls[entity.VariantThumbnail],
+		Medium:    urls[entity.VariantMedium],
+	}
+}
+
+func (ar *AdResponse) SetSellerRating(rating *entity.Rating) {
+	if rating == nil {
+		return
 	}
+
+	ar.SellerRating = RoundRating(rating.Average())
+	ar.SellerReviews = rating.Count
 }
 
 func (ar *AdResponse) ProcessOwner(ad *entity.Ad, curAuthorizedUserID uuid.UUID) {
 	ar.IsOwner = ad.Author.ID == curAuthorizedUserID
+	if ar.IsOwner {
+		ar.Status = ad.Status
+		ar.RejectionReason = ad.RejectionReason
+	}
 }
@@ -4,11 +4,15 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
 	"github.com/alishashelby/marketplace/pkg"
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 )
 
 const (
@@ -19,58 +23,199 @@ const (
 	reportMissingUserIDKey           = "no appropriate ID key found in bearer token"
 	reportUnexpectedStringError      = "unexpected format of userID"
 	reportInvalidUserIDKey           = "userID should be a UUID"
+	reportMissingTokenID             = "no valid token ID found in bearer token"
+	reportMissingTimestamps          = "no valid iat and exp found in bearer token"
+	reportRevokedToken               = "token has been revoked"
+	reportRevocationCheckFailed      = "failed to check token revocation"
+	reportInvalidRole                = "unexpected format of role"
+	reportForbidden                  = "insufficient role for this action"
 )
 
-func AuthMiddleware(jwtService *service.JWTService, next http.Handler) http.Handler {
+// WebSocketTokenProtocol is the subprotocol that precedes the token of a
+// WebSocket handshake.
+const WebSocketTokenProtocol = "access_token"
+
+type session struct {
+	userID    uuid.UUID
+	role      string
+	tokenID   uuid.UUID
+	expiresAt time.Time
+}
+
+func (s *session) withContext(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, service.UserIDKey, s.userID)
+	ctx = context.WithValue(ctx, service.RoleKey, s.role)
+	ctx = context.WithValue(ctx, service.TokenIDKey, s.tokenID)
+
+	return context.WithValue(ctx, service.ExpiryKey, s.expiresAt)
+}
+
+func AuthMiddleware(jwtService *service.JWTService, revocations *service.RevocationService,
+	next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		log.Print("AuthMiddleware")
 
-		tokenString := strings.TrimSpace(
-			strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
-		)
-
-		if tokenString == "" {
-			pkg.SendJSON(w, http.StatusUnauthorized, reportMissingAuthorizationHeader)
+		session, report := authenticate(jwtService, revocations, bearerToken(r))
+		if report != "" {
+			pkg.SendJSON(w, http.StatusUnauthorized, report)
 			return
 		}
 
-		jwtClaims, err := jwtService.ParseToken(tokenString)
-		if err != nil {
-			pkg.SendJSON(w, http.StatusUnauthorized, reportParsingError)
-			return
-		}
+		next.ServeHTTP(w, r.WithContext(session.withContext(r.Context())))
+	})
+}
 
-		userClaim, exists := jwtClaims[string(service.UserKey)]
-		if !exists {
-			pkg.SendJSON(w, http.StatusUnauthorized, reportMissingUserKey)
-			return
-		}
+// OptionalAuthMiddleware puts the user ID into the request context when a valid
+// bearer token is present, and lets anonymous requests through untouched.
+func OptionalAuthMiddleware(jwtService *service.JWTService, revocations *service.RevocationService,
+	next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Print("OptionalAuthMiddleware")
 
-		userMap, ok := userClaim.(map[string]interface{})
-		if !ok {
-			pkg.SendJSON(w, http.StatusUnauthorized, reportInvalidUserData)
+		session, report := authenticate(jwtService, revocations, bearerToken(r))
+		if report != "" {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		rawUserID, exists := userMap[string(service.UserIDKey)]
-		if !exists {
-			pkg.SendJSON(w, http.StatusUnauthorized, reportMissingUserIDKey)
-			return
-		}
+		next.ServeHTTP(w, r.WithContext(session.withContext(r.Context())))
+	})
+}
 
-		stringUserID, ok := rawUserID.(string)
-		if !ok {
-			pkg.SendJSON(w, http.StatusUnauthorized, reportUnexpectedStringError)
-			return
+// Browsers can set no header on a WebSocket handshake but the subprotocols, so
+// they pass WebSocketTokenProtocol followed by the token.
+func WebSocketAuthMiddleware(jwtService *service.JWTService, revocations *service.RevocationService,
+	next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Print("WebSocketAuthMiddleware")
+
+		tokenString := bearerToken(r)
+		if tokenString == "" {
+			tokenString = protocolToken(r)
 		}
 
-		userID, err := uuid.Parse(stringUserID)
-		if err != nil {
-			pkg.SendJSON(w, http.StatusUnauthorized, reportInvalidUserIDKey)
+		session, report := authenticate(jwtService, revocations, tokenString)
+		if report != "" {
+			pkg.SendJSON(w, http.StatusUnauthorized, report)
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), service.UserIDKey, userID)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r.WithContext(session.withContext(r.Context())))
 	})
 }
+
+// RequireRole has to run after AuthMiddleware.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := r.Context().Value(service.RoleKey).(string)
+			if !ok {
+				pkg.SendJSON(w, http.StatusUnauthorized, reportMissingUserKey)
+				return
+			}
+
+			if !slices.Contains(roles, role) {
+				pkg.SendJSON(w, http.StatusForbidden, reportForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func bearerToken(r *http.Request) string {
+	return strings.TrimSpace(
+		strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+	)
+}
+
+func protocolToken(r *http.Request) string {
+	protocols := websocket.Subprotocols(r)
+	if len(protocols) != 2 || protocols[0] != WebSocketTokenProtocol {
+		return ""
+	}
+
+	return protocols[1]
+}
+
+func authenticate(jwtService *service.JWTService, revocations *service.RevocationService,
+	tokenString string) (*session, string) {
+	if tokenString == "" {
+		return nil, reportMissingAuthorizationHeader
+	}
+
+	jwtClaims, err := jwtService.ParseToken(tokenString)
+	if err != nil {
+		return nil, reportParsingError
+	}
+
+	userClaim, exists := jwtClaims[string(service.UserKey)]
+	if !exists {
+		return nil, reportMissingUserKey
+	}
+
+	userMap, ok := userClaim.(map[string]interface{})
+	if !ok {
+		return nil, reportInvalidUserData
+	}
+
+	rawUserID, exists := userMap[string(service.UserIDKey)]
+	if !exists {
+		return nil, reportMissingUserIDKey
+	}
+
+	stringUserID, ok := rawUserID.(string)
+	if !ok {
+		return nil, reportUnexpectedStringError
+	}
+
+	userID, err := uuid.Parse(stringUserID)
+	if err != nil {
+		return nil, reportInvalidUserIDKey
+	}
+
+	// Tokens issued before roles were introduced have no role claim.
+	role := entity.RoleUser
+	if rawRole, exists := userMap[string(service.RoleKey)]; exists {
+		if role, ok = rawRole.(string); !ok {
+			return nil, reportInvalidRole
+		}
+	}
+
+	rawTokenID, ok := jwtClaims[string(service.TokenIDKey)].(string)
+	if !ok {
+		return nil, reportMissingTokenID
+	}
+
+	tokenID, err := uuid.Parse(rawTokenID)
+	if err != nil {
+		return nil, reportMissingTokenID
+	}
+
+	issuedAt, err := jwtClaims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return nil, reportMissingTimestamps
+	}
+
+	expiresAt, err := jwtClaims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return nil, reportMissingTimestamps
+	}
+
+	revoked, err := revocations.IsRevoked(tokenID, userID, issuedAt.Time, expiresAt.Time)
+	if err != nil {
+		log.Print("authenticate revocation check error: ", err)
+		return nil, reportRevocationCheckFailed
+	}
+	if revoked {
+		return nil, reportRevokedToken
+	}
+
+	return &session{
+		userID:    userID,
+		role:      role,
+		tokenID:   tokenID,
+		expiresAt: expiresAt.Time,
+	}, ""
+}
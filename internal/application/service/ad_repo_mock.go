@@ -9,6 +9,7 @@ import (
 
 	entity "github.com/alishashelby/marketplace/internal/domain/entity"
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 )
 
 // MockAdRepository is a mock of AdRepository interface.
@@ -34,6 +35,50 @@ func (m *MockAdRepository) EXPECT() *MockAdRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockAdRepository) Count(ops *entity.Options) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ops)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockAdRepositoryMockRecorder) Count(ops interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockAdRepository)(nil).Count), ops)
+}
+
+// Delete mocks base method.
+func (m *MockAdRepository) Delete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAdRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAdRepository)(nil).Delete), id)
+}
+
+// DeleteByAuthor mocks base method.
+func (m *MockAdRepository) DeleteByAuthor(authorID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByAuthor", authorID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByAuthor indicates an expected call of DeleteByAuthor.
+func (mr *MockAdRepositoryMockRecorder) DeleteByAuthor(authorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByAuthor", reflect.TypeOf((*MockAdRepository)(nil).DeleteByAuthor), authorID)
+}
+
 // FindAll mocks base method.
 func (m *MockAdRepository) FindAll(ops *entity.Options) ([]*entity.Ad, error) {
 	m.ctrl.T.Helper()
@@ -49,6 +94,95 @@ func (mr *MockAdRepositoryMockRecorder) FindAll(ops interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockAdRepository)(nil).FindAll), ops)
 }
 
+// FindByID mocks base method.
+func (m *MockAdRepository) FindByID(id uuid.UUID) (*entity.Ad, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", id)
+	ret0, _ := ret[0].(*entity.Ad)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockAdRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockAdRepository)(nil).FindByID), id)
+}
+
+// FindIDsByAuthor mocks base method.
+func (m *MockAdRepository) FindIDsByAuthor(authorID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIDsByAuthor", authorID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIDsByAuthor indicates an expected call of FindIDsByAuthor.
+func (mr *MockAdRepositoryMockRecorder) FindIDsByAuthor(authorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIDsByAuthor", reflect.TypeOf((*MockAdRepository)(nil).FindIDsByAuthor), authorID)
+}
+
+// FindMatches mocks base method.
+func (m *MockAdRepository) FindMatches(ops *entity.Options) ([]*entity.Ad, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMatches", ops)
+	ret0, _ := ret[0].([]*entity.Ad)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMatches indicates an expected call of FindMatches.
+func (mr *MockAdRepositoryMockRecorder) FindMatches(ops interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMatches", reflect.TypeOf((*MockAdRepository)(nil).FindMatches), ops)
+}
+
+// FindPendingIDs mocks base method.
+func (m *MockAdRepository) FindPendingIDs() ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingIDs")
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingIDs indicates an expected call of FindPendingIDs.
+func (mr *MockAdRepositoryMockRecorder) FindPendingIDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingIDs", reflect.TypeOf((*MockAdRepository)(nil).FindPendingIDs))
+}
+
+// IncrementViews mocks base method.
+func (m *MockAdRepository) IncrementViews(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementViews", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementViews indicates an expected call of IncrementViews.
+func (mr *MockAdRepositoryMockRecorder) IncrementViews(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementViews", reflect.TypeOf((*MockAdRepository)(nil).IncrementViews), id)
+}
+
+// RenameAuthor mocks base method.
+func (m *MockAdRepository) RenameAuthor(authorID uuid.UUID, username string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameAuthor", authorID, username)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameAuthor indicates an expected call of RenameAuthor.
+func (mr *MockAdRepositoryMockRecorder) RenameAuthor(authorID, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameAuthor", reflect.TypeOf((*MockAdRepository)(nil).RenameAuthor), authorID, username)
+}
+
 // Save mocks base method.
 func (m *MockAdRepository) Save(ad *entity.Ad) error {
 	m.ctrl.T.Helper()
@@ -62,3 +196,32 @@ func (mr *MockAdRepositoryMockRecorder) Save(ad interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockAdRepository)(nil).Save), ad)
 }
+
+// Update mocks base method.
+func (m *MockAdRepository) Update(ad *entity.Ad) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ad)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAdRepositoryMockRecorder) Update(ad interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAdRepository)(nil).Update), ad)
+}
+
+// UpdateVerification mocks base method.
+func (m *MockAdRepository) UpdateVerification(ad *entity.Ad) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVerification", ad)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVerification indicates an expected call of UpdateVerification.
+func (mr *MockAdRepositoryMockRecorder) UpdateVerification(ad interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVerification", reflect.TypeOf((*MockAdRepository)(nil).UpdateVerification), ad)
+}
@@ -1,25 +1,129 @@
 package service
 
-import "github.com/alishashelby/marketplace/internal/domain/entity"
+import (
+	"errors"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrorNotAdAuthor      = errors.New("only the author can modify this ad")
+	ErrorAdNotPublishable = errors.New("only drafts and expired ads can be published")
+)
 
 //go:generate mockgen -source=ad_service.go -destination=ad_repo_mock.go -package=service AdRepository
 type AdRepository interface {
 	Save(ad *entity.Ad) error
 	FindAll(ops *entity.Options) ([]*entity.Ad, error)
+	FindMatches(ops *entity.Options) ([]*entity.Ad, error)
+	Count(ops *entity.Options) (int64, error)
+	FindByID(id uuid.UUID) (*entity.Ad, error)
+	Update(ad *entity.Ad) error
+	IncrementViews(id uuid.UUID) error
+	RenameAuthor(authorID uuid.UUID, username string) (int64, error)
+	Delete(id uuid.UUID) error
+	DeleteByAuthor(authorID uuid.UUID) (int64, error)
+	FindPendingIDs() ([]uuid.UUID, error)
+	FindIDsByAuthor(authorID uuid.UUID) ([]uuid.UUID, error)
+	UpdateVerification(ad *entity.Ad) (bool, error)
 }
 
 type AdService struct {
-	repo AdRepository
+	repo   AdRepository
+	events Publisher
 }
 
-func NewAdService(repo AdRepository) *AdService {
-	return &AdService{repo: repo}
+func NewAdService(repo AdRepository, events Publisher) *AdService {
+	return &AdService{
+		repo:   repo,
+		events: events,
+	}
 }
 
 func (s *AdService) Create(ad *entity.Ad) error {
-	return s.repo.Save(ad)
+	if err := s.repo.Save(ad); err != nil {
+		return err
+	}
+
+	if ad.Status == entity.AdStatusActive {
+		publishListed(s.events, ad)
+	}
+
+	return nil
 }
 
 func (s *AdService) GetAds(ops *entity.Options) ([]*entity.Ad, error) {
 	return s.repo.FindAll(ops)
 }
+
+func (s *AdService) CountAds(ops *entity.Options) (int64, error) {
+	return s.repo.Count(ops)
+}
+
+func (s *AdService) RecordView(id uuid.UUID) error {
+	return s.repo.IncrementViews(id)
+}
+
+func (s *AdService) GetByID(id uuid.UUID) (*entity.Ad, error) {
+	return s.repo.FindByID(id)
+}
+
+// GetOwned returns the ad only if it was published by the given user.
+func (s *AdService) GetOwned(id, userID uuid.UUID) (*entity.Ad, error) {
+	ad, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if ad.Author == nil || ad.Author.ID != userID {
+		return nil, ErrorNotAdAuthor
+	}
+
+	return ad, nil
+}
+
+func (s *AdService) Update(ad *entity.Ad) error {
+	return s.repo.Update(ad)
+}
+
+func (s *AdService) Publish(id, userID uuid.UUID) (*entity.Ad, error) {
+	ad, err := s.GetOwned(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	firstListing := ad.ListedAt.IsZero()
+	if !ad.Publish() {
+		return nil, ErrorAdNotPublishable
+	}
+	if err = s.repo.Update(ad); err != nil {
+		return nil, err
+	}
+
+	if ad.Status == entity.AdStatusActive && firstListing {
+		publishListed(s.events, ad)
+	}
+
+	return ad, nil
+}
+
+func (s *AdService) Delete(id, userID uuid.UUID) error {
+	if _, err := s.GetOwned(id, userID); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(id)
+}
+
+// DeleteAny removes the ad regardless of its author; it is meant for moderation.
+func (s *AdService) DeleteAny(id uuid.UUID) error {
+	return s.repo.Delete(id)
+}
+
+func publishListed(events Publisher, ad *entity.Ad) {
+	events.Publish(AdsTopic, &entity.Event{
+		Type:    entity.EventAdCreated,
+		Payload: ad,
+	})
+}
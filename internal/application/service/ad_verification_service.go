@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	verificationQueueSize   = 1024
+	verificationMaxAttempts = 3
+	imageWorkers            = 4
+	ReportRejectedImage     = "images[%d].url: %v"
+)
+
+var ErrorAdEdited = errors.New("ad was edited during verification")
+
+// ImageFetcher downloads an external image; temporary errors are retried.
+type ImageFetcher interface {
+	FetchImage(url string) (string, []byte, error)
+}
+
+type temporary interface {
+	Temporary() bool
+}
+
+type fetchedImage struct {
+	contentType string
+	data        []byte
+	err         error
+}
+
+// AdVerificationService verifies the external images of pending ads in the
+// background; the periodic sweep picks up the ads the queue missed.
+type AdVerificationService struct {
+	repo       AdRepository
+	images     *ImageService
+	fetcher    ImageFetcher
+	events     Publisher
+	retryDelay time.Duration
+	queue      chan uuid.UUID
+
+	mu     sync.Mutex
+	queued map[uuid.UUID]struct{}
+}
+
+func NewAdVerificationService(repo AdRepository, images *ImageService, fetcher ImageFetcher, events Publisher,
+	retryDelay time.Duration) *AdVerificationService {
+	return &AdVerificationService{
+		repo:       repo,
+		images:     images,
+		fetcher:    fetcher,
+		events:     events,
+		retryDelay: retryDelay,
+		queue:      make(chan uuid.UUID, verificationQueueSize),
+		queued:     make(map[uuid.UUID]struct{}),
+	}
+}
+
+// Enqueue schedules the verification of the ad without blocking.
+func (s *AdVerificationService) Enqueue(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.queued[id]; ok {
+		return
+	}
+
+	select {
+	case s.queue <- id:
+		s.queued[id] = struct{}{}
+	default:
+		log.Printf("AdVerificationService queue is full, ad %s waits for the next sweep", id)
+	}
+}
+
+func (s *AdVerificationService) Run(ctx context.Context, workers int, sweepInterval time.Duration) {
+	for range workers {
+		go s.work(ctx)
+	}
+
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		s.sweep()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *AdVerificationService) sweep() {
+	ids, err := s.repo.FindPendingIDs()
+	if err != nil {
+		log.Print("AdVerificationService.sweep error: ", err)
+		return
+	}
+
+	for _, id := range ids {
+		s.Enqueue(id)
+	}
+}
+
+func (s *AdVerificationService) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			err := s.Verify(ctx, id)
+
+			// Still marked as queued, so an edit made meanwhile is queued below.
+			s.mu.Lock()
+			delete(s.queued, id)
+			s.mu.Unlock()
+
+			switch {
+			case errors.Is(err, ErrorAdEdited):
+				s.Enqueue(id)
+			case err != nil && ctx.Err() == nil:
+				log.Printf("AdVerificationService failed to verify ad %s: %v", id, err)
+			}
+		}
+	}
+}
+
+func (s *AdVerificationService) Verify(ctx context.Context, id uuid.UUID) error {
+	ad, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if ad.Status != entity.AdStatusPending {
+		return nil
+	}
+
+	fetched := s.fetchPending(ctx, ad.Images)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	for i := range ad.Images {
+		image := &ad.Images[i]
+		if !image.Pending {
+			continue
+		}
+
+		result := fetched[i]
+		if result.err != nil {
+			ad.Reject(fmt.Sprintf(ReportRejectedImage, i, result.err))
+			break
+		}
+
+		image.Pending = false
+		stored, err := s.images.Import(ad.Author.ID, image.URL, result.contentType, result.data)
+		if err != nil {
+			log.Printf("AdVerificationService failed to make variants of %s: %v", image.URL, err)
+			continue
+		}
+		image.Thumbnails = s.images.VariantURLs(stored)
+	}
+	firstListing := ad.ListedAt.IsZero()
+	ad.Activate()
+
+	stored, err := s.repo.UpdateVerification(ad)
+	if err != nil {
+		return err
+	}
+	if !stored {
+		return ErrorAdEdited
+	}
+
+	s.events.Publish(UserTopic(ad.Author.ID), &entity.Event{
+		Type:    entity.EventAdStatusChanged,
+		Payload: ad,
+	})
+	if ad.Status == entity.AdStatusActive && firstListing {
+		publishListed(s.events, ad)
+	}
+
+	return nil
+}
+
+// The results of fetchPending keep the positions of the images.
+func (s *AdVerificationService) fetchPending(ctx context.Context, images []entity.AdImage) []fetchedImage {
+	pending := make([]int, 0, len(images))
+	for i, image := range images {
+		if image.Pending {
+			pending = append(pending, i)
+		}
+	}
+
+	fetched := make([]fetchedImage, len(images))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for range min(imageWorkers, len(pending)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				result := &fetched[i]
+				result.contentType, result.data, result.err = s.fetch(ctx, images[i].URL)
+			}
+		}()
+	}
+
+	for _, i := range pending {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return fetched
+}
+
+func (s *AdVerificationService) fetch(ctx context.Context, url string) (string, []byte, error) {
+	delay := s.retryDelay
+
+	for attempt := 1; ; attempt++ {
+		contentType, data, err := s.fetcher.FetchImage(url)
+		if err == nil {
+			return contentType, data, nil
+		}
+
+		var tmp temporary
+		if attempt == verificationMaxAttempts || !errors.As(err, &tmp) || !tmp.Temporary() {
+			return "", nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import "github.com/alishashelby/marketplace/internal/domain/entity"
+
+//go:generate mockgen -source=category_service.go -destination=category_repo_mock.go -package=service CategoryRepository
+type CategoryRepository interface {
+	FindAll() ([]*entity.Category, error)
+	ExistsByID(id int64) (bool, error)
+	FindSubtreeIDs(id int64) ([]int64, error)
+}
+
+type CategoryService struct {
+	repo CategoryRepository
+}
+
+func NewCategoryService(repo CategoryRepository) *CategoryService {
+	return &CategoryService{repo: repo}
+}
+
+func (s *CategoryService) GetTree() ([]*entity.Category, error) {
+	categories, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]*entity.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	roots := make([]*entity.Category, 0)
+	for _, c := range categories {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+
+		if parent, ok := byID[*c.ParentID]; ok {
+			parent.Children = append(parent.Children, c)
+		}
+	}
+
+	return roots, nil
+}
+
+func (s *CategoryService) Exists(id int64) (bool, error) {
+	return s.repo.ExistsByID(id)
+}
+
+func (s *CategoryService) GetSubtreeIDs(id int64) ([]int64, error) {
+	return s.repo.FindSubtreeIDs(id)
+}
@@ -0,0 +1,200 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: conversation_service.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/alishashelby/marketplace/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockConversationRepository is a mock of ConversationRepository interface.
+type MockConversationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConversationRepositoryMockRecorder
+}
+
+// MockConversationRepositoryMockRecorder is the mock recorder for MockConversationRepository.
+type MockConversationRepositoryMockRecorder struct {
+	mock *MockConversationRepository
+}
+
+// NewMockConversationRepository creates a new mock instance.
+func NewMockConversationRepository(ctrl *gomock.Controller) *MockConversationRepository {
+	mock := &MockConversationRepository{ctrl: ctrl}
+	mock.recorder = &MockConversationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConversationRepository) EXPECT() *MockConversationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountByUser mocks base method.
+func (m *MockConversationRepository) CountByUser(userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByUser", userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByUser indicates an expected call of CountByUser.
+func (mr *MockConversationRepositoryMockRecorder) CountByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByUser", reflect.TypeOf((*MockConversationRepository)(nil).CountByUser), userID)
+}
+
+// CountMessages mocks base method.
+func (m *MockConversationRepository) CountMessages(conversationID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountMessages", conversationID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountMessages indicates an expected call of CountMessages.
+func (mr *MockConversationRepositoryMockRecorder) CountMessages(conversationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountMessages", reflect.TypeOf((*MockConversationRepository)(nil).CountMessages), conversationID)
+}
+
+// CountMessagesByAds mocks base method.
+func (m *MockConversationRepository) CountMessagesByAds(adIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountMessagesByAds", adIDs)
+	ret0, _ := ret[0].(map[uuid.UUID]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountMessagesByAds indicates an expected call of CountMessagesByAds.
+func (mr *MockConversationRepositoryMockRecorder) CountMessagesByAds(adIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountMessagesByAds", reflect.TypeOf((*MockConversationRepository)(nil).CountMessagesByAds), adIDs)
+}
+
+// CountUnread mocks base method.
+func (m *MockConversationRepository) CountUnread(userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnread", userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnread indicates an expected call of CountUnread.
+func (mr *MockConversationRepositoryMockRecorder) CountUnread(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnread", reflect.TypeOf((*MockConversationRepository)(nil).CountUnread), userID)
+}
+
+// FindByAd mocks base method.
+func (m *MockConversationRepository) FindByAd(adID, buyerID uuid.UUID) (*entity.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByAd", adID, buyerID)
+	ret0, _ := ret[0].(*entity.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByAd indicates an expected call of FindByAd.
+func (mr *MockConversationRepositoryMockRecorder) FindByAd(adID, buyerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByAd", reflect.TypeOf((*MockConversationRepository)(nil).FindByAd), adID, buyerID)
+}
+
+// FindByID mocks base method.
+func (m *MockConversationRepository) FindByID(id uuid.UUID) (*entity.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", id)
+	ret0, _ := ret[0].(*entity.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockConversationRepositoryMockRecorder) FindByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockConversationRepository)(nil).FindByID), id)
+}
+
+// FindByUser mocks base method.
+func (m *MockConversationRepository) FindByUser(userID uuid.UUID, limit, offset int) ([]*entity.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", userID, limit, offset)
+	ret0, _ := ret[0].([]*entity.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockConversationRepositoryMockRecorder) FindByUser(userID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockConversationRepository)(nil).FindByUser), userID, limit, offset)
+}
+
+// FindMessages mocks base method.
+func (m *MockConversationRepository) FindMessages(conversationID uuid.UUID, limit, offset int) ([]*entity.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMessages", conversationID, limit, offset)
+	ret0, _ := ret[0].([]*entity.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMessages indicates an expected call of FindMessages.
+func (mr *MockConversationRepositoryMockRecorder) FindMessages(conversationID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMessages", reflect.TypeOf((*MockConversationRepository)(nil).FindMessages), conversationID, limit, offset)
+}
+
+// MarkRead mocks base method.
+func (m *MockConversationRepository) MarkRead(conversationID, readerID uuid.UUID, readAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", conversationID, readerID, readAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockConversationRepositoryMockRecorder) MarkRead(conversationID, readerID, readAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockConversationRepository)(nil).MarkRead), conversationID, readerID, readAt)
+}
+
+// SaveMessage mocks base method.
+func (m *MockConversationRepository) SaveMessage(message *entity.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMessage", message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMessage indicates an expected call of SaveMessage.
+func (mr *MockConversationRepositoryMockRecorder) SaveMessage(message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMessage", reflect.TypeOf((*MockConversationRepository)(nil).SaveMessage), message)
+}
+
+// Start mocks base method.
+func (m *MockConversationRepository) Start(conversation *entity.Conversation, message *entity.Message) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", conversation, message)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockConversationRepositoryMockRecorder) Start(conversation, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockConversationRepository)(nil).Start), conversation, message)
+}
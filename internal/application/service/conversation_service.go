@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrorConversationNotFound = errors.New("conversation not found")
+	ErrorConversationOwnAd    = errors.New("you cannot start a conversation about your own ad")
+)
+
+//go:generate mockgen -source=conversation_service.go -destination=conversation_repo_mock.go -package=service ConversationRepository
+type ConversationRepository interface {
+	Start(conversation *entity.Conversation, message *entity.Message) (bool, error)
+	FindByID(id uuid.UUID) (*entity.Conversation, error)
+	FindByAd(adID, buyerID uuid.UUID) (*entity.Conversation, error)
+	FindByUser(userID uuid.UUID, limit, offset int) ([]*entity.Conversation, error)
+	CountByUser(userID uuid.UUID) (int64, error)
+	CountUnread(userID uuid.UUID) (int64, error)
+	SaveMessage(message *entity.Message) error
+	FindMessages(conversationID uuid.UUID, limit, offset int) ([]*entity.Message, error)
+	CountMessages(conversationID uuid.UUID) (int64, error)
+	CountMessagesByAds(adIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	MarkRead(conversationID, readerID uuid.UUID, readAt time.Time) error
+}
+
+type ConversationService struct {
+	repo   ConversationRepository
+	events Publisher
+}
+
+func NewConversationService(repo ConversationRepository, events Publisher) *ConversationService {
+	return &ConversationService{
+		repo:   repo,
+		events: events,
+	}
+}
+
+// Start reports false if the message was added to an existing conversation.
+func (s *ConversationService) Start(ad *entity.Ad, buyer *entity.User, text string) (*entity.Conversation, bool, error) {
+	if ad.Author == nil || ad.Author.ID == buyer.ID {
+		return nil, false, ErrorConversationOwnAd
+	}
+
+	conversation := entity.NewConversation(ad, buyer)
+	message := entity.NewMessage(conversation.ID, buyer.ID, text)
+
+	created, err := s.repo.Start(conversation, message)
+	if err != nil {
+		return nil, false, err
+	}
+	s.publish(conversation, message)
+
+	return conversation, created, nil
+}
+
+// Get returns the conversation if the user takes part in it.
+func (s *ConversationService) Get(id, userID uuid.UUID) (*entity.Conversation, error) {
+	conversation, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if conversation == nil || !conversation.HasParticipant(userID) {
+		return nil, ErrorConversationNotFound
+	}
+
+	return conversation, nil
+}
+
+func (s *ConversationService) GetByAd(adID, buyerID uuid.UUID) (*entity.Conversation, error) {
+	conversation, err := s.repo.FindByAd(adID, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation == nil {
+		return nil, ErrorConversationNotFound
+	}
+
+	return conversation, nil
+}
+
+func (s *ConversationService) GetConversations(userID uuid.UUID, page, limit int) ([]*entity.Conversation, error) {
+	return s.repo.FindByUser(userID, limit, (page-1)*limit)
+}
+
+func (s *ConversationService) Count(userID uuid.UUID) (int64, error) {
+	return s.repo.CountByUser(userID)
+}
+
+func (s *ConversationService) CountUnread(userID uuid.UUID) (int64, error) {
+	return s.repo.CountUnread(userID)
+}
+
+func (s *ConversationService) Send(id, senderID uuid.UUID, text string) (*entity.Message, error) {
+	conversation, err := s.Get(id, senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := entity.NewMessage(conversation.ID, senderID, text)
+	if err = s.repo.SaveMessage(message); err != nil {
+		return nil, err
+	}
+	s.publish(conversation, message)
+
+	return message, nil
+}
+
+// GetMessages expects the caller to have checked the participant with Get.
+func (s *ConversationService) GetMessages(conversation *entity.Conversation, page, limit int) ([]*entity.Message, error) {
+	return s.repo.FindMessages(conversation.ID, limit, (page-1)*limit)
+}
+
+func (s *ConversationService) CountMessages(conversation *entity.Conversation) (int64, error) {
+	return s.repo.CountMessages(conversation.ID)
+}
+
+func (s *ConversationService) CountMessagesByAds(adIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	if len(adIDs) == 0 {
+		return make(map[uuid.UUID]int64), nil
+	}
+
+	return s.repo.CountMessagesByAds(adIDs)
+}
+
+func (s *ConversationService) MarkRead(id, userID uuid.UUID) error {
+	if _, err := s.Get(id, userID); err != nil {
+		return err
+	}
+
+	return s.repo.MarkRead(id, userID, time.Now())
+}
+
+func (s *ConversationService) publish(conversation *entity.Conversation, message *entity.Message) {
+	event := &entity.Event{
+		Type:    entity.EventMessageCreated,
+		Payload: message,
+	}
+
+	s.events.Publish(UserTopic(conversation.BuyerID), event)
+	s.events.Publish(UserTopic(conversation.SellerID), event)
+}
@@ -0,0 +1,151 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: favorite_service.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockFavoriteRepository is a mock of FavoriteRepository interface.
+type MockFavoriteRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFavoriteRepositoryMockRecorder
+}
+
+// MockFavoriteRepositoryMockRecorder is the mock recorder for MockFavoriteRepository.
+type MockFavoriteRepositoryMockRecorder struct {
+	mock *MockFavoriteRepository
+}
+
+// NewMockFavoriteRepository creates a new mock instance.
+func NewMockFavoriteRepository(ctrl *gomock.Controller) *MockFavoriteRepository {
+	mock := &MockFavoriteRepository{ctrl: ctrl}
+	mock.recorder = &MockFavoriteRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFavoriteRepository) EXPECT() *MockFavoriteRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockFavoriteRepository) Add(userID, adID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", userID, adID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockFavoriteRepositoryMockRecorder) Add(userID, adID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockFavoriteRepository)(nil).Add), userID, adID)
+}
+
+// CountByAds mocks base method.
+func (m *MockFavoriteRepository) CountByAds(adIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByAds", adIDs)
+	ret0, _ := ret[0].(map[uuid.UUID]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByAds indicates an expected call of CountByAds.
+func (mr *MockFavoriteRepositoryMockRecorder) CountByAds(adIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByAds", reflect.TypeOf((*MockFavoriteRepository)(nil).CountByAds), adIDs)
+}
+
+// CountByUser mocks base method.
+func (m *MockFavoriteRepository) CountByUser(userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByUser", userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByUser indicates an expected call of CountByUser.
+func (mr *MockFavoriteRepositoryMockRecorder) CountByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByUser", reflect.TypeOf((*MockFavoriteRepository)(nil).CountByUser), userID)
+}
+
+// FilterFavorites mocks base method.
+func (m *MockFavoriteRepository) FilterFavorites(userID uuid.UUID, adIDs []uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterFavorites", userID, adIDs)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterFavorites indicates an expected call of FilterFavorites.
+func (mr *MockFavoriteRepositoryMockRecorder) FilterFavorites(userID, adIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterFavorites", reflect.TypeOf((*MockFavoriteRepository)(nil).FilterFavorites), userID, adIDs)
+}
+
+// FindAdIDs mocks base method.
+func (m *MockFavoriteRepository) FindAdIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAdIDs", userID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAdIDs indicates an expected call of FindAdIDs.
+func (mr *MockFavoriteRepositoryMockRecorder) FindAdIDs(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAdIDs", reflect.TypeOf((*MockFavoriteRepository)(nil).FindAdIDs), userID)
+}
+
+// Remove mocks base method.
+func (m *MockFavoriteRepository) Remove(userID, adID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", userID, adID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockFavoriteRepositoryMockRecorder) Remove(userID, adID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockFavoriteRepository)(nil).Remove), userID, adID)
+}
+
+// RemoveAd mocks base method.
+func (m *MockFavoriteRepository) RemoveAd(adID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAd", adID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAd indicates an expected call of RemoveAd.
+func (mr *MockFavoriteRepositoryMockRecorder) RemoveAd(adID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAd", reflect.TypeOf((*MockFavoriteRepository)(nil).RemoveAd), adID)
+}
+
+// RemoveAds mocks base method.
+func (m *MockFavoriteRepository) RemoveAds(adIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAds", adIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAds indicates an expected call of RemoveAds.
+func (mr *MockFavoriteRepositoryMockRecorder) RemoveAds(adIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAds", reflect.TypeOf((*MockFavoriteRepository)(nil).RemoveAds), adIDs)
+}
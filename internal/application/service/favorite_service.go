@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// MaxFavorites keeps the ID filter of the favorites listing small.
+const MaxFavorites = 500
+
+var ErrorTooManyFavorites = errors.New("too many favorites")
+
+//go:generate mockgen -source=favorite_service.go -destination=favorite_repo_mock.go -package=service FavoriteRepository
+type FavoriteRepository interface {
+	Add(userID, adID uuid.UUID) error
+	Remove(userID, adID uuid.UUID) error
+	RemoveAd(adID uuid.UUID) error
+	RemoveAds(adIDs []uuid.UUID) error
+	CountByUser(userID uuid.UUID) (int64, error)
+	FindAdIDs(userID uuid.UUID) ([]uuid.UUID, error)
+	FilterFavorites(userID uuid.UUID, adIDs []uuid.UUID) ([]uuid.UUID, error)
+	CountByAds(adIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+}
+
+type FavoriteService struct {
+	repo FavoriteRepository
+}
+
+func NewFavoriteService(repo FavoriteRepository) *FavoriteService {
+	return &FavoriteService{repo: repo}
+}
+
+// Add favorites the ad for the user; favoriting it again is a no-op.
+func (s *FavoriteService) Add(userID, adID uuid.UUID) error {
+	count, err := s.repo.CountByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if count >= MaxFavorites {
+		favorites, err := s.Favorites(userID, []uuid.UUID{adID})
+		if err != nil {
+			return err
+		}
+		if _, ok := favorites[adID]; !ok {
+			return ErrorTooManyFavorites
+		}
+
+		return nil
+	}
+
+	return s.repo.Add(userID, adID)
+}
+
+func (s *FavoriteService) Remove(userID, adID uuid.UUID) error {
+	return s.repo.Remove(userID, adID)
+}
+
+// RemoveAd drops a deleted ad from the favorites of all users.
+func (s *FavoriteService) RemoveAd(adID uuid.UUID) error {
+	return s.repo.RemoveAd(adID)
+}
+
+func (s *FavoriteService) GetAdIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	return s.repo.FindAdIDs(userID)
+}
+
+// Favorites returns the set of adIDs the user has favorited.
+func (s *FavoriteService) Favorites(userID uuid.UUID, adIDs []uuid.UUID) (map[uuid.UUID]struct{}, error) {
+	favorites := make(map[uuid.UUID]struct{})
+	if len(adIDs) == 0 {
+		return favorites, nil
+	}
+
+	ids, err := s.repo.FilterFavorites(userID, adIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		favorites[id] = struct{}{}
+	}
+
+	return favorites, nil
+}
+
+func (s *FavoriteService) CountByAds(adIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	if len(adIDs) == 0 {
+		return make(map[uuid.UUID]int64), nil
+	}
+
+	return s.repo.CountByAds(adIDs)
+}
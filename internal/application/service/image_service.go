@@ -0,0 +1,213 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+	"log"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+const MediaPathPrefix = "/media/"
+
+var ErrorVariantNotFound = errors.New("image variant not found")
+
+//go:generate mockgen -source=image_service.go -destination=image_repo_mock.go -package=service ImageRepository,BlobStore
+type ImageRepository interface {
+	Save(image *entity.Image) error
+	FindByID(id uuid.UUID) (*entity.Image, error)
+	ExistsForOwner(id, ownerID uuid.UUID) (bool, error)
+	FindByOwner(ownerID uuid.UUID) ([]*entity.Image, error)
+	Delete(id uuid.UUID) error
+}
+
+// BlobStore keeps the bytes of uploaded files under slash-separated keys.
+type BlobStore interface {
+	Put(key, contentType string, data []byte) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+type ImageService struct {
+	repo    ImageRepository
+	blobs   BlobStore
+	baseURL string
+}
+
+// An empty baseURL keeps the image URLs relative to the API host.
+func NewImageService(repo ImageRepository, blobs BlobStore, baseURL string) *ImageService {
+	return &ImageService{
+		repo:    repo,
+		blobs:   blobs,
+		baseURL: baseURL,
+	}
+}
+
+func (s *ImageService) Upload(ownerID uuid.UUID, contentType string, data []byte,
+	config image.Config) (*entity.Image, error) {
+	img := entity.NewImage(ownerID, contentType, int64(len(data)), config.Width, config.Height)
+
+	if err := s.blobs.Put(img.Key, contentType, data); err != nil {
+		return nil, err
+	}
+
+	if err := s.storeVariants(img, data); err != nil {
+		s.deleteBlobs(img)
+		return nil, err
+	}
+
+	if err := s.repo.Save(img); err != nil {
+		s.deleteBlobs(img)
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// Import stores only the variants; the original stays where it is hosted.
+func (s *ImageService) Import(ownerID uuid.UUID, sourceURL, contentType string, data []byte) (*entity.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrorUndecodableImage
+	}
+
+	img := entity.NewExternalImage(ownerID, sourceURL, contentType, int64(len(data)), config.Width, config.Height)
+	if err = s.storeVariants(img, data); err != nil {
+		s.deleteBlobs(img)
+		return nil, err
+	}
+
+	if err = s.repo.Save(img); err != nil {
+		s.deleteBlobs(img)
+		return nil, err
+	}
+
+	return img, nil
+}
+
+func (s *ImageService) storeVariants(img *entity.Image, data []byte) error {
+	variants, err := makeVariants(img.ContentType, data)
+	if err != nil {
+		return err
+	}
+
+	img.Variants = make(map[string]entity.ImageVariant, len(variants))
+	for _, variant := range variants {
+		key := img.VariantKey(variant.name)
+		if err = s.blobs.Put(key, variant.contentType, variant.data); err != nil {
+			return err
+		}
+
+		img.Variants[variant.name] = entity.ImageVariant{
+			Key:         key,
+			ContentType: variant.contentType,
+			Size:        int64(len(variant.data)),
+			Width:       variant.width,
+			Height:      variant.height,
+		}
+	}
+
+	return nil
+}
+
+func (s *ImageService) deleteBlobs(img *entity.Image) {
+	for _, key := range blobKeys(img) {
+		if err := s.blobs.Delete(key); err != nil {
+			log.Printf("ImageService failed to delete orphaned blob %s: %v", key, err)
+		}
+	}
+}
+
+func blobKeys(img *entity.Image) []string {
+	keys := make([]string, 0, len(img.Variants)+1)
+	if img.Key != "" {
+		keys = append(keys, img.Key)
+	}
+	for _, variant := range img.Variants {
+		keys = append(keys, variant.Key)
+	}
+
+	return keys
+}
+
+// A row goes only after its blobs, so that a retry still finds what is left.
+func (s *ImageService) DeleteByOwner(ownerID uuid.UUID) error {
+	images, err := s.repo.FindByOwner(ownerID)
+	if err != nil {
+		return err
+	}
+
+	for _, img := range images {
+		for _, key := range blobKeys(img) {
+			if err = s.blobs.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		if err = s.repo.Delete(img.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ImageService) URL(id uuid.UUID) string {
+	return s.baseURL + MediaPathPrefix + id.String()
+}
+
+func (s *ImageService) VariantURLs(img *entity.Image) map[string]string {
+	if len(img.Variants) == 0 {
+		return nil
+	}
+
+	urls := make(map[string]string, len(img.Variants))
+	for name := range img.Variants {
+		urls[name] = s.URL(img.ID) + "/" + name
+	}
+
+	return urls
+}
+
+func (s *ImageService) IsOwnedBy(id, userID uuid.UUID) (bool, error) {
+	return s.repo.ExistsForOwner(id, userID)
+}
+
+func (s *ImageService) GetByID(id uuid.UUID) (*entity.Image, error) {
+	return s.repo.FindByID(id)
+}
+
+// The caller has to close the returned reader.
+func (s *ImageService) Open(id uuid.UUID, variant string) (*entity.ImageVariant, io.ReadCloser, error) {
+	img, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blob := entity.ImageVariant{
+		Key:         img.Key,
+		ContentType: img.ContentType,
+		Size:        img.Size,
+		Width:       img.Width,
+		Height:      img.Height,
+	}
+	if variant != "" {
+		var ok bool
+		if blob, ok = img.Variants[variant]; !ok {
+			return nil, nil, ErrorVariantNotFound
+		}
+	}
+	if blob.Key == "" {
+		return nil, nil, ErrorVariantNotFound
+	}
+
+	body, err := s.blobs.Get(blob.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &blob, body, nil
+}
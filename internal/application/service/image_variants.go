@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+)
+
+const (
+	contentTypeJPEG    = "image/jpeg"
+	contentTypePNG     = "image/png"
+	variantJPEGQuality = 80
+	// maxDecodedPixels guards against small files decoding into huge bitmaps.
+	maxDecodedPixels = 40_000_000
+)
+
+var (
+	ErrorUndecodableImage = errors.New("image cannot be decoded")
+	ErrorImageTooLarge    = errors.New("image dimensions are too large")
+)
+
+var variantSizes = []struct {
+	name    string
+	maxSide int
+}{
+	{name: entity.VariantThumbnail, maxSide: 240},
+	{name: entity.VariantMedium, maxSide: 800},
+}
+
+type encodedVariant struct {
+	name        string
+	contentType string
+	data        []byte
+	width       int
+	height      int
+}
+
+// Non-JPEG sources are encoded as PNG to keep transparency.
+func makeVariants(contentType string, data []byte) ([]encodedVariant, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrorUndecodableImage
+	}
+	if config.Width*config.Height > maxDecodedPixels {
+		return nil, ErrorImageTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrorUndecodableImage
+	}
+	src := toRGBA(decoded)
+
+	variants := make([]encodedVariant, 0, len(variantSizes))
+	for _, size := range variantSizes {
+		resized := downscale(src, size.maxSide)
+
+		var buf bytes.Buffer
+		variantType := contentTypePNG
+		if contentType == contentTypeJPEG {
+			variantType = contentTypeJPEG
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: variantJPEGQuality})
+		} else {
+			err = png.Encode(&buf, resized)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, encodedVariant{
+			name:        size.name,
+			contentType: variantType,
+			data:        buf.Bytes(),
+			width:       resized.Bounds().Dx(),
+			height:      resized.Bounds().Dy(),
+		})
+	}
+
+	return variants, nil
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok {
+		return rgba
+	}
+
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+
+	return dst
+}
+
+func fitWithin(width, height, maxSide int) (int, int) {
+	longer := max(width, height)
+	if longer <= maxSide {
+		return width, height
+	}
+
+	return max(1, (width*maxSide+longer/2)/longer), max(1, (height*maxSide+longer/2)/longer)
+}
+
+// downscale is a box filter over premultiplied RGBA, so transparent pixels
+// do not bleed their color.
+func downscale(src *image.RGBA, maxSide int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dstW, dstH := fitWithin(srcW, srcH, maxSide)
+	if dstW == srcW && dstH == srcH {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*srcH/dstH, (y+1)*srcH/dstH
+
+		for x := 0; x < dstW; x++ {
+			x0, x1 := x*srcW/dstW, (x+1)*srcW/dstW
+
+			var sum [4]uint64
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(bounds.Min.X+x0, bounds.Min.Y+sy)
+				for sx := x0; sx < x1; sx++ {
+					sum[0] += uint64(src.Pix[i])
+					sum[1] += uint64(src.Pix[i+1])
+					sum[2] += uint64(src.Pix[i+2])
+					sum[3] += uint64(src.Pix[i+3])
+					i += 4
+				}
+			}
+
+			n := uint64((x1 - x0) * (y1 - y0))
+			j := dst.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				dst.Pix[j+c] = uint8((sum[c] + n/2) / n)
+			}
+		}
+	}
+
+	return dst
+}
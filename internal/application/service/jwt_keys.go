@@ -0,0 +1,171 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	DotEnvJWTKeysDir        = "JWT_KEYS_DIR"
+	DotEnvJWTSigningKeyID   = "JWT_SIGNING_KEY_ID"
+	DotEnvJWTRetiredKeys    = "JWT_RETIRED_KEYS"
+	DotEnvJWTKeyGracePeriod = "JWT_KEY_GRACE_PERIOD"
+	DotEnvJWTSecretRetired  = "JWT_SECRET_RETIRED_AT"
+
+	keyFileExtension = ".pem"
+	keyIDHeader      = "kid"
+)
+
+var (
+	errorUnknownSigningKey   = errors.New("JWT_SIGNING_KEY_ID does not match any key in JWT_KEYS_DIR")
+	errorPublicSigningKey    = errors.New("JWT_SIGNING_KEY_ID should refer to a private key")
+	errorRetiredSigningKey   = errors.New("JWT_SIGNING_KEY_ID should not refer to a retired key")
+	errorParsingRetiredKeys  = errors.New("error parsing JWT_RETIRED_KEYS environment variable")
+	errorParsingGracePeriod  = errors.New("error parsing JWT_KEY_GRACE_PERIOD environment variable")
+	errorParsingSecretRetire = errors.New("error parsing JWT_SECRET_RETIRED_AT environment variable")
+	errorMissingSecretRetire = errors.New("JWT_SECRET_RETIRED_AT is required when JWT_SECRET is set along with JWT_KEYS_DIR")
+	errorNoPEMBlock          = errors.New("no PEM block found")
+	errorUnsupportedKeyType  = errors.New("only RSA and Ed25519 keys are supported")
+	errorUnknownKeyID        = errors.New("token is signed with an unknown key")
+	errorKeyRetired          = errors.New("token is signed with a retired key")
+	errorSecretRetired       = errors.New("token is signed with the retired JWT_SECRET")
+	errorMissingKeyID        = errors.New("token has no key ID")
+	errorUnexpectedAlgorithm = errors.New("token algorithm does not match its key")
+)
+
+// Keys without a private part can only verify tokens.
+type signingKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	public    crypto.PublicKey
+	retiredAt *time.Time
+}
+
+func (k *signingKey) usableAt(now time.Time, gracePeriod time.Duration) bool {
+	return k.retiredAt == nil || now.Before(k.retiredAt.Add(gracePeriod))
+}
+
+func (k *signingKey) jwk() entity.JSONWebKey {
+	key := entity.JSONWebKey{
+		Use: "sig",
+		Alg: k.method.Alg(),
+		Kid: k.kid,
+	}
+
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		key.Kty = "RSA"
+		key.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		key.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		key.Kty = "OKP"
+		key.Crv = "Ed25519"
+		key.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+
+	return key
+}
+
+// The file name without the .pem extension becomes the key ID.
+func loadSigningKeys(dir string) (map[string]*signingKey, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+keyFileExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*signingKey, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		kid := strings.TrimSuffix(filepath.Base(path), keyFileExtension)
+		key, err := parseSigningKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", kid, err)
+		}
+		keys[kid] = key
+	}
+
+	return keys, nil
+}
+
+func parseSigningKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errorNoPEMBlock
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, errorUnsupportedKeyType
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{kid: kid}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.method, key.public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, errorUnsupportedKeyType
+	}
+
+	return key, nil
+}
+
+// parseRetiredKeys parses a comma-separated list of kid=RFC3339 pairs.
+func parseRetiredKeys(value string) (map[string]time.Time, error) {
+	retired := make(map[string]time.Time)
+	if value == "" {
+		return retired, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		kid, at, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, errorParsingRetiredKeys
+		}
+
+		retiredAt, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return nil, errorParsingRetiredKeys
+		}
+		retired[kid] = retiredAt
+	}
+
+	return retired, nil
+}
@@ -4,21 +4,25 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/alishashelby/marketplace/internal/domain/entity"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type ctxKey string
 
 const (
 	UserIDKey   ctxKey = "id"
+	RoleKey     ctxKey = "role"
 	UserKey     ctxKey = "user"
 	UsernameKey ctxKey = "username"
 	IssuedAtKey ctxKey = "iat"
 	ExpiryKey   ctxKey = "exp"
+	TokenIDKey  ctxKey = "jti"
 
 	DotEnvJWTSecret     = "JWT_SECRET"
 	DotEnvJWTExpiration = "JWT_TTL"
@@ -31,17 +35,18 @@ var (
 	errorNotPositiveTTL = errors.New("JWT_TTL environment variable should be positive")
 )
 
+// With JWT_KEYS_DIR set, JWT_SECRET is retired at JWT_SECRET_RETIRED_AT and its
+// tokens are accepted for the grace period like those of retired keys.
 type JWTService struct {
-	secret []byte
-	ttl    time.Duration
+	secret          []byte
+	secretRetiredAt time.Time
+	ttl             time.Duration
+	keys            map[string]*signingKey
+	signingKey      *signingKey
+	gracePeriod     time.Duration
 }
 
 func NewJWTService() (*JWTService, error) {
-	secret := []byte(os.Getenv(DotEnvJWTSecret))
-	if secret == nil {
-		return nil, errorLoadingSecret
-	}
-
 	ttl := os.Getenv(DotEnvJWTExpiration)
 	if ttl == "" {
 		return nil, errorLoadingTTL
@@ -55,10 +60,84 @@ func NewJWTService() (*JWTService, error) {
 		return nil, errorNotPositiveTTL
 	}
 
-	return &JWTService{
-		secret: secret,
-		ttl:    time.Duration(ttlInSeconds) * time.Second,
-	}, nil
+	service := &JWTService{
+		secret:      []byte(os.Getenv(DotEnvJWTSecret)),
+		ttl:         time.Duration(ttlInSeconds) * time.Second,
+		gracePeriod: time.Duration(ttlInSeconds) * time.Second,
+	}
+
+	keysDir := os.Getenv(DotEnvJWTKeysDir)
+	if keysDir == "" {
+		if len(service.secret) == 0 {
+			return nil, errorLoadingSecret
+		}
+
+		return service, nil
+	}
+
+	if err = service.loadKeys(keysDir); err != nil {
+		return nil, err
+	}
+
+	if len(service.secret) == 0 {
+		return service, nil
+	}
+
+	// A fixed time keeps restarts from reopening the grace period.
+	retiredAt := os.Getenv(DotEnvJWTSecretRetired)
+	if retiredAt == "" {
+		return nil, errorMissingSecretRetire
+	}
+	if service.secretRetiredAt, err = time.Parse(time.RFC3339, retiredAt); err != nil {
+		return nil, errorParsingSecretRetire
+	}
+
+	return service, nil
+}
+
+func (s *JWTService) loadKeys(dir string) error {
+	keys, err := loadSigningKeys(dir)
+	if err != nil {
+		return err
+	}
+
+	retired, err := parseRetiredKeys(os.Getenv(DotEnvJWTRetiredKeys))
+	if err != nil {
+		return err
+	}
+	for kid, retiredAt := range retired {
+		if key, ok := keys[kid]; ok {
+			key.retiredAt = &retiredAt
+		}
+	}
+
+	if gracePeriod := os.Getenv(DotEnvJWTKeyGracePeriod); gracePeriod != "" {
+		seconds, err := strconv.Atoi(gracePeriod)
+		if err != nil || seconds < 0 {
+			return errorParsingGracePeriod
+		}
+		s.gracePeriod = time.Duration(seconds) * time.Second
+	}
+
+	key, ok := keys[os.Getenv(DotEnvJWTSigningKeyID)]
+	if !ok {
+		return errorUnknownSigningKey
+	}
+	if key.private == nil {
+		return errorPublicSigningKey
+	}
+	if key.retiredAt != nil {
+		return errorRetiredSigningKey
+	}
+
+	s.keys = keys
+	s.signingKey = key
+
+	return nil
+}
+
+func (s *JWTService) TTL() time.Duration {
+	return s.ttl
 }
 
 func (s *JWTService) GenerateToken(user *entity.User) (*string, error) {
@@ -66,13 +145,26 @@ func (s *JWTService) GenerateToken(user *entity.User) (*string, error) {
 		string(UserKey): map[string]interface{}{
 			string(UsernameKey): user.Username,
 			string(UserIDKey):   user.ID.String(),
+			string(RoleKey):     user.Role,
 		},
 		string(IssuedAtKey): time.Now().Unix(),
 		string(ExpiryKey):   time.Now().Add(s.ttl).Unix(),
+		string(TokenIDKey):  uuid.New().String(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString(s.secret)
+	if s.signingKey == nil {
+		signedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+		if err != nil {
+			return nil, err
+		}
+
+		return &signedToken, nil
+	}
+
+	token := jwt.NewWithClaims(s.signingKey.method, claims)
+	token.Header[keyIDHeader] = s.signingKey.kid
+
+	signedToken, err := token.SignedString(s.signingKey.private)
 	if err != nil {
 		return nil, err
 	}
@@ -81,13 +173,7 @@ func (s *JWTService) GenerateToken(user *entity.User) (*string, error) {
 }
 
 func (s *JWTService) ParseToken(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-
-		return s.secret, nil
-	})
+	token, err := jwt.Parse(tokenString, s.verificationKey)
 
 	if err != nil || !token.Valid {
 		return nil, err
@@ -100,3 +186,63 @@ func (s *JWTService) ParseToken(tokenString string) (jwt.MapClaims, error) {
 
 	return payload, nil
 }
+
+func (s *JWTService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if len(s.secret) == 0 {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		if s.keys != nil && !s.secretUsableFor(token) {
+			return nil, errorSecretRetired
+		}
+
+		return s.secret, nil
+	}
+
+	if s.keys == nil {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	kid, ok := token.Header[keyIDHeader].(string)
+	if !ok {
+		return nil, errorMissingKeyID
+	}
+
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, errorUnknownKeyID
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errorUnexpectedAlgorithm
+	}
+	if !key.usableAt(time.Now(), s.gracePeriod) {
+		return nil, errorKeyRetired
+	}
+
+	return key.public, nil
+}
+
+func (s *JWTService) secretUsableFor(token *jwt.Token) bool {
+	issuedAt, err := token.Claims.GetIssuedAt()
+	if err != nil || issuedAt == nil || !issuedAt.Before(s.secretRetiredAt) {
+		return false
+	}
+
+	return time.Now().Before(s.secretRetiredAt.Add(s.gracePeriod))
+}
+
+func (s *JWTService) PublicKeys() entity.JSONWebKeySet {
+	set := entity.JSONWebKeySet{Keys: make([]entity.JSONWebKey, 0, len(s.keys))}
+
+	now := time.Now()
+	for _, key := range s.keys {
+		if key.usableAt(now, s.gracePeriod) {
+			set.Keys = append(set.Keys, key.jwk())
+		}
+	}
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+
+	return set
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+var ErrorNotificationNotFound = errors.New("notification not found")
+
+//go:generate mockgen -source=notification_service.go -destination=notification_repo_mock.go -package=service NotificationRepository
+type NotificationRepository interface {
+	SaveAll(notifications []*entity.Notification) error
+	FindByUser(userID uuid.UUID, unreadOnly bool, limit, offset int) ([]*entity.Notification, error)
+	CountByUser(userID uuid.UUID, unreadOnly bool) (int64, error)
+	MarkRead(id, userID uuid.UUID, readAt time.Time) (bool, error)
+	MarkAllRead(userID uuid.UUID, readAt time.Time) error
+}
+
+type NotificationService struct {
+	repo   NotificationRepository
+	events Publisher
+}
+
+func NewNotificationService(repo NotificationRepository, events Publisher) *NotificationService {
+	return &NotificationService{
+		repo:   repo,
+		events: events,
+	}
+}
+
+func (s *NotificationService) Notify(notifications []*entity.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	if err := s.repo.SaveAll(notifications); err != nil {
+		return err
+	}
+
+	for _, notification := range notifications {
+		s.events.Publish(UserTopic(notification.UserID), &entity.Event{
+			Type:    entity.EventNotificationCreated,
+			Payload: notification,
+		})
+	}
+
+	return nil
+}
+
+func (s *NotificationService) GetNotifications(userID uuid.UUID, unreadOnly bool,
+	page, limit int) ([]*entity.Notification, error) {
+	return s.repo.FindByUser(userID, unreadOnly, limit, (page-1)*limit)
+}
+
+func (s *NotificationService) Count(userID uuid.UUID, unreadOnly bool) (int64, error) {
+	return s.repo.CountByUser(userID, unreadOnly)
+}
+
+func (s *NotificationService) MarkRead(id, userID uuid.UUID) error {
+	found, err := s.repo.MarkRead(id, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrorNotificationNotFound
+	}
+
+	return nil
+}
+
+func (s *NotificationService) MarkAllRead(userID uuid.UUID) error {
+	return s.repo.MarkAllRead(userID, time.Now())
+}
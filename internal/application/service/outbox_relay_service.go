@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+)
+
+const (
+	outboxBatch = 20
+	// outboxLease must be long enough to apply a whole batch.
+	outboxLease       = 5 * time.Minute
+	outboxMaxAttempts = 8
+	outboxMaxDelay    = time.Hour
+)
+
+var ErrorInvalidOutboxEvent = errors.New("invalid outbox event")
+
+//go:generate mockgen -source=outbox_relay_service.go -destination=outbox_repo_mock.go -package=service OutboxRepository
+type OutboxRepository interface {
+	Claim(limit int, lease time.Duration) ([]*entity.OutboxEvent, error)
+	Delete(id int64) error
+	Retry(id int64, delay time.Duration, lastError string) error
+	DeadLetter(id int64, lastError string) error
+}
+
+// Events are delivered at least once, so applying one must be idempotent.
+type OutboxRelayService struct {
+	repo       OutboxRepository
+	ads        AdRepository
+	favorites  FavoriteRepository
+	images     *ImageService
+	retryDelay time.Duration
+}
+
+func NewOutboxRelayService(repo OutboxRepository, ads AdRepository, favorites FavoriteRepository,
+	images *ImageService, retryDelay time.Duration) *OutboxRelayService {
+	return &OutboxRelayService{
+		repo:       repo,
+		ads:        ads,
+		favorites:  favorites,
+		images:     images,
+		retryDelay: retryDelay,
+	}
+}
+
+func (s *OutboxRelayService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for s.RunOnce() == outboxBatch {
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *OutboxRelayService) RunOnce() int {
+	events, err := s.repo.Claim(outboxBatch, outboxLease)
+	if err != nil {
+		log.Print("OutboxRelayService.RunOnce error: ", err)
+		return 0
+	}
+
+	for _, event := range events {
+		s.relay(event)
+	}
+
+	return len(events)
+}
+
+func (s *OutboxRelayService) relay(event *entity.OutboxEvent) {
+	err := s.apply(event)
+	if err == nil {
+		// The event is claimed again after the lease if this fails.
+		if err = s.repo.Delete(event.ID); err != nil {
+			log.Printf("OutboxRelayService failed to complete event %d: %v", event.ID, err)
+		}
+		return
+	}
+
+	if errors.Is(err, ErrorInvalidOutboxEvent) || event.Attempts >= outboxMaxAttempts {
+		log.Printf("OutboxRelayService dead-lettered event %d (%s of user %s) after %d attempts: %v",
+			event.ID, event.Type, event.UserID, event.Attempts, err)
+		err = s.repo.DeadLetter(event.ID, err.Error())
+	} else {
+		err = s.repo.Retry(event.ID, s.delay(event.Attempts), err.Error())
+	}
+	if err != nil {
+		log.Printf("OutboxRelayService failed to record the failure of event %d: %v", event.ID, err)
+	}
+}
+
+func (s *OutboxRelayService) apply(event *entity.OutboxEvent) error {
+	switch event.Type {
+	case entity.OutboxUserRenamed:
+		var renamed entity.UserRenamed
+		if err := json.Unmarshal(event.Payload, &renamed); err != nil || renamed.Username == "" {
+			return fmt.Errorf("%w: %s payload %s", ErrorInvalidOutboxEvent, event.Type, event.Payload)
+		}
+
+		_, err := s.ads.RenameAuthor(event.UserID, renamed.Username)
+		return err
+	case entity.OutboxUserDeleted:
+		// The favorites go first, so that a retry still finds the ads.
+		ids, err := s.ads.FindIDsByAuthor(event.UserID)
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			if err = s.favorites.RemoveAds(ids); err != nil {
+				return err
+			}
+		}
+
+		if _, err = s.ads.DeleteByAuthor(event.UserID); err != nil {
+			return err
+		}
+
+		return s.images.DeleteByOwner(event.UserID)
+	default:
+		return fmt.Errorf("%w: unknown type %s", ErrorInvalidOutboxEvent, event.Type)
+	}
+}
+
+func (s *OutboxRelayService) delay(attempts int) time.Duration {
+	delay := s.retryDelay
+	for range attempts - 1 {
+		delay *= 2
+		if delay >= outboxMaxDelay {
+			return outboxMaxDelay
+		}
+	}
+
+	return delay
+}
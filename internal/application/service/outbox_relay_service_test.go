@@ -0,0 +1,300 @@
+package service
+
+import (
+	"errors"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/blob"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/ad"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/favorite"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/image"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/user"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"testing"
+	"time"
+)
+
+const (
+	claimOutboxQuery      = "UPDATE outbox SET attempts = attempts"
+	deleteOutboxQuery     = "DELETE FROM outbox"
+	deleteFavoritesQuery  = "DELETE FROM favorites"
+	retryOutboxQuery      = "UPDATE outbox SET run_after"
+	deadLetterOutboxQuery = "UPDATE outbox SET dead_at"
+)
+
+// setUpOutboxRelay relays the outbox of a mocked PostgreSQL to a mocked
+// MongoDB, the favorites in the same PostgreSQL and the blobs in a temporary
+// directory, with a retry delay of 10 seconds.
+func setUpOutboxRelay(t *testing.T, mt *mtest.T) (*OutboxRelayService, pgxmock.PgxPoolIface, *blob.LocalStore) {
+	t.Helper()
+
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mock.Close)
+
+	blobs, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	images := NewImageService(image.NewImageRepoMongoDB(mt.DB), blobs, "")
+
+	return NewOutboxRelayService(user.NewOutboxRepoPostgres(mock), ad.NewAdRepoMongoDB(mt.DB),
+		favorite.NewFavoriteRepoPostgres(mock), images, 10*time.Second), mock, blobs
+}
+
+func expectClaim(mock pgxmock.PgxPoolIface, events ...*entity.OutboxEvent) {
+	rows := mock.NewRows([]string{"id", "type", "user_id", "payload", "attempts", "created_at"})
+	for _, e := range events {
+		rows.AddRow(e.ID, e.Type, e.UserID, e.Payload, e.Attempts, e.CreatedAt)
+	}
+
+	mock.ExpectQuery(claimOutboxQuery).
+		WithArgs(outboxBatch, outboxLease.Seconds()).
+		WillReturnRows(rows)
+}
+
+func adIDsResponse(ids ...uuid.UUID) bson.D {
+	docs := make([]bson.D, 0, len(ids))
+	for _, id := range ids {
+		docs = append(docs, bson.D{{Key: "_id", Value: id}})
+	}
+
+	return mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch, docs...)
+}
+
+func imagesResponse(t *testing.T, images ...*entity.Image) bson.D {
+	t.Helper()
+
+	docs := make([]bson.D, 0, len(images))
+	for _, img := range images {
+		data, err := bson.Marshal(img)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var doc bson.D
+		if err = bson.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return mtest.CreateCursorResponse(0, "db.images", mtest.FirstBatch, docs...)
+}
+
+func newOutboxEvent(id int64, eventType, payload string, attempts int) *entity.OutboxEvent {
+	return &entity.OutboxEvent{
+		ID:        id,
+		Type:      eventType,
+		UserID:    uuid.New(),
+		Payload:   []byte(payload),
+		Attempts:  attempts,
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestOutboxRelayService_RunOnce(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Renamed", func(mt *mtest.T) {
+		relay, mock, _ := setUpOutboxRelay(t, mt)
+		event := newOutboxEvent(1, entity.OutboxUserRenamed, `{"username": "renamed"}`, 1)
+
+		expectClaim(mock, event)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 2},
+			bson.E{Key: "nModified", Value: 2},
+		))
+		mock.ExpectExec(deleteOutboxQuery).
+			WithArgs(event.ID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		assert.Equal(t, 1, relay.RunOnce())
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		_, data := update.Lookup("q", "author._id").Binary()
+		assert.Equal(t, event.UserID[:], data)
+		assert.Equal(t, "renamed", update.Lookup("u", "$set", "author.username").StringValue())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	mt.Run("Deleted", func(mt *mtest.T) {
+		relay, mock, blobs := setUpOutboxRelay(t, mt)
+		event := newOutboxEvent(2, entity.OutboxUserDeleted, `{}`, 1)
+		adIDs := []uuid.UUID{uuid.New(), uuid.New()}
+		img := entity.NewImage(event.UserID, "image/png", 3, 1, 1)
+		img.Variants = map[string]entity.ImageVariant{"thumb": {Key: img.VariantKey("thumb")}}
+		keys := []string{img.Key, img.VariantKey("thumb")}
+		for _, key := range keys {
+			if err := blobs.Put(key, img.ContentType, []byte("png")); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		expectClaim(mock, event)
+		mt.AddMockResponses(
+			adIDsResponse(adIDs...),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}),
+			imagesResponse(t, img),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+		mock.ExpectExec(deleteFavoritesQuery).
+			WithArgs(adIDs).
+			WillReturnResult(pgxmock.NewResult("DELETE", 3))
+		mock.ExpectExec(deleteOutboxQuery).
+			WithArgs(event.ID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		assert.Equal(t, 1, relay.RunOnce())
+
+		_, data := mt.GetStartedEvent().Command.Lookup("filter", "author._id").Binary()
+		assert.Equal(t, event.UserID[:], data)
+		deletion := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document()
+		_, data = deletion.Lookup("q", "author._id").Binary()
+		assert.Equal(t, event.UserID[:], data)
+		_, data = mt.GetStartedEvent().Command.Lookup("filter", "owner_id").Binary()
+		assert.Equal(t, event.UserID[:], data)
+		deletion = mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document()
+		_, data = deletion.Lookup("q", "_id").Binary()
+		assert.Equal(t, img.ID[:], data)
+		for _, key := range keys {
+			_, err := blobs.Get(key)
+			assert.ErrorIs(t, err, blob.ErrorBlobNotFound)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	mt.Run("Already applied", func(mt *mtest.T) {
+		relay, mock, _ := setUpOutboxRelay(t, mt)
+		event := newOutboxEvent(3, entity.OutboxUserDeleted, `{}`, 2)
+
+		expectClaim(mock, event)
+		mt.AddMockResponses(
+			adIDsResponse(),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+			imagesResponse(t),
+		)
+		mock.ExpectExec(deleteOutboxQuery).
+			WithArgs(event.ID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		assert.Equal(t, 1, relay.RunOnce())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	mt.Run("Retry", func(mt *mtest.T) {
+		relay, mock, _ := setUpOutboxRelay(t, mt)
+		event := newOutboxEvent(4, entity.OutboxUserRenamed, `{"username": "renamed"}`, 3)
+
+		expectClaim(mock, event)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		mock.ExpectExec(retryOutboxQuery).
+			WithArgs(event.ID, float64(40), ad.ErrorFailedToUpdateAd.Error()).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		assert.Equal(t, 1, relay.RunOnce())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	mt.Run("Retry if favorites remain", func(mt *mtest.T) {
+		relay, mock, _ := setUpOutboxRelay(t, mt)
+		event := newOutboxEvent(8, entity.OutboxUserDeleted, `{}`, 1)
+		adID := uuid.New()
+
+		expectClaim(mock, event)
+		mt.AddMockResponses(adIDsResponse(adID))
+		mock.ExpectExec(deleteFavoritesQuery).
+			WithArgs([]uuid.UUID{adID}).
+			WillReturnError(errors.New("test error"))
+		mock.ExpectExec(retryOutboxQuery).
+			WithArgs(event.ID, float64(10), "test error").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		assert.Equal(t, 1, relay.RunOnce())
+
+		mt.GetStartedEvent()
+		assert.Nil(t, mt.GetStartedEvent(), "ads deleted before their favorites")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	mt.Run("Retry if images remain", func(mt *mtest.T) {
+		relay, mock, blobs := setUpOutboxRelay(t, mt)
+		event := newOutboxEvent(9, entity.OutboxUserDeleted, `{}`, 1)
+		img := entity.NewImage(event.UserID, "image/png", 3, 1, 1)
+		if err := blobs.Put(img.Key, img.ContentType, []byte("png")); err != nil {
+			t.Fatal(err)
+		}
+
+		expectClaim(mock, event)
+		mt.AddMockResponses(
+			adIDsResponse(),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+			imagesResponse(t, img),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{}),
+		)
+		mock.ExpectExec(retryOutboxQuery).
+			WithArgs(event.ID, float64(10), image.ErrorFailedToDeleteImage.Error()).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		assert.Equal(t, 1, relay.RunOnce())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	mt.Run("Dead-lettered after the last attempt", func(mt *mtest.T) {
+		relay, mock, _ := setUpOutboxRelay(t, mt)
+		event := newOutboxEvent(5, entity.OutboxUserDeleted, `{}`, outboxMaxAttempts)
+
+		expectClaim(mock, event)
+		mt.AddMockResponses(adIDsResponse(), mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		mock.ExpectExec(deadLetterOutboxQuery).
+			WithArgs(event.ID, ad.ErrorFailedToDeleteAd.Error()).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		assert.Equal(t, 1, relay.RunOnce())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	mt.Run("Dead-lettered if invalid", func(mt *mtest.T) {
+		relay, mock, _ := setUpOutboxRelay(t, mt)
+		unknown := newOutboxEvent(6, "user.promoted", `{}`, 1)
+		malformed := newOutboxEvent(7, entity.OutboxUserRenamed, `{"name": "renamed"}`, 1)
+
+		expectClaim(mock, unknown, malformed)
+		mock.ExpectExec(deadLetterOutboxQuery).
+			WithArgs(unknown.ID, pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec(deadLetterOutboxQuery).
+			WithArgs(malformed.ID, pgxmock.AnyArg()).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		assert.Equal(t, 2, relay.RunOnce())
+		assert.Nil(t, mt.GetStartedEvent())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	mt.Run("Claim failure", func(mt *mtest.T) {
+		relay, mock, _ := setUpOutboxRelay(t, mt)
+
+		mock.ExpectQuery(claimOutboxQuery).
+			WithArgs(outboxBatch, outboxLease.Seconds()).
+			WillReturnError(errors.New("test error"))
+
+		assert.Zero(t, relay.RunOnce())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOutboxRelayService_Delay(t *testing.T) {
+	relay := NewOutboxRelayService(nil, nil, nil, nil, 10*time.Second)
+
+	assert.Equal(t, 10*time.Second, relay.delay(1))
+	assert.Equal(t, 20*time.Second, relay.delay(2))
+	assert.Equal(t, 640*time.Second, relay.delay(7))
+	assert.Equal(t, outboxMaxDelay, relay.delay(20))
+}
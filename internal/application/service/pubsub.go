@@ -0,0 +1,32 @@
+package service
+
+import (
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	// AdsTopic carries the ads as they get listed.
+	AdsTopic        = "ads"
+	userTopicPrefix = "user:"
+)
+
+// Publish must not block on a slow subscriber.
+type Publisher interface {
+	Publish(topic string, event *entity.Event)
+}
+
+type PubSub interface {
+	Publisher
+	Subscribe(topic string, buffer int) Subscription
+}
+
+type Subscription interface {
+	// Events is closed on Close or once the subscriber falls behind its buffer.
+	Events() <-chan *entity.Event
+	Close()
+}
+
+func UserTopic(userID uuid.UUID) string {
+	return userTopicPrefix + userID.String()
+}
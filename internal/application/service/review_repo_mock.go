@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: review_service.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	reflect "reflect"
+
+	entity "github.com/alishashelby/marketplace/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockReviewRepository is a mock of ReviewRepository interface.
+type MockReviewRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewRepositoryMockRecorder
+}
+
+// MockReviewRepositoryMockRecorder is the mock recorder for MockReviewRepository.
+type MockReviewRepositoryMockRecorder struct {
+	mock *MockReviewRepository
+}
+
+// NewMockReviewRepository creates a new mock instance.
+func NewMockReviewRepository(ctrl *gomock.Controller) *MockReviewRepository {
+	mock := &MockReviewRepository{ctrl: ctrl}
+	mock.recorder = &MockReviewRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewRepository) EXPECT() *MockReviewRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindBySeller mocks base method.
+func (m *MockReviewRepository) FindBySeller(sellerID uuid.UUID, limit, offset int) ([]*entity.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySeller", sellerID, limit, offset)
+	ret0, _ := ret[0].([]*entity.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySeller indicates an expected call of FindBySeller.
+func (mr *MockReviewRepositoryMockRecorder) FindBySeller(sellerID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySeller", reflect.TypeOf((*MockReviewRepository)(nil).FindBySeller), sellerID, limit, offset)
+}
+
+// FindRatings mocks base method.
+func (m *MockReviewRepository) FindRatings(userIDs []uuid.UUID) ([]*entity.Rating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRatings", userIDs)
+	ret0, _ := ret[0].([]*entity.Rating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRatings indicates an expected call of FindRatings.
+func (mr *MockReviewRepositoryMockRecorder) FindRatings(userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRatings", reflect.TypeOf((*MockReviewRepository)(nil).FindRatings), userIDs)
+}
+
+// Save mocks base method.
+func (m *MockReviewRepository) Save(review *entity.Review) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", review)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockReviewRepositoryMockRecorder) Save(review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockReviewRepository)(nil).Save), review)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrorReviewNotAllowed = errors.New("only buyers who have messaged the author about the ad can review it")
+	ErrorSelfReview       = errors.New("you cannot review yourself")
+	ErrorReviewExists     = errors.New("you have already reviewed this ad")
+)
+
+//go:generate mockgen -source=review_service.go -destination=review_repo_mock.go -package=service ReviewRepository
+type ReviewRepository interface {
+	Save(review *entity.Review) (bool, error)
+	FindBySeller(sellerID uuid.UUID, limit, offset int) ([]*entity.Review, error)
+	FindRatings(userIDs []uuid.UUID) ([]*entity.Rating, error)
+}
+
+// The conversation proves the deal, so a removed ad can still be reviewed.
+type ReviewService struct {
+	repo          ReviewRepository
+	conversations *ConversationService
+}
+
+func NewReviewService(repo ReviewRepository, conversations *ConversationService) *ReviewService {
+	return &ReviewService{
+		repo:          repo,
+		conversations: conversations,
+	}
+}
+
+func (s *ReviewService) Create(adID, reviewerID uuid.UUID, rating int, text string) (*entity.Review, error) {
+	conversation, err := s.conversations.GetByAd(adID, reviewerID)
+	if err != nil {
+		if errors.Is(err, ErrorConversationNotFound) {
+			return nil, ErrorReviewNotAllowed
+		}
+
+		return nil, err
+	}
+	if conversation.SellerID == reviewerID {
+		return nil, ErrorSelfReview
+	}
+
+	review := entity.NewReview(conversation, rating, text)
+	created, err := s.repo.Save(review)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrorReviewExists
+	}
+
+	return review, nil
+}
+
+func (s *ReviewService) GetBySeller(sellerID uuid.UUID, page, limit int) ([]*entity.Review, error) {
+	return s.repo.FindBySeller(sellerID, limit, (page-1)*limit)
+}
+
+func (s *ReviewService) Rating(userID uuid.UUID) (*entity.Rating, error) {
+	ratings, err := s.Ratings([]uuid.UUID{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return ratings[userID], nil
+}
+
+func (s *ReviewService) Ratings(userIDs []uuid.UUID) (map[uuid.UUID]*entity.Rating, error) {
+	ratings := make(map[uuid.UUID]*entity.Rating, len(userIDs))
+	if len(userIDs) == 0 {
+		return ratings, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(userIDs))
+	for _, id := range userIDs {
+		if _, ok := ratings[id]; !ok {
+			ratings[id] = &entity.Rating{UserID: id}
+			ids = append(ids, id)
+		}
+	}
+
+	found, err := s.repo.FindRatings(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, rating := range found {
+		ratings[rating.UserID] = rating
+	}
+
+	return ratings, nil
+}
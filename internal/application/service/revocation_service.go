@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// negativeCacheTTL bounds how late a revocation by another instance is noticed.
+const negativeCacheTTL = 30 * time.Second
+
+//go:generate mockgen -source=revocation_service.go -destination=revocation_repo_mock.go -package=service RevocationRepository
+type RevocationRepository interface {
+	Revoke(jti, userID uuid.UUID, expiresAt time.Time) error
+	RevokeAllForUser(userID uuid.UUID, revokedBefore, expiresAt time.Time) error
+	IsRevoked(jti, userID uuid.UUID, issuedAt time.Time) (bool, error)
+	DeleteExpired(now time.Time) error
+}
+
+type revocationEntry struct {
+	revoked   bool
+	expiresAt time.Time
+}
+
+type userRevocation struct {
+	revokedBefore time.Time
+	expiresAt     time.Time
+}
+
+type RevocationService struct {
+	repo     RevocationRepository
+	tokenTTL time.Duration
+
+	mu     sync.RWMutex
+	tokens map[uuid.UUID]revocationEntry
+	users  map[uuid.UUID]userRevocation
+}
+
+func NewRevocationService(repo RevocationRepository, tokenTTL time.Duration) *RevocationService {
+	return &RevocationService{
+		repo:     repo,
+		tokenTTL: tokenTTL,
+		tokens:   make(map[uuid.UUID]revocationEntry),
+		users:    make(map[uuid.UUID]userRevocation),
+	}
+}
+
+func (s *RevocationService) Revoke(jti, userID uuid.UUID, expiresAt time.Time) error {
+	if err := s.repo.Revoke(jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.tokens[jti] = revocationEntry{revoked: true, expiresAt: expiresAt}
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *RevocationService) RevokeAll(userID uuid.UUID) error {
+	revokedBefore := time.Now().Truncate(time.Second)
+	expiresAt := revokedBefore.Add(s.tokenTTL + time.Second)
+
+	if err := s.repo.RevokeAllForUser(userID, revokedBefore, expiresAt); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.users[userID] = userRevocation{revokedBefore: revokedBefore, expiresAt: expiresAt}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Token timestamps have a one second resolution, so a token issued in the same
+// second as a "log out all" counts as revoked.
+func (s *RevocationService) IsRevoked(jti, userID uuid.UUID, issuedAt, expiresAt time.Time) (bool, error) {
+	now := time.Now()
+
+	s.mu.RLock()
+	user, userCached := s.users[userID]
+	entry, tokenCached := s.tokens[jti]
+	s.mu.RUnlock()
+
+	if userCached && !issuedAt.After(user.revokedBefore) {
+		return true, nil
+	}
+	if tokenCached && now.Before(entry.expiresAt) {
+		return entry.revoked, nil
+	}
+
+	revoked, err := s.repo.IsRevoked(jti, userID, issuedAt)
+	if err != nil {
+		return false, err
+	}
+
+	entry = revocationEntry{revoked: revoked, expiresAt: expiresAt}
+	if !revoked && now.Add(negativeCacheTTL).Before(expiresAt) {
+		entry.expiresAt = now.Add(negativeCacheTTL)
+	}
+
+	s.mu.Lock()
+	s.tokens[jti] = entry
+	s.mu.Unlock()
+
+	return revoked, nil
+}
+
+func (s *RevocationService) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.cleanup(now)
+		}
+	}
+}
+
+func (s *RevocationService) cleanup(now time.Time) {
+	s.mu.Lock()
+	for jti, entry := range s.tokens {
+		if !now.Before(entry.expiresAt) {
+			delete(s.tokens, jti)
+		}
+	}
+	for userID, user := range s.users {
+		if !now.Before(user.expiresAt) {
+			delete(s.users, userID)
+		}
+	}
+	s.mu.Unlock()
+
+	if err := s.repo.DeleteExpired(now); err != nil {
+		log.Print("RevocationService.cleanup error: ", err)
+	}
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: saved_search_service.go
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/alishashelby/marketplace/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockSavedSearchRepository is a mock of SavedSearchRepository interface.
+type MockSavedSearchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSavedSearchRepositoryMockRecorder
+}
+
+// MockSavedSearchRepositoryMockRecorder is the mock recorder for MockSavedSearchRepository.
+type MockSavedSearchRepositoryMockRecorder struct {
+	mock *MockSavedSearchRepository
+}
+
+// NewMockSavedSearchRepository creates a new mock instance.
+func NewMockSavedSearchRepository(ctrl *gomock.Controller) *MockSavedSearchRepository {
+	mock := &MockSavedSearchRepository{ctrl: ctrl}
+	mock.recorder = &MockSavedSearchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSavedSearchRepository) EXPECT() *MockSavedSearchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountByUser mocks base method.
+func (m *MockSavedSearchRepository) CountByUser(userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByUser", userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByUser indicates an expected call of CountByUser.
+func (mr *MockSavedSearchRepositoryMockRecorder) CountByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByUser", reflect.TypeOf((*MockSavedSearchRepository)(nil).CountByUser), userID)
+}
+
+// Delete mocks base method.
+func (m *MockSavedSearchRepository) Delete(id, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSavedSearchRepositoryMockRecorder) Delete(id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSavedSearchRepository)(nil).Delete), id, userID)
+}
+
+// FindByUser mocks base method.
+func (m *MockSavedSearchRepository) FindByUser(userID uuid.UUID) ([]*entity.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", userID)
+	ret0, _ := ret[0].([]*entity.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockSavedSearchRepositoryMockRecorder) FindByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockSavedSearchRepository)(nil).FindByUser), userID)
+}
+
+// FindDue mocks base method.
+func (m *MockSavedSearchRepository) FindDue(before time.Time, afterID uuid.UUID, limit int) ([]*entity.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDue", before, afterID, limit)
+	ret0, _ := ret[0].([]*entity.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDue indicates an expected call of FindDue.
+func (mr *MockSavedSearchRepositoryMockRecorder) FindDue(before, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDue", reflect.TypeOf((*MockSavedSearchRepository)(nil).FindDue), before, afterID, limit)
+}
+
+// Save mocks base method.
+func (m *MockSavedSearchRepository) Save(search *entity.SavedSearch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", search)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockSavedSearchRepositoryMockRecorder) Save(search interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockSavedSearchRepository)(nil).Save), search)
+}
+
+// SetLastRunAt mocks base method.
+func (m *MockSavedSearchRepository) SetLastRunAt(id uuid.UUID, lastRunAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLastRunAt", id, lastRunAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLastRunAt indicates an expected call of SetLastRunAt.
+func (mr *MockSavedSearchRepositoryMockRecorder) SetLastRunAt(id, lastRunAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLastRunAt", reflect.TypeOf((*MockSavedSearchRepository)(nil).SetLastRunAt), id, lastRunAt)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxSavedSearches bounds the queries a user adds to every run.
+	MaxSavedSearches = 20
+	savedSearchBatch = 100
+	// savedSearchLag leaves ads listed during a run to the next one.
+	savedSearchLag   = time.Minute
+	maxSearchMatches = entity.LimitMaxValue
+)
+
+var (
+	ErrorTooManySavedSearches = errors.New("too many saved searches")
+	ErrorSavedSearchNotFound  = errors.New("saved search not found")
+)
+
+//go:generate mockgen -source=saved_search_service.go -destination=saved_search_repo_mock.go -package=service SavedSearchRepository
+type SavedSearchRepository interface {
+	Save(search *entity.SavedSearch) error
+	FindByUser(userID uuid.UUID) ([]*entity.SavedSearch, error)
+	CountByUser(userID uuid.UUID) (int64, error)
+	Delete(id, userID uuid.UUID) (bool, error)
+	FindDue(before time.Time, afterID uuid.UUID, limit int) ([]*entity.SavedSearch, error)
+	SetLastRunAt(id uuid.UUID, lastRunAt time.Time) error
+}
+
+type SavedSearchService struct {
+	repo          SavedSearchRepository
+	ads           AdRepository
+	categories    *CategoryService
+	notifications *NotificationService
+}
+
+func NewSavedSearchService(repo SavedSearchRepository, ads AdRepository, categories *CategoryService,
+	notifications *NotificationService) *SavedSearchService {
+	return &SavedSearchService{
+		repo:          repo,
+		ads:           ads,
+		categories:    categories,
+		notifications: notifications,
+	}
+}
+
+func (s *SavedSearchService) Create(search *entity.SavedSearch) error {
+	count, err := s.repo.CountByUser(search.UserID)
+	if err != nil {
+		return err
+	}
+	if count >= MaxSavedSearches {
+		return ErrorTooManySavedSearches
+	}
+
+	return s.repo.Save(search)
+}
+
+func (s *SavedSearchService) GetByUser(userID uuid.UUID) ([]*entity.SavedSearch, error) {
+	return s.repo.FindByUser(userID)
+}
+
+func (s *SavedSearchService) Delete(id, userID uuid.UUID) error {
+	deleted, err := s.repo.Delete(id, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrorSavedSearchNotFound
+	}
+
+	return nil
+}
+
+// Run matches the saved searches every interval until ctx is done.
+func (s *SavedSearchService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce matches the ads listed since the last run of each search. A failed
+// search keeps its window for the next run.
+func (s *SavedSearchService) RunOnce(now time.Time) {
+	until := now.Add(-savedSearchLag)
+	afterID := uuid.Nil
+
+	for {
+		searches, err := s.repo.FindDue(until, afterID, savedSearchBatch)
+		if err != nil {
+			log.Print("SavedSearchService.RunOnce error: ", err)
+			return
+		}
+
+		for _, search := range searches {
+			if err = s.match(search, until); err != nil {
+				log.Printf("SavedSearchService failed to match saved search %s: %v", search.ID, err)
+			}
+		}
+
+		if len(searches) < savedSearchBatch {
+			return
+		}
+		afterID = searches[len(searches)-1].ID
+	}
+}
+
+func (s *SavedSearchService) match(search *entity.SavedSearch, until time.Time) error {
+	ops := search.Options()
+	ops.Limit = maxSearchMatches
+	ops.SortBy = entity.SortByCreatedAt
+	ops.OrderBy = entity.OrderByDesc
+	ops.ListedAfter = search.LastRunAt
+	ops.ListedBefore = until
+
+	if ops.CategoryID > 0 {
+		ids, err := s.categories.GetSubtreeIDs(ops.CategoryID)
+		if err != nil {
+			return err
+		}
+		ops.CategoryIDs = ids
+	}
+
+	ads, err := s.ads.FindMatches(ops)
+	if err != nil {
+		return err
+	}
+
+	notifications := make([]*entity.Notification, 0, len(ads))
+	for _, ad := range ads {
+		if ad.Author.ID == search.UserID {
+			continue
+		}
+		notifications = append(notifications, entity.NewSearchMatchNotification(search, ad))
+	}
+
+	if err = s.notifications.Notify(notifications); err != nil {
+		return err
+	}
+
+	return s.repo.SetLastRunAt(search.ID, until)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	DotEnvJWTRefreshExpiration = "JWT_REFRESH_TTL"
+
+	defaultRefreshTTL    = 30 * 24 * time.Hour
+	refreshTokenByteSize = 32
+)
+
+var (
+	ErrorInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrorRefreshTokenExpired = errors.New("refresh token has expired")
+	ErrorRefreshTokenReused  = errors.New("refresh token has already been used")
+
+	errorParsingRefreshTTL     = errors.New("error parsing JWT_REFRESH_TTL environment variable")
+	errorNotPositiveRefreshTTL = errors.New("JWT_REFRESH_TTL environment variable should be positive")
+)
+
+//go:generate mockgen -source=token_service.go -destination=token_repo_mock.go -package=service RefreshTokenRepository
+type RefreshTokenRepository interface {
+	Save(token *entity.RefreshToken) error
+	GetByHash(hash string) (*entity.RefreshToken, error)
+	Rotate(oldID uuid.UUID, next *entity.RefreshToken) (bool, error)
+	RevokeFamily(familyID uuid.UUID) error
+	RevokeAllForUser(userID uuid.UUID) error
+}
+
+type TokenService struct {
+	jwtService  *JWTService
+	repo        RefreshTokenRepository
+	users       UserRepository
+	revocations *RevocationService
+	ttl         time.Duration
+}
+
+// JWT_REFRESH_TTL is in seconds and defaults to 30 days.
+func NewTokenService(jwtService *JWTService, repo RefreshTokenRepository,
+	users UserRepository, revocations *RevocationService) (*TokenService, error) {
+	ttl := defaultRefreshTTL
+
+	if value := os.Getenv(DotEnvJWTRefreshExpiration); value != "" {
+		ttlInSeconds, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errorParsingRefreshTTL
+		}
+		if ttlInSeconds <= 0 {
+			return nil, errorNotPositiveRefreshTTL
+		}
+		ttl = time.Duration(ttlInSeconds) * time.Second
+	}
+
+	return &TokenService{
+		jwtService:  jwtService,
+		repo:        repo,
+		users:       users,
+		revocations: revocations,
+		ttl:         ttl,
+	}, nil
+}
+
+func (s *TokenService) Issue(user *entity.User) (*entity.TokenPair, error) {
+	raw, token, err := s.newRefreshToken(user.ID, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.Save(token); err != nil {
+		return nil, err
+	}
+
+	return s.pair(user, raw)
+}
+
+// A reused refresh token has leaked, so its whole family is revoked.
+func (s *TokenService) Refresh(raw string) (*entity.TokenPair, error) {
+	current, err := s.repo.GetByHash(hashRefreshToken(raw))
+	if err != nil {
+		return nil, ErrorInvalidRefreshToken
+	}
+
+	if current.RevokedAt != nil {
+		return nil, s.revokeFamily(current.FamilyID)
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return nil, ErrorRefreshTokenExpired
+	}
+
+	user, err := s.users.GetByID(current.UserID)
+	if err != nil {
+		return nil, ErrorInvalidRefreshToken
+	}
+	if user.IsBanned() {
+		return nil, ErrorUserBanned
+	}
+
+	nextRaw, next, err := s.newRefreshToken(current.UserID, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := s.repo.Rotate(current.ID, next)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, s.revokeFamily(current.FamilyID)
+	}
+
+	return s.pair(user, nextRaw)
+}
+
+// The refresh token family is only revoked if it belongs to the same user.
+func (s *TokenService) Logout(userID, jti uuid.UUID, expiresAt time.Time, refreshToken string) error {
+	if err := s.revocations.Revoke(jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	current, err := s.repo.GetByHash(hashRefreshToken(refreshToken))
+	if err != nil || current.UserID != userID {
+		return nil
+	}
+
+	return s.repo.RevokeFamily(current.FamilyID)
+}
+
+func (s *TokenService) LogoutAll(userID uuid.UUID) error {
+	if err := s.revocations.RevokeAll(userID); err != nil {
+		return err
+	}
+
+	return s.repo.RevokeAllForUser(userID)
+}
+
+func (s *TokenService) revokeFamily(familyID uuid.UUID) error {
+	if err := s.repo.RevokeFamily(familyID); err != nil {
+		return err
+	}
+
+	return ErrorRefreshTokenReused
+}
+
+func (s *TokenService) newRefreshToken(userID, familyID uuid.UUID) (string, *entity.RefreshToken, error) {
+	buf := make([]byte, refreshTokenByteSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, err
+	}
+	raw := base64.RawURLEncoding.EncodeToString(buf)
+
+	now := time.Now()
+	return raw, &entity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashRefreshToken(raw),
+		ExpiresAt: now.Add(s.ttl),
+		CreatedAt: now,
+	}, nil
+}
+
+func (s *TokenService) pair(user *entity.User, refreshToken string) (*entity.TokenPair, error) {
+	accessToken, err := s.jwtService.GenerateToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.TokenPair{
+		AccessToken:  *accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func hashRefreshToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+
+	return hex.EncodeToString(sum[:])
+}
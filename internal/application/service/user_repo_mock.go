@@ -6,6 +6,7 @@ package service
 
 import (
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/alishashelby/marketplace/internal/domain/entity"
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +36,51 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockUserRepository) Count() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockUserRepositoryMockRecorder) Count() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockUserRepository)(nil).Count))
+}
+
+// Delete mocks base method.
+func (m *MockUserRepository) Delete(id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepository)(nil).Delete), id)
+}
+
+// FindAll mocks base method.
+func (m *MockUserRepository) FindAll(limit, offset int) ([]*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", limit, offset)
+	ret0, _ := ret[0].([]*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockUserRepositoryMockRecorder) FindAll(limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockUserRepository)(nil).FindAll), limit, offset)
+}
+
 // GetByID mocks base method.
 func (m *MockUserRepository) GetByID(id uuid.UUID) (*entity.User, error) {
 	m.ctrl.T.Helper()
@@ -78,3 +124,32 @@ func (mr *MockUserRepositoryMockRecorder) Save(user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUserRepository)(nil).Save), user)
 }
+
+// SetBannedAt mocks base method.
+func (m *MockUserRepository) SetBannedAt(id uuid.UUID, bannedAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBannedAt", id, bannedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBannedAt indicates an expected call of SetBannedAt.
+func (mr *MockUserRepositoryMockRecorder) SetBannedAt(id, bannedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBannedAt", reflect.TypeOf((*MockUserRepository)(nil).SetBannedAt), id, bannedAt)
+}
+
+// UpdateUsername mocks base method.
+func (m *MockUserRepository) UpdateUsername(id uuid.UUID, username string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUsername", id, username)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUsername indicates an expected call of UpdateUsername.
+func (mr *MockUserRepositoryMockRecorder) UpdateUsername(id, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUsername", reflect.TypeOf((*MockUserRepository)(nil).UpdateUsername), id, username)
+}
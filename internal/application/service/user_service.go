@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"time"
 
 	"github.com/alishashelby/marketplace/internal/domain/entity"
 	"github.com/google/uuid"
@@ -13,6 +14,10 @@ var (
 	ErrorUserWithUsernameDoesNotExists = errors.New("user with this username does not exist")
 	ErrorUserWithIDDoesNotExists       = errors.New("user with this id does not exist")
 	ErrorInvalidPassword               = errors.New("invalid password")
+	ErrorUserBanned                    = errors.New("user is banned")
+	ErrorCannotBanYourself             = errors.New("you cannot ban yourself")
+	ErrorCannotDeleteYourself          = errors.New("you cannot delete yourself")
+	ErrorUsernameTaken                 = errors.New("username is already taken")
 )
 
 //go:generate mockgen -source=user_service.go -destination=user_repo_mock.go -package=service UserRepository
@@ -20,21 +25,26 @@ type UserRepository interface {
 	Save(user *entity.User) error
 	GetByUsername(username string) (*entity.User, error)
 	GetByID(id uuid.UUID) (*entity.User, error)
+	FindAll(limit, offset int) ([]*entity.User, error)
+	Count() (int64, error)
+	SetBannedAt(id uuid.UUID, bannedAt *time.Time) error
+	UpdateUsername(id uuid.UUID, username string) (bool, error)
+	Delete(id uuid.UUID) (bool, error)
 }
 
 type UserService struct {
-	repo       UserRepository
-	jwtService *JWTService
+	repo         UserRepository
+	tokenService *TokenService
 }
 
-func NewUserService(repo UserRepository, service *JWTService) *UserService {
+func NewUserService(repo UserRepository, service *TokenService) *UserService {
 	return &UserService{
-		repo:       repo,
-		jwtService: service,
+		repo:         repo,
+		tokenService: service,
 	}
 }
 
-func (s *UserService) Register(username, password string) (*string, error) {
+func (s *UserService) Register(username, password string) (*entity.TokenPair, error) {
 	if user, err := s.repo.GetByUsername(username); err == nil && user != nil {
 		return nil, ErrorUserExists
 	}
@@ -45,19 +55,21 @@ func (s *UserService) Register(username, password string) (*string, error) {
 	}
 
 	user := &entity.User{
-		ID:       uuid.New(),
-		Username: username,
-		Password: string(hash),
+		ID:        uuid.New(),
+		Username:  username,
+		Password:  string(hash),
+		Role:      entity.RoleUser,
+		CreatedAt: time.Now(),
 	}
 
 	if err := s.repo.Save(user); err != nil {
 		return nil, err
 	}
 
-	return s.jwtService.GenerateToken(user)
+	return s.tokenService.Issue(user)
 }
 
-func (s *UserService) Login(username, password string) (*string, error) {
+func (s *UserService) Login(username, password string) (*entity.TokenPair, error) {
 	user, err := s.repo.GetByUsername(username)
 	if err != nil {
 		return nil, ErrorUserWithUsernameDoesNotExists
@@ -67,7 +79,23 @@ func (s *UserService) Login(username, password string) (*string, error) {
 		return nil, ErrorInvalidPassword
 	}
 
-	return s.jwtService.GenerateToken(user)
+	if user.IsBanned() {
+		return nil, ErrorUserBanned
+	}
+
+	return s.tokenService.Issue(user)
+}
+
+func (s *UserService) RefreshToken(refreshToken string) (*entity.TokenPair, error) {
+	return s.tokenService.Refresh(refreshToken)
+}
+
+func (s *UserService) Logout(userID, jti uuid.UUID, expiresAt time.Time, refreshToken string) error {
+	return s.tokenService.Logout(userID, jti, expiresAt, refreshToken)
+}
+
+func (s *UserService) LogoutAll(userID uuid.UUID) error {
+	return s.tokenService.LogoutAll(userID)
 }
 
 func (s *UserService) GetByID(id uuid.UUID) (*entity.User, error) {
@@ -78,3 +106,83 @@ func (s *UserService) GetByID(id uuid.UUID) (*entity.User, error) {
 
 	return user, nil
 }
+
+func (s *UserService) GetByUsername(username string) (*entity.User, error) {
+	user, err := s.repo.GetByUsername(username)
+	if err != nil {
+		return nil, ErrorUserWithUsernameDoesNotExists
+	}
+
+	return user, nil
+}
+
+func (s *UserService) ChangeUsername(id uuid.UUID, username string) (*entity.User, error) {
+	user, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Username == username {
+		return user, nil
+	}
+
+	updated, err := s.repo.UpdateUsername(id, username)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrorUsernameTaken
+	}
+	user.Username = username
+
+	return user, nil
+}
+
+func (s *UserService) GetUsers(page, limit int) ([]*entity.User, error) {
+	return s.repo.FindAll(limit, (page-1)*limit)
+}
+
+func (s *UserService) CountUsers() (int64, error) {
+	return s.repo.Count()
+}
+
+func (s *UserService) Ban(id, adminID uuid.UUID) error {
+	if id == adminID {
+		return ErrorCannotBanYourself
+	}
+
+	now := time.Now()
+	if err := s.repo.SetBannedAt(id, &now); err != nil {
+		return ErrorUserWithIDDoesNotExists
+	}
+
+	return s.tokenService.LogoutAll(id)
+}
+
+func (s *UserService) Unban(id uuid.UUID) error {
+	if err := s.repo.SetBannedAt(id, nil); err != nil {
+		return ErrorUserWithIDDoesNotExists
+	}
+
+	return nil
+}
+
+func (s *UserService) Delete(id, adminID uuid.UUID) error {
+	if id == adminID {
+		return ErrorCannotDeleteYourself
+	}
+
+	// This instance rejects the tokens right away, others once the user is gone.
+	if err := s.tokenService.LogoutAll(id); err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.Delete(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrorUserWithIDDoesNotExists
+	}
+
+	return nil
+}
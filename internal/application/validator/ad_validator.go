@@ -1,27 +1,27 @@
 package validator
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
-	"image"
-	_ "image/gif"
-	_ "image/jpeg"
-	_ "image/png"
-	"io"
 	"log"
+	"net"
 	"net/http"
-	"time"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/alishashelby/marketplace/internal/application/dto"
 	"github.com/alishashelby/marketplace/internal/domain/entity"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 )
 
 const (
 	maxImageSize   = 5 * 1024 * 1024
-	timeout        = 15 * time.Second
 	ImageURLField  = "image_url"
+	ImageIDField   = "image_id"
+	CategoryField  = "CategoryID"
+	CoverField     = "Cover"
 	ContentTypeKey = "Content-Type"
 )
 
@@ -40,81 +40,221 @@ const (
 	ReportErrorInComparePrices        = "min_price cannot be greater than max_price"
 	ReportInvalidSortBy               = "invalid sort_by parameter"
 	ReportInvalidOrderBy              = "invalid order_by parameter"
+	ReportRelevanceWithoutQuery       = "sort_by=relevance requires the q parameter"
+	ReportUnknownCategory             = "category %d does not exist"
+	ReportCursorWithPage              = "cursor cannot be combined with page"
+	ReportCursorWithRelevance         = "cursor cannot be combined with sort_by=relevance"
+	ReportCursorMismatch              = "cursor does not match sort_by and order_by"
+	ReportNeedImage                   = "either image_url or image_id is required"
+	ReportUnknownImage                = "image %s does not exist or was uploaded by another user"
+	ReportCoverOutOfRange             = "cover must be the index of one of %d images"
+	SearchFiltersField                = "filters"
 )
 
+const (
+	imageIDKeyFmt   = "images[%d].image_id"
+	structNamespace = "AdDTO."
+
+	imagesStructField   = "Images"
+	imageURLStructField = "ImageURL"
+)
+
+type CategoryChecker interface {
+	Exists(id int64) (bool, error)
+}
+
+type ImageChecker interface {
+	IsOwnedBy(id, userID uuid.UUID) (bool, error)
+}
+
+// ImageFetcher downloads external images. It must refuse URLs that point
+// into the internal network, since they are supplied by users.
+type ImageFetcher interface {
+	Get(url string) (*http.Response, error)
+}
+
 type AdValidator struct {
-	validator    *validator.Validate
-	contentTypes map[string]struct{}
+	validator  *validator.Validate
+	categories CategoryChecker
+	images     ImageChecker
+	fetcher    ImageFetcher
+	imageCheck *ImageValidator
 }
 
-func NewAdValidator() *AdValidator {
+func NewAdValidator(categories CategoryChecker, images ImageChecker, fetcher ImageFetcher) *AdValidator {
 	return &AdValidator{
-		validator: validator.New(),
-		contentTypes: map[string]struct{}{
-			"image/jpeg": {},
-			"image/png":  {},
-			"image/gif":  {},
-		},
+		validator:  validator.New(),
+		categories: categories,
+		images:     images,
+		fetcher:    fetcher,
+		imageCheck: NewImageValidator(),
 	}
 }
 
-func (v *AdValidator) validateImgFormat(imgURL string, errors map[string]string) {
-	client := &http.Client{Timeout: timeout}
-	resp, err := client.Get(imgURL)
+// ImageError is temporary for network failures and 5xx responses.
+type ImageError struct {
+	Report string
+	Retry  bool
+}
+
+func (e *ImageError) Error() string {
+	return e.Report
+}
+
+func (e *ImageError) Temporary() bool {
+	return e.Retry
+}
+
+// FetchImage downloads an external image and checks it like an uploaded one.
+func (v *AdValidator) FetchImage(imgURL string) (string, []byte, error) {
+	resp, err := v.fetcher.Get(imgURL)
 	if err != nil {
-		errors[ImageURLField] = fmt.Sprintf(ReportErrorFetchingImgFromURL, err)
-		return
+		return "", nil, &ImageError{
+			Report: fmt.Sprintf(ReportErrorFetchingImgFromURL, err),
+			Retry:  isTemporary(err),
+		}
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
 	if resp.StatusCode != http.StatusOK {
-		errors[ImageURLField] = fmt.Sprintf(ReportErrorUnavailableImg, resp.StatusCode)
-		return
+		return "", nil, &ImageError{
+			Report: fmt.Sprintf(ReportErrorUnavailableImg, resp.StatusCode),
+			Retry:  resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests,
+		}
 	}
 
 	contentType := resp.Header.Get(ContentTypeKey)
-	if _, ok := v.contentTypes[contentType]; !ok {
-		errors[ImageURLField] = fmt.Sprintf(ReportErrorUnsupportedContentType, contentType)
-		log.Printf("unsupported content type: %s", contentType)
-		return
+	data, _, report := v.imageCheck.Check(contentType, resp.Body)
+	if report != "" {
+		return "", nil, &ImageError{Report: report}
 	}
 
-	var buf bytes.Buffer
-	n, err := io.Copy(&buf, io.LimitReader(resp.Body, maxImageSize+1))
-	if err != nil {
-		errors[ImageURLField] = fmt.Sprintf(ReportErrorReadingImg, err)
-		return
+	return contentType, data, nil
+}
+
+func isTemporary(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && urlErr.Timeout() {
+		return true
 	}
 
-	log.Printf("image size: %d", n)
-	if n > maxImageSize {
-		errors[ImageURLField] = fmt.Sprintf(ReportErrorLargeImg, n, maxImageSize)
-		return
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return !dnsErr.IsNotFound
 	}
 
-	_, _, err = image.DecodeConfig(bytes.NewReader(buf.Bytes()))
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+func (v *AdValidator) validateUploadedImg(imageID, userID uuid.UUID) string {
+	owned, err := v.images.IsOwnedBy(imageID, userID)
 	if err != nil {
-		errors[ImageURLField] = fmt.Sprintf(ReportInvalidFormatImg, err)
-		return
+		log.Printf("failed to check image %s: %v", imageID, err)
+		return fmt.Sprintf(ReportFailedToValidate, ImageIDField)
+	}
+
+	if !owned {
+		return fmt.Sprintf(ReportUnknownImage, imageID)
+	}
+
+	return ""
+}
+
+// Validate leaves external images to the background verification.
+func (v *AdValidator) Validate(dto dto.AdDTO, userID uuid.UUID) map[string]string {
+	errs := v.validateFields(dto)
+
+	images := dto.ImageList()
+	switch {
+	case len(images) == 0:
+		if _, failed := errs[imageURLStructField]; !failed {
+			errs[imagesStructField] = ReportNeedImage
+		}
+	case dto.Cover >= len(images):
+		errs[CoverField] = fmt.Sprintf(ReportCoverOutOfRange, len(images))
+	}
+
+	for i, image := range images {
+		if image.ImageID == nil {
+			continue
+		}
+
+		key := ImageIDField
+		if len(dto.Images) > 0 {
+			key = fmt.Sprintf(imageIDKeyFmt, i)
+		}
+		if report := v.validateUploadedImg(*image.ImageID, userID); report != "" {
+			errs[key] = report
+		}
+	}
+	if len(errs) > 0 {
+		return errs
 	}
+
+	return nil
 }
 
-func (v *AdValidator) Validate(dto dto.AdDTO) map[string]string {
+func (v *AdValidator) validateFields(dto dto.AdDTO) map[string]string {
 	errs := make(map[string]string)
 
 	if err := v.validator.Struct(dto); err != nil {
 		var validationErrors validator.ValidationErrors
 		if errors.As(err, &validationErrors) {
 			for _, valErr := range validationErrors {
+				// Nested image fields are reported as Images[i].URL.
+				field := strings.TrimPrefix(valErr.Namespace(), structNamespace)
+
 				switch valErr.Tag() {
 				case "min":
-					errs[valErr.Field()] = fmt.Sprintf(ReportNeedMoreCharacters, valErr.Field(), valErr.Param())
+					errs[field] = fmt.Sprintf(ReportNeedMoreCharacters, field, valErr.Param())
 				case "max":
-					errs[valErr.Field()] = fmt.Sprintf(ReportTooManyCharacters, valErr.Field(), valErr.Param())
+					errs[field] = fmt.Sprintf(ReportTooManyCharacters, field, valErr.Param())
 				case "url":
-					errs[valErr.Field()] = fmt.Sprintf(ReportNeedURL, valErr.Field())
+					errs[field] = fmt.Sprintf(ReportNeedURL, field)
+				case "required_without", "required_without_all":
+					errs[field] = ReportNeedImage
 				case "gt":
-					errs[valErr.Field()] = fmt.Sprintf(ReportNeedPositive, valErr.Field())
+					errs[field] = fmt.Sprintf(ReportNeedPositive, field)
+				default:
+					errs[field] = fmt.Sprintf(ReportFailedToValidate, field)
+				}
+			}
+		}
+	}
+
+	if _, failed := errs[CategoryField]; !failed {
+		v.validateCategory(dto.CategoryID, CategoryField, errs)
+	}
+
+	return errs
+}
+
+func (v *AdValidator) validateCategory(categoryID int64, key string, errs map[string]string) {
+	exists, err := v.categories.Exists(categoryID)
+	if err != nil {
+		log.Printf("failed to check category %d: %v", categoryID, err)
+		errs[key] = fmt.Sprintf(ReportFailedToValidate, key)
+		return
+	}
+
+	if !exists {
+		errs[key] = fmt.Sprintf(ReportUnknownCategory, categoryID)
+	}
+}
+
+func (v *AdValidator) ValidateSearch(dto dto.SavedSearchDTO) map[string]string {
+	errs := make(map[string]string)
+
+	if err := v.validator.Struct(dto); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			for _, valErr := range validationErrors {
+				switch valErr.Tag() {
+				case "required":
+					errs[valErr.Field()] = fmt.Sprintf(ReportIsRequired, valErr.Field())
+				case "max":
+					errs[valErr.Field()] = fmt.Sprintf(ReportTooManyCharacters, valErr.Field(), valErr.Param())
 				default:
 					errs[valErr.Field()] = fmt.Sprintf(ReportFailedToValidate, valErr.Field())
 				}
@@ -122,7 +262,12 @@ func (v *AdValidator) Validate(dto dto.AdDTO) map[string]string {
 		}
 	}
 
-	v.validateImgFormat(dto.ImageURL, errs)
+	if err := v.ValidateOptions(dto.Options()); err != nil {
+		errs[SearchFiltersField] = err.Error()
+	} else if dto.Category > 0 {
+		v.validateCategory(dto.Category, entity.ParamCategory, errs)
+	}
+
 	if len(errs) > 0 {
 		return errs
 	}
@@ -131,6 +276,12 @@ func (v *AdValidator) Validate(dto dto.AdDTO) map[string]string {
 }
 
 func (v *AdValidator) ValidateOptions(ops *entity.Options) error {
+	if ops.Cursor != nil {
+		if err := v.validateCursor(ops); err != nil {
+			return err
+		}
+	}
+
 	if ops.Page < 1 {
 		return fmt.Errorf(ReportNeedPositive, entity.ParamPage)
 	}
@@ -152,9 +303,21 @@ func (v *AdValidator) ValidateOptions(ops *entity.Options) error {
 		return errors.New(ReportErrorInComparePrices)
 	}
 
-	if ops.SortBy != "" && ops.SortBy != entity.SortByCreatedAt && ops.SortBy != entity.SortByPrice {
+	if ops.CategoryID < 0 {
+		return fmt.Errorf(ReportNeedPositive, entity.ParamCategory)
+	}
+
+	if ops.SortBy != "" && ops.SortBy != entity.SortByCreatedAt &&
+		ops.SortBy != entity.SortByPrice && ops.SortBy != entity.SortByRelevance {
 		return errors.New(ReportInvalidSortBy)
 	}
+	if ops.SortBy == entity.SortByRelevance && ops.Query == "" {
+		return errors.New(ReportRelevanceWithoutQuery)
+	}
+
+	if len([]rune(ops.Query)) > entity.QueryMaxLength {
+		return fmt.Errorf(ReportTooManyCharacters, entity.ParamQuery, strconv.Itoa(entity.QueryMaxLength))
+	}
 
 	if ops.OrderBy != 0 && ops.OrderBy != entity.OrderByAsc && ops.OrderBy != entity.OrderByDesc {
 		return errors.New(ReportInvalidOrderBy)
@@ -162,3 +325,20 @@ func (v *AdValidator) ValidateOptions(ops *entity.Options) error {
 
 	return nil
 }
+
+func (v *AdValidator) validateCursor(ops *entity.Options) error {
+	if ops.Page > 1 {
+		return errors.New(ReportCursorWithPage)
+	}
+	ops.Page = 1
+
+	if ops.SortBy == entity.SortByRelevance {
+		return errors.New(ReportCursorWithRelevance)
+	}
+
+	if ops.Cursor.SortBy != ops.SortBy || ops.Cursor.OrderBy != ops.OrderBy {
+		return errors.New(ReportCursorMismatch)
+	}
+
+	return nil
+}
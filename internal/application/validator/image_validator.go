@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+)
+
+const ImageField = "image"
+
+type ImageValidator struct {
+	contentTypes map[string]struct{}
+}
+
+func NewImageValidator() *ImageValidator {
+	return &ImageValidator{
+		contentTypes: map[string]struct{}{
+			"image/jpeg": {},
+			"image/png":  {},
+			"image/gif":  {},
+		},
+	}
+}
+
+// On failure the returned string describes the problem.
+func (v *ImageValidator) Check(contentType string, body io.Reader) ([]byte, image.Config, string) {
+	if _, ok := v.contentTypes[contentType]; !ok {
+		log.Printf("unsupported content type: %s", contentType)
+		return nil, image.Config{}, fmt.Sprintf(ReportErrorUnsupportedContentType, contentType)
+	}
+
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(body, maxImageSize+1))
+	if err != nil {
+		return nil, image.Config{}, fmt.Sprintf(ReportErrorReadingImg, err)
+	}
+
+	log.Printf("image size: %d", n)
+	if n > maxImageSize {
+		return nil, image.Config{}, fmt.Sprintf(ReportErrorLargeImg, n, maxImageSize)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return nil, image.Config{}, fmt.Sprintf(ReportInvalidFormatImg, err)
+	}
+
+	return buf.Bytes(), config, ""
+}
+
+func (v *ImageValidator) Validate(contentType string, body io.Reader) ([]byte, image.Config, map[string]string) {
+	data, config, report := v.Check(contentType, body)
+	if report != "" {
+		return nil, image.Config{}, map[string]string{ImageField: report}
+	}
+
+	return data, config, nil
+}
@@ -0,0 +1,40 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/go-playground/validator/v10"
+)
+
+type MessageValidator struct {
+	validator *validator.Validate
+}
+
+func NewMessageValidator() *MessageValidator {
+	return &MessageValidator{validator: validator.New()}
+}
+
+func (mv *MessageValidator) Validate(dto dto.MessageDTO) map[string]string {
+	if err := mv.validator.Struct(dto); err != nil {
+		errs := make(map[string]string)
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			for _, valErr := range validationErrors {
+				switch valErr.Tag() {
+				case "required":
+					errs[valErr.Field()] = fmt.Sprintf(ReportIsRequired, valErr.Field())
+				case "max":
+					errs[valErr.Field()] = fmt.Sprintf(ReportTooManyCharacters, valErr.Field(), valErr.Param())
+				default:
+					errs[valErr.Field()] = fmt.Sprintf(ReportFailedToValidate, valErr.Field())
+				}
+			}
+		}
+
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	ratingField       = "Rating"
+	ReportRatingRange = "%s must be between %d and %d"
+)
+
+type ReviewValidator struct {
+	validator *validator.Validate
+}
+
+func NewReviewValidator() *ReviewValidator {
+	return &ReviewValidator{validator: validator.New()}
+}
+
+func (rv *ReviewValidator) Validate(dto dto.ReviewDTO) map[string]string {
+	if err := rv.validator.Struct(dto); err != nil {
+		errs := make(map[string]string)
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			for _, valErr := range validationErrors {
+				switch {
+				case valErr.Field() == ratingField:
+					// A missing rating is zero, which is out of range as well.
+					errs[valErr.Field()] = fmt.Sprintf(ReportRatingRange, valErr.Field(), entity.RatingMin,
+						entity.RatingMax)
+				case valErr.Tag() == "required":
+					errs[valErr.Field()] = fmt.Sprintf(ReportIsRequired, valErr.Field())
+				case valErr.Tag() == "max":
+					errs[valErr.Field()] = fmt.Sprintf(ReportTooManyCharacters, valErr.Field(), valErr.Param())
+				default:
+					errs[valErr.Field()] = fmt.Sprintf(ReportFailedToValidate, valErr.Field())
+				}
+			}
+		}
+
+		return errs
+	}
+
+	return nil
+}
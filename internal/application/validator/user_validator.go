@@ -11,6 +11,7 @@ import (
 const (
 	ReportMustBeOnlyLetters      = "%s must contain only letters"
 	ReportMustBeOneSpecialSymbol = "%s must contain at least one special character from %s"
+	ReportIsRequired             = "%s is required"
 )
 
 type UserValidator struct {
@@ -50,3 +51,53 @@ func (uv *UserValidator) Validate(dto dto.UserDTO) map[string]string {
 
 	return nil
 }
+
+func (uv *UserValidator) ValidateUsername(dto dto.UsernameDTO) map[string]string {
+	if err := uv.validator.Struct(dto); err != nil {
+		errs := make(map[string]string)
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			for _, valErr := range validationErrors {
+				switch valErr.Tag() {
+				case "required":
+					errs[valErr.Field()] = fmt.Sprintf(ReportIsRequired, valErr.Field())
+				case "min":
+					errs[valErr.Field()] = fmt.Sprintf(
+						ReportNeedMoreCharacters, valErr.Field(), valErr.Param())
+				case "max":
+					errs[valErr.Field()] = fmt.Sprintf(
+						ReportTooManyCharacters, valErr.Field(), valErr.Param())
+				case "alpha":
+					errs[valErr.Field()] = fmt.Sprintf(ReportMustBeOnlyLetters, valErr.Field())
+				default:
+					errs[valErr.Field()] = fmt.Sprintf(ReportFailedToValidate, valErr.Field())
+				}
+			}
+		}
+
+		return errs
+	}
+
+	return nil
+}
+
+func (uv *UserValidator) ValidateRefreshToken(dto dto.RefreshTokenDTO) map[string]string {
+	if err := uv.validator.Struct(dto); err != nil {
+		errs := make(map[string]string)
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			for _, valErr := range validationErrors {
+				switch valErr.Tag() {
+				case "required":
+					errs[valErr.Field()] = fmt.Sprintf(ReportIsRequired, valErr.Field())
+				default:
+					errs[valErr.Field()] = fmt.Sprintf(ReportFailedToValidate, valErr.Field())
+				}
+			}
+		}
+
+		return errs
+	}
+
+	return nil
+}
@@ -6,14 +6,46 @@ import (
 	"github.com/google/uuid"
 )
 
+// AdStatus tells whether an ad is listed.
+type AdStatus string
+
+const (
+	AdStatusDraft    AdStatus = "draft"
+	AdStatusPending  AdStatus = "pending"
+	AdStatusActive   AdStatus = "active"
+	AdStatusRejected AdStatus = "rejected"
+	AdStatusExpired  AdStatus = "expired"
+)
+
+var AdStatuses = []AdStatus{AdStatusDraft, AdStatusPending, AdStatusActive, AdStatusRejected, AdStatusExpired}
+
 type Ad struct {
-	ID        uuid.UUID `json:"id" bson:"_id"`
-	Title     string    `json:"title" bson:"title"`
-	Text      string    `json:"text" bson:"text"`
-	ImageURL  string    `json:"image_url" bson:"image_url"`
-	Price     float64   `json:"price" bson:"price"`
-	Author    *Author   `json:"author" bson:"author"`
+	ID              uuid.UUID `json:"id" bson:"_id"`
+	Title           string    `json:"title" bson:"title"`
+	Text            string    `json:"text" bson:"text"`
+	Images          []AdImage `json:"images" bson:"images"`
+	Cover           int       `json:"cover" bson:"cover"`
+	Price           float64   `json:"price" bson:"price"`
+	CategoryID      int64     `json:"category_id" bson:"category_id"`
+	Author          *Author   `json:"author" bson:"author"`
+	Status          AdStatus  `json:"status" bson:"status"`
+	RejectionReason string    `json:"rejection_reason,omitempty" bson:"rejection_reason,omitempty"`
+	Views           int64     `json:"-" bson:"views,omitempty"`
+	// Revision is bumped by every edit to detect outdated verifications.
+	Revision int64 `json:"-" bson:"revision"`
+	// ListedAt is when the ad became active for the first time.
+	ListedAt  time.Time `json:"-" bson:"listed_at,omitempty"`
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// AdImage is an external image or one uploaded to our blob storage. Pending
+// marks external images that have not been verified yet.
+type AdImage struct {
+	URL        string            `json:"url" bson:"url"`
+	ImageID    *uuid.UUID        `json:"image_id,omitempty" bson:"image_id,omitempty"`
+	Thumbnails map[string]string `json:"thumbnails,omitempty" bson:"thumbnails,omitempty"`
+	Pending    bool              `json:"-" bson:"pending,omitempty"`
 }
 
 type Author struct {
@@ -21,19 +53,99 @@ type Author struct {
 	ID       uuid.UUID `json:"id" bson:"_id"`
 }
 
-func NewAd(title, text, imageURL string, price float64, user *User) *Ad {
-	return &Ad{
-		ID:       uuid.New(),
-		Title:    title,
-		Text:     text,
-		ImageURL: imageURL,
-		Price:    price,
+func NewAd(title, text string, images []AdImage, cover int, price float64, categoryID int64, user *User) *Ad {
+	ad := &Ad{
+		ID:         uuid.New(),
+		Title:      title,
+		Text:       text,
+		Images:     images,
+		Cover:      cover,
+		Price:      price,
+		CategoryID: categoryID,
 		Author: &Author{
 			Username: user.Username,
 			ID:       user.ID,
 		},
 		CreatedAt: time.Now(),
 	}
+	ad.resetStatus()
+
+	return ad
+}
+
+func (a *Ad) CoverImage() *AdImage {
+	if a.Cover < 0 || a.Cover >= len(a.Images) {
+		return nil
+	}
+
+	return &a.Images[a.Cover]
+}
+
+func (a *Ad) Edit(title, text string, images []AdImage, cover int, price float64, categoryID int64) {
+	a.Title = title
+	a.Text = text
+	a.Images = images
+	a.Cover = cover
+	a.Price = price
+	a.CategoryID = categoryID
+	a.Revision++
+	a.UpdatedAt = time.Now()
+	a.resetStatus()
+}
+
+// NeedsVerification reports whether some images of the ad are still pending.
+func (a *Ad) NeedsVerification() bool {
+	for _, image := range a.Images {
+		if image.Pending {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Activate lists the ad once none of its images is pending.
+func (a *Ad) Activate() {
+	if !a.NeedsVerification() {
+		a.Status = AdStatusActive
+		a.RejectionReason = ""
+		if a.ListedAt.IsZero() {
+			a.ListedAt = time.Now()
+		}
+	}
+}
+
+func (a *Ad) MakeDraft() {
+	a.Status = AdStatusDraft
+	a.RejectionReason = ""
+	a.ListedAt = time.Time{}
+}
+
+// Publish reports false unless the ad is a draft or has expired.
+func (a *Ad) Publish() bool {
+	if a.Status != AdStatusDraft && a.Status != AdStatusExpired {
+		return false
+	}
+
+	a.Status = AdStatusPending
+	a.Activate()
+
+	return true
+}
+
+func (a *Ad) Reject(reason string) {
+	a.Status = AdStatusRejected
+	a.RejectionReason = reason
+}
+
+func (a *Ad) resetStatus() {
+	if a.Status == AdStatusDraft || a.Status == AdStatusExpired {
+		return
+	}
+
+	a.Status = AdStatusPending
+	a.RejectionReason = ""
+	a.Activate()
 }
 
 const (
@@ -41,8 +153,10 @@ const (
 	OrderByDesc       = -1
 	SortByCreatedAt   = "created_at"
 	SortByPrice       = "price"
+	SortByRelevance   = "relevance"
 	LimitMaxValue     = 40
 	LimitDefaultValue = 10
+	QueryMaxLength    = 100
 )
 
 const (
@@ -52,6 +166,9 @@ const (
 	ParamOrderBy  = "order_by"
 	ParamMinPrice = "min_price"
 	ParamMaxPrice = "max_price"
+	ParamQuery    = "q"
+	ParamCategory = "category"
+	ParamCursor   = "cursor"
 )
 
 type Options struct {
@@ -61,4 +178,30 @@ type Options struct {
 	OrderBy  int
 	MinPrice float64
 	MaxPrice float64
+	Query    string
+	// CategoryIDs adds the descendants of CategoryID and is what gets filtered on.
+	CategoryID  int64
+	CategoryIDs []int64
+	// Cursor switches the listing to keyset pagination, Page is ignored then.
+	Cursor *Cursor
+	// IDs restricts the listing to the given ads unless it is nil.
+	IDs      []uuid.UUID
+	AuthorID uuid.UUID
+	// Only active ads are matched if Statuses is empty.
+	Statuses []AdStatus
+	// ListedAfter and ListedBefore bound the time the ads were listed,
+	// exclusively and inclusively, unless they are zero.
+	ListedAfter  time.Time
+	ListedBefore time.Time
+}
+
+func (o *Options) MatchesPrice(price float64) bool {
+	if o.MinPrice > 0 && price < o.MinPrice {
+		return false
+	}
+	if o.MaxPrice > 0 && price > o.MaxPrice {
+		return false
+	}
+
+	return true
 }
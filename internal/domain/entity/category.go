@@ -0,0 +1,8 @@
+package entity
+
+type Category struct {
+	ID       int64       `json:"id"`
+	ParentID *int64      `json:"parent_id,omitempty"`
+	Name     string      `json:"name"`
+	Children []*Category `json:"children,omitempty"`
+}
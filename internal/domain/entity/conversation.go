@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Conversation is the only thread of a buyer about an ad.
+type Conversation struct {
+	ID             uuid.UUID
+	AdID           uuid.UUID
+	AdTitle        string
+	BuyerID        uuid.UUID
+	BuyerUsername  string
+	SellerID       uuid.UUID
+	SellerUsername string
+	CreatedAt      time.Time
+	LastMessageAt  time.Time
+	// Unread is only set in listings.
+	Unread int64
+}
+
+type Message struct {
+	ID             uuid.UUID
+	ConversationID uuid.UUID
+	SenderID       uuid.UUID
+	Text           string
+	CreatedAt      time.Time
+	ReadAt         *time.Time
+}
+
+func NewConversation(ad *Ad, buyer *User) *Conversation {
+	now := time.Now()
+
+	return &Conversation{
+		ID:             uuid.New(),
+		AdID:           ad.ID,
+		AdTitle:        ad.Title,
+		BuyerID:        buyer.ID,
+		BuyerUsername:  buyer.Username,
+		SellerID:       ad.Author.ID,
+		SellerUsername: ad.Author.Username,
+		CreatedAt:      now,
+		LastMessageAt:  now,
+	}
+}
+
+func NewMessage(conversationID, senderID uuid.UUID, text string) *Message {
+	return &Message{
+		ID:             uuid.New(),
+		ConversationID: conversationID,
+		SenderID:       senderID,
+		Text:           text,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// HasParticipant reports whether the user is the buyer or the seller.
+func (c *Conversation) HasParticipant(userID uuid.UUID) bool {
+	return c.BuyerID == userID || c.SellerID == userID
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrorInvalidCursor = errors.New("invalid cursor")
+)
+
+// Clients only ever see a Cursor in its opaque encoded form.
+type Cursor struct {
+	SortBy    string    `json:"s"`
+	OrderBy   int       `json:"o"`
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"c"`
+	Price     float64   `json:"p"`
+}
+
+func NewCursor(ad *Ad, sortBy string, orderBy int) *Cursor {
+	return &Cursor{
+		SortBy:    sortBy,
+		OrderBy:   orderBy,
+		ID:        ad.ID,
+		CreatedAt: ad.CreatedAt,
+		Price:     ad.Price,
+	}
+}
+
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrorInvalidCursor
+	}
+
+	var c Cursor
+	if err = json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrorInvalidCursor
+	}
+
+	if c.ID == uuid.Nil || (c.SortBy != SortByCreatedAt && c.SortBy != SortByPrice) ||
+		(c.OrderBy != OrderByAsc && c.OrderBy != OrderByDesc) {
+		return nil, ErrorInvalidCursor
+	}
+
+	return &c, nil
+}
+
+func (c *Cursor) Encode() string {
+	raw, _ := json.Marshal(c) //nolint:errcheck
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func (c *Cursor) Value() any {
+	if c.SortBy == SortByPrice {
+		return c.Price
+	}
+
+	return c.CreatedAt
+}
@@ -0,0 +1,14 @@
+package entity
+
+const (
+	EventAdCreated           = "ad.created"
+	EventMessageCreated      = "message.created"
+	EventNotificationCreated = "notification.created"
+	EventAdStatusChanged     = "ad.status_changed"
+)
+
+// Payload is shared between subscribers and must not be modified.
+type Event struct {
+	Type    string
+	Payload any
+}
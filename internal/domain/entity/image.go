@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	VariantThumbnail = "thumbnail"
+	VariantMedium    = "medium"
+)
+
+// Images imported from an external SourceURL have no Key, only variants.
+type Image struct {
+	ID          uuid.UUID               `json:"id" bson:"_id"`
+	OwnerID     uuid.UUID               `json:"owner_id" bson:"owner_id"`
+	Key         string                  `json:"key" bson:"key"`
+	SourceURL   string                  `json:"source_url,omitempty" bson:"source_url,omitempty"`
+	ContentType string                  `json:"content_type" bson:"content_type"`
+	Size        int64                   `json:"size" bson:"size"`
+	Width       int                     `json:"width" bson:"width"`
+	Height      int                     `json:"height" bson:"height"`
+	Variants    map[string]ImageVariant `json:"variants,omitempty" bson:"variants,omitempty"`
+	CreatedAt   time.Time               `json:"created_at" bson:"created_at"`
+}
+
+type ImageVariant struct {
+	Key         string `json:"key" bson:"key"`
+	ContentType string `json:"content_type" bson:"content_type"`
+	Size        int64  `json:"size" bson:"size"`
+	Width       int    `json:"width" bson:"width"`
+	Height      int    `json:"height" bson:"height"`
+}
+
+func NewImage(ownerID uuid.UUID, contentType string, size int64, width, height int) *Image {
+	id := uuid.New()
+
+	return &Image{
+		ID:          id,
+		OwnerID:     ownerID,
+		Key:         "images/" + id.String(),
+		ContentType: contentType,
+		Size:        size,
+		Width:       width,
+		Height:      height,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func NewExternalImage(ownerID uuid.UUID, sourceURL, contentType string, size int64, width, height int) *Image {
+	image := NewImage(ownerID, contentType, size, width, height)
+	image.Key = ""
+	image.SourceURL = sourceURL
+
+	return image
+}
+
+func (i *Image) VariantKey(name string) string {
+	return "variants/" + i.ID.String() + "/" + name
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const NotificationSearchMatch = "search_match"
+
+// Notification is an inbox entry; Type tells which optional fields are set.
+type Notification struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	Type          string
+	SavedSearchID *uuid.UUID
+	SearchName    string
+	AdID          *uuid.UUID
+	AdTitle       string
+	CreatedAt     time.Time
+	ReadAt        *time.Time
+}
+
+func NewSearchMatchNotification(search *SavedSearch, ad *Ad) *Notification {
+	searchID, adID := search.ID, ad.ID
+
+	return &Notification{
+		ID:            uuid.New(),
+		UserID:        search.UserID,
+		Type:          NotificationSearchMatch,
+		SavedSearchID: &searchID,
+		SearchName:    search.Name,
+		AdID:          &adID,
+		AdTitle:       ad.Title,
+		CreatedAt:     time.Now(),
+	}
+}
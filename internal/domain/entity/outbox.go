@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OutboxUserRenamed = "user.renamed"
+	OutboxUserDeleted = "user.deleted"
+)
+
+type OutboxEvent struct {
+	ID     int64
+	Type   string
+	UserID uuid.UUID
+	// Payload is a UserRenamed for OutboxUserRenamed and {} otherwise.
+	Payload   []byte
+	Attempts  int
+	CreatedAt time.Time
+}
+
+type UserRenamed struct {
+	Username string `json:"username"`
+}
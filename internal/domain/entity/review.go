@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RatingMin = 1
+	RatingMax = 5
+)
+
+// AdTitle is copied, since the ad may have been removed since.
+type Review struct {
+	ID               uuid.UUID
+	AdID             uuid.UUID
+	AdTitle          string
+	ReviewerID       uuid.UUID
+	ReviewerUsername string
+	SellerID         uuid.UUID
+	Rating           int
+	Text             string
+	CreatedAt        time.Time
+}
+
+type Rating struct {
+	UserID uuid.UUID
+	Count  int64
+	Sum    int64
+}
+
+func NewReview(conversation *Conversation, rating int, text string) *Review {
+	return &Review{
+		ID:               uuid.New(),
+		AdID:             conversation.AdID,
+		AdTitle:          conversation.AdTitle,
+		ReviewerID:       conversation.BuyerID,
+		ReviewerUsername: conversation.BuyerUsername,
+		SellerID:         conversation.SellerID,
+		Rating:           rating,
+		Text:             text,
+		CreatedAt:        time.Now(),
+	}
+}
+
+func (r *Rating) Average() float64 {
+	if r.Count == 0 {
+		return 0
+	}
+
+	return float64(r.Sum) / float64(r.Count)
+}
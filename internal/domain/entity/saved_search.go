@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedSearch has been matched against the ads listed up to LastRunAt.
+type SavedSearch struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	Query      string
+	MinPrice   float64
+	MaxPrice   float64
+	CategoryID int64
+	SortBy     string
+	OrderBy    int
+	LastRunAt  time.Time
+	CreatedAt  time.Time
+}
+
+func NewSavedSearch(userID uuid.UUID, name string, ops *Options) *SavedSearch {
+	now := time.Now()
+
+	return &SavedSearch{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Name:       name,
+		Query:      ops.Query,
+		MinPrice:   ops.MinPrice,
+		MaxPrice:   ops.MaxPrice,
+		CategoryID: ops.CategoryID,
+		SortBy:     ops.SortBy,
+		OrderBy:    ops.OrderBy,
+		LastRunAt:  now,
+		CreatedAt:  now,
+	}
+}
+
+// Options returns the filters of the search as the first page of a listing.
+func (s *SavedSearch) Options() *Options {
+	return &Options{
+		Page:       1,
+		Limit:      LimitDefaultValue,
+		SortBy:     s.SortBy,
+		OrderBy:    s.OrderBy,
+		MinPrice:   s.MinPrice,
+		MaxPrice:   s.MaxPrice,
+		Query:      s.Query,
+		CategoryID: s.CategoryID,
+	}
+}
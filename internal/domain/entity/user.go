@@ -1,9 +1,26 @@
 package entity
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
 
 type User struct {
-	ID       uuid.UUID `json:"id"`
-	Username string    `json:"username"`
-	Password string    `json:"password"`
+	ID        uuid.UUID  `json:"id"`
+	Username  string     `json:"username"`
+	Password  string     `json:"password"`
+	Role      string     `json:"role"`
+	BannedAt  *time.Time `json:"banned_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (u *User) IsBanned() bool {
+	return u.BannedAt != nil
 }
@@ -0,0 +1,52 @@
+package blob
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(root, "media"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("image bytes")
+	assert.NoError(t, store.Put("images/cat.png", "image/png", data))
+
+	onDisk, err := os.ReadFile(filepath.Join(root, "media", "images", "cat.png"))
+	assert.NoError(t, err)
+	assert.Equal(t, data, onDisk)
+
+	body, err := store.Get("images/cat.png")
+	if assert.NoError(t, err) {
+		got, err := io.ReadAll(body)
+		assert.NoError(t, err)
+		assert.Equal(t, data, got)
+		assert.NoError(t, body.Close())
+	}
+
+	assert.NoError(t, store.Delete("images/cat.png"))
+	assert.NoError(t, store.Delete("images/cat.png"))
+
+	_, err = store.Get("images/cat.png")
+	assert.ErrorIs(t, err, ErrorBlobNotFound)
+}
+
+func TestLocalStore_InvalidKeys(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "images/../../secret", "images//cat.png", `images\cat.png`} {
+		assert.ErrorIs(t, store.Put(key, "image/png", []byte("data")), ErrorInvalidKey, key)
+
+		_, err = store.Get(key)
+		assert.ErrorIs(t, err, ErrorInvalidKey, key)
+	}
+}
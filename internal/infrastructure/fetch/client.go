@@ -0,0 +1,249 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout      = 15 * time.Second
+	defaultMaxRedirects = 3
+	dialTimeout         = 5 * time.Second
+)
+
+var (
+	ErrorForbiddenScheme  = errors.New("url scheme is not allowed")
+	ErrorForbiddenPort    = errors.New("url port is not allowed")
+	ErrorForbiddenAddress = errors.New("url resolves to a non-public address")
+	ErrorNoAddress        = errors.New("url host has no addresses")
+	ErrorTooManyRedirects = errors.New("too many redirects")
+	ErrorInvalidAllowlist = errors.New("invalid allowlist entry")
+)
+
+var defaultPorts = []int{80, 443}
+
+// blockedPrefixes are the special-purpose ranges netip.Addr has no predicate for.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/23"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+type Config struct {
+	// Allowlist hosts and CIDR ranges are trusted on any port.
+	Allowlist []string
+	// Ports defaults to 80 and 443.
+	Ports        []int
+	MaxRedirects int
+	Timeout      time.Duration
+}
+
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// Client dials the address it has checked, so DNS cannot change in between.
+type Client struct {
+	client       *http.Client
+	resolver     Resolver
+	dialer       *net.Dialer
+	hosts        map[string]struct{}
+	networks     []netip.Prefix
+	ports        map[int]struct{}
+	maxRedirects int
+}
+
+func NewClient(config Config) (*Client, error) {
+	return newClient(config, net.DefaultResolver)
+}
+
+func newClient(config Config, resolver Resolver) (*Client, error) {
+	c := &Client{
+		resolver:     resolver,
+		dialer:       &net.Dialer{Timeout: dialTimeout},
+		hosts:        make(map[string]struct{}),
+		ports:        make(map[int]struct{}),
+		maxRedirects: config.MaxRedirects,
+	}
+
+	for _, entry := range config.Allowlist {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrorInvalidAllowlist, entry)
+			}
+			c.networks = append(c.networks, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			c.networks = append(c.networks, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		c.hosts[entry] = struct{}{}
+	}
+
+	ports := config.Ports
+	if len(ports) == 0 {
+		ports = defaultPorts
+	}
+	for _, port := range ports {
+		c.ports[port] = struct{}{}
+	}
+
+	if c.maxRedirects <= 0 {
+		c.maxRedirects = defaultMaxRedirects
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect to the target on our behalf, bypassing the checks.
+	transport.Proxy = nil
+	transport.DialContext = c.dialContext
+
+	c.client = &http.Client{
+		Timeout:       timeout,
+		Transport:     transport,
+		CheckRedirect: c.checkRedirect,
+	}
+
+	return c, nil
+}
+
+func (c *Client) Get(rawURL string) (*http.Response, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkScheme(target); err != nil {
+		return nil, err
+	}
+
+	return c.client.Get(target.String())
+}
+
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > c.maxRedirects {
+		return ErrorTooManyRedirects
+	}
+
+	return checkScheme(req.URL)
+}
+
+func checkScheme(target *url.URL) error {
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return fmt.Errorf("%w: %q", ErrorForbiddenScheme, target.Scheme)
+	}
+
+	return nil
+}
+
+// Any blocked address rejects the host, so round-robin DNS cannot smuggle one in.
+func (c *Client) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrorForbiddenPort, portStr)
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	addrs, err := c.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	_, trusted := c.hosts[host]
+	if !trusted {
+		trusted = true
+		for _, addr := range addrs {
+			if c.allowlisted(addr) {
+				continue
+			}
+			if isBlocked(addr) {
+				return nil, fmt.Errorf("%w: %s", ErrorForbiddenAddress, addr)
+			}
+			trusted = false
+		}
+	}
+
+	if _, ok := c.ports[port]; !ok && !trusted {
+		return nil, fmt.Errorf("%w: %d", ErrorForbiddenPort, port)
+	}
+
+	return c.dialer.DialContext(ctx, network, net.JoinHostPort(addrs[0].String(), portStr))
+}
+
+func (c *Client) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr.WithZone("").Unmap()}, nil
+	}
+
+	addrs, err := c.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrorNoAddress, host)
+	}
+
+	for i, addr := range addrs {
+		addrs[i] = addr.WithZone("").Unmap()
+	}
+
+	return addrs, nil
+}
+
+func (c *Client) allowlisted(addr netip.Addr) bool {
+	for _, network := range c.networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isBlocked(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() ||
+		addr.IsLinkLocalUnicast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return true
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,228 @@
+package fetch
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+type stubResolver map[string][]netip.Addr
+
+func (r stubResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return addrs, nil
+}
+
+func newTestServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, int) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return server, port
+}
+
+func serveBody(w http.ResponseWriter, _ *http.Request) {
+	w.Write([]byte("image")) //nolint:errcheck
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(body)
+}
+
+func TestClient_BlocksLoopbackByDefault(t *testing.T) {
+	server, _ := newTestServer(t, serveBody)
+
+	client, err := NewClient(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Get(server.URL)
+	assert.ErrorIs(t, err, ErrorForbiddenAddress)
+}
+
+func TestClient_AllowlistedNetwork(t *testing.T) {
+	server, _ := newTestServer(t, serveBody)
+
+	for _, entry := range []string{"127.0.0.1", "127.0.0.0/8"} {
+		client, err := NewClient(Config{Allowlist: []string{entry}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := client.Get(server.URL)
+		if assert.NoError(t, err, entry) {
+			assert.Equal(t, "image", readBody(t, resp))
+		}
+	}
+}
+
+func TestClient_ResolvesHostItself(t *testing.T) {
+	_, port := newTestServer(t, serveBody)
+	loopback := netip.MustParseAddr("127.0.0.1")
+	resolver := stubResolver{
+		"cdn.internal":      {loopback},
+		"images.example":    {loopback},
+		"rebind.example":    {netip.MustParseAddr("93.184.216.34"), loopback},
+		"metadata.example":  {netip.MustParseAddr("169.254.169.254")},
+		"mapped.example":    {netip.MustParseAddr("::ffff:127.0.0.2")},
+		"private6.example":  {netip.MustParseAddr("fd00::1")},
+		"allowlisted.cidrs": {netip.MustParseAddr("127.0.0.2")},
+	}
+
+	client, err := newClient(Config{Allowlist: []string{"cdn.internal", "127.0.0.2/32"}}, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Get("http://CDN.internal:" + strconv.Itoa(port) + "/cat.png")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "image", readBody(t, resp))
+	}
+
+	for _, host := range []string{"images.example", "rebind.example", "metadata.example", "private6.example"} {
+		_, err = client.Get("http://" + host + ":" + strconv.Itoa(port) + "/cat.png")
+		assert.ErrorIs(t, err, ErrorForbiddenAddress, host)
+	}
+
+	// 127.0.0.2 is allowlisted, so these fail only because nothing listens there.
+	for _, host := range []string{"mapped.example", "allowlisted.cidrs"} {
+		_, err = client.Get("http://" + host + ":1/cat.png")
+		assert.Error(t, err, host)
+		assert.NotErrorIs(t, err, ErrorForbiddenAddress, host)
+		assert.NotErrorIs(t, err, ErrorForbiddenPort, host)
+	}
+
+	_, err = client.Get("http://unknown.example/cat.png")
+	var dnsErr *net.DNSError
+	assert.ErrorAs(t, err, &dnsErr)
+}
+
+func TestClient_ForbiddenSchemes(t *testing.T) {
+	client, err := NewClient(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rawURL := range []string{"file:///etc/passwd", "ftp://example.com/cat.png", "gopher://example.com", "//example.com"} {
+		_, err = client.Get(rawURL)
+		assert.ErrorIs(t, err, ErrorForbiddenScheme, rawURL)
+	}
+}
+
+func TestClient_ForbiddenPorts(t *testing.T) {
+	_, port := newTestServer(t, serveBody)
+	resolver := stubResolver{"images.example": {netip.MustParseAddr("93.184.216.34")}}
+
+	client, err := newClient(Config{}, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []int{22, 5432, 27017, port} {
+		_, err = client.Get("http://images.example:" + strconv.Itoa(p) + "/cat.png")
+		assert.ErrorIs(t, err, ErrorForbiddenPort, p)
+	}
+}
+
+func TestClient_BlocksRedirectToInternalAddress(t *testing.T) {
+	server, port := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metadata":
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+		case "/loopback":
+			http.Redirect(w, r, "http://[::1]:"+r.URL.Query().Get("port")+"/", http.StatusFound)
+		case "/scheme":
+			http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+		default:
+			serveBody(w, r)
+		}
+	})
+
+	client, err := NewClient(Config{Allowlist: []string{"127.0.0.1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Get(server.URL + "/metadata")
+	assert.ErrorIs(t, err, ErrorForbiddenAddress)
+
+	_, err = client.Get(server.URL + "/loopback?port=" + strconv.Itoa(port))
+	assert.ErrorIs(t, err, ErrorForbiddenAddress)
+
+	_, err = client.Get(server.URL + "/scheme")
+	assert.ErrorIs(t, err, ErrorForbiddenScheme)
+}
+
+func TestClient_RedirectLimit(t *testing.T) {
+	server, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		hops, _ := strconv.Atoi(r.URL.Query().Get("hops"))
+		if hops == 0 {
+			serveBody(w, r)
+			return
+		}
+		http.Redirect(w, r, "/?hops="+strconv.Itoa(hops-1), http.StatusFound)
+	})
+
+	client, err := NewClient(Config{Allowlist: []string{"127.0.0.1"}, MaxRedirects: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Get(server.URL + "/?hops=2")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "image", readBody(t, resp))
+	}
+
+	_, err = client.Get(server.URL + "/?hops=3")
+	assert.ErrorIs(t, err, ErrorTooManyRedirects)
+}
+
+func TestClient_InvalidAllowlist(t *testing.T) {
+	_, err := NewClient(Config{Allowlist: []string{"10.0.0.0/33"}})
+	assert.ErrorIs(t, err, ErrorInvalidAllowlist)
+}
+
+func TestIsBlocked(t *testing.T) {
+	blocked := []string{
+		"127.0.0.1", "10.0.0.1", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1",
+		"0.0.0.0", "224.0.0.1", "255.255.255.255", "::1", "::", "fe80::1", "fd00::1",
+		"::ffff:127.0.0.1", "64:ff9b::a00:1", "ff02::1",
+	}
+	for _, addr := range blocked {
+		assert.True(t, isBlocked(netip.MustParseAddr(addr)), addr)
+	}
+
+	for _, addr := range []string{"8.8.8.8", "93.184.216.34", "2606:4700:4700::1111"} {
+		assert.False(t, isBlocked(netip.MustParseAddr(addr)), addr)
+	}
+}
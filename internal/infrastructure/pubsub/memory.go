@@ -0,0 +1,89 @@
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+)
+
+// A subscriber whose buffer is full is dropped instead of blocking Publish.
+type MemoryPubSub struct {
+	mu     sync.RWMutex
+	topics map[string]map[*subscription]struct{}
+}
+
+func NewMemoryPubSub() *MemoryPubSub {
+	return &MemoryPubSub{
+		topics: make(map[string]map[*subscription]struct{}),
+	}
+}
+
+func (p *MemoryPubSub) Publish(topic string, event *entity.Event) {
+	var overflowed []*subscription
+
+	p.mu.RLock()
+	for sub := range p.topics[topic] {
+		select {
+		case sub.events <- event:
+		default:
+			overflowed = append(overflowed, sub)
+		}
+	}
+	p.mu.RUnlock()
+
+	for _, sub := range overflowed {
+		p.unsubscribe(sub)
+	}
+}
+
+func (p *MemoryPubSub) Subscribe(topic string, buffer int) service.Subscription {
+	sub := &subscription{
+		pubsub: p,
+		topic:  topic,
+		events: make(chan *entity.Event, buffer),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	subs, ok := p.topics[topic]
+	if !ok {
+		subs = make(map[*subscription]struct{})
+		p.topics[topic] = subs
+	}
+	subs[sub] = struct{}{}
+
+	return sub
+}
+
+// Sending happens under the read lock, so the channel is never closed mid-send.
+func (p *MemoryPubSub) unsubscribe(sub *subscription) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	subs := p.topics[sub.topic]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(p.topics, sub.topic)
+	}
+	close(sub.events)
+}
+
+type subscription struct {
+	pubsub *MemoryPubSub
+	topic  string
+	events chan *entity.Event
+}
+
+func (s *subscription) Events() <-chan *entity.Event {
+	return s.events
+}
+
+func (s *subscription) Close() {
+	s.pubsub.unsubscribe(s)
+}
@@ -0,0 +1,83 @@
+package pubsub
+
+import (
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestMemoryPubSub_Publish(t *testing.T) {
+	p := NewMemoryPubSub()
+
+	first := p.Subscribe("user:1", 2)
+	second := p.Subscribe("user:1", 2)
+	other := p.Subscribe("user:2", 2)
+
+	event := &entity.Event{Type: entity.EventMessageCreated}
+	p.Publish("user:1", event)
+	p.Publish("user:3", &entity.Event{Type: entity.EventNotificationCreated})
+
+	assert.Equal(t, event, <-first.Events())
+	assert.Equal(t, event, <-second.Events())
+	assert.Empty(t, other.Events())
+}
+
+func TestMemoryPubSub_Close(t *testing.T) {
+	p := NewMemoryPubSub()
+
+	sub := p.Subscribe("user:1", 1)
+	sub.Close()
+	sub.Close()
+
+	p.Publish("user:1", &entity.Event{Type: entity.EventMessageCreated})
+
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.Empty(t, p.topics)
+}
+
+func TestMemoryPubSub_SlowSubscriber(t *testing.T) {
+	p := NewMemoryPubSub()
+
+	slow := p.Subscribe("user:1", 1)
+	fast := p.Subscribe("user:1", 3)
+
+	for range 3 {
+		p.Publish("user:1", &entity.Event{Type: entity.EventMessageCreated})
+	}
+
+	// The buffered event is still delivered before the channel is closed.
+	_, ok := <-slow.Events()
+	assert.True(t, ok)
+	_, ok = <-slow.Events()
+	assert.False(t, ok)
+
+	assert.Len(t, fast.Events(), 3)
+	slow.Close()
+}
+
+func TestMemoryPubSub_Concurrent(t *testing.T) {
+	p := NewMemoryPubSub()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				p.Publish("user:1", &entity.Event{Type: entity.EventMessageCreated})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				sub := p.Subscribe("user:1", 1)
+				sub.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Empty(t, p.topics)
+}
@@ -6,18 +6,28 @@ import (
 	"time"
 
 	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var (
-	ErrorAdsNotFound    = errors.New("ads not found")
-	ErrorFailedToSaveAd = errors.New("failed to save ad")
+	ErrorAdsNotFound      = errors.New("ads not found")
+	ErrorAdNotFound       = errors.New("ad not found")
+	ErrorFailedToSaveAd   = errors.New("failed to save ad")
+	ErrorFailedToUpdateAd = errors.New("failed to update ad")
+	ErrorFailedToDeleteAd = errors.New("failed to delete ad")
 )
 
 const (
-	collectionName = "ads"
+	collectionName  = "ads"
+	textIndexName   = "ads_text_search"
+	authorIndexName = "ads_author"
+	listedIndexName = "ads_listed"
+	listedAtField   = "listed_at"
+	textScoreField  = "score"
+	textTitleWeight = 3
 )
 
 type AdRepoMongoDB struct {
@@ -32,6 +42,92 @@ func NewAdRepoMongoDB(db *mongo.Database) *AdRepoMongoDB {
 	}
 }
 
+func (r *AdRepoMongoDB) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "title", Value: "text"},
+				{Key: "text", Value: "text"},
+			},
+			Options: options.Index().
+				SetName(textIndexName).
+				SetWeights(bson.D{{Key: "title", Value: textTitleWeight}}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "author._id", Value: 1},
+				{Key: entity.SortByCreatedAt, Value: entity.OrderByDesc},
+			},
+			Options: options.Index().SetName(authorIndexName),
+		},
+		{
+			Keys:    bson.D{{Key: listedAtField, Value: 1}},
+			Options: options.Index().SetName(listedIndexName),
+		},
+	})
+
+	return err
+}
+
+// MigrateImages turns the image_url and image_id of older ads into a gallery.
+func (r *AdRepoMongoDB) MigrateImages() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	filter := bson.M{"images": bson.M{"$exists": false}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"images": bson.A{bson.M{"url": "$image_url", "image_id": "$image_id"}},
+			"cover":  0,
+		}}},
+		{{Key: "$unset", Value: bson.A{"image_url", "image_id"}}},
+	}
+
+	res, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.ModifiedCount, nil
+}
+
+// MigrateStatus lists the ads stored without a status.
+func (r *AdRepoMongoDB) MigrateStatus() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	filter := bson.M{"status": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"status": entity.AdStatusActive, "revision": 0}}
+
+	res, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.ModifiedCount, nil
+}
+
+// MigrateListedAt uses created_at for the active ads stored without listed_at.
+func (r *AdRepoMongoDB) MigrateListedAt() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	filter := bson.M{"status": entity.AdStatusActive, listedAtField: bson.M{"$exists": false}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{listedAtField: "$" + entity.SortByCreatedAt}}},
+	}
+
+	res, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.ModifiedCount, nil
+}
+
 func (r *AdRepoMongoDB) Save(ad *entity.Ad) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -45,20 +141,37 @@ func (r *AdRepoMongoDB) Save(ad *entity.Ad) error {
 }
 
 func (r *AdRepoMongoDB) FindAll(ops *entity.Options) ([]*entity.Ad, error) {
+	ads, err := r.find(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ads) == 0 {
+		return nil, ErrorAdsNotFound
+	}
+
+	return ads, nil
+}
+
+// FindMatches is FindAll without the error for an empty page.
+func (r *AdRepoMongoDB) FindMatches(ops *entity.Options) ([]*entity.Ad, error) {
+	ads, err := r.find(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	if ads == nil {
+		ads = make([]*entity.Ad, 0)
+	}
+
+	return ads, nil
+}
+
+func (r *AdRepoMongoDB) find(ops *entity.Options) ([]*entity.Ad, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{}
-	if ops.MinPrice > 0 || ops.MaxPrice > 0 {
-		priceFilter := bson.M{}
-		if ops.MinPrice > 0 {
-			priceFilter["$gte"] = ops.MinPrice
-		}
-		if ops.MaxPrice > 0 {
-			priceFilter["$lte"] = ops.MaxPrice
-		}
-		filter[entity.SortByPrice] = priceFilter
-	}
+	filter := buildFilter(ops)
 
 	orderBy := entity.OrderByDesc
 	if ops.OrderBy == entity.OrderByAsc {
@@ -68,13 +181,30 @@ func (r *AdRepoMongoDB) FindAll(ops *entity.Options) ([]*entity.Ad, error) {
 	if ops.SortBy == entity.SortByPrice {
 		sortBy = entity.SortByPrice
 	}
-	sortOps := bson.D{{Key: sortBy, Value: orderBy}}
+	sortOps := bson.D{
+		{Key: sortBy, Value: orderBy},
+		{Key: "_id", Value: orderBy},
+	}
 
 	findOps := options.Find().
-		SetSort(sortOps).
-		SetSkip(int64((ops.Page - 1) * ops.Limit)).
 		SetLimit(int64(ops.Limit))
 
+	if ops.Cursor != nil {
+		filter["$and"] = bson.A{keysetFilter(ops.Cursor)}
+	} else {
+		findOps.SetSkip(int64((ops.Page - 1) * ops.Limit))
+	}
+
+	if ops.SortBy == entity.SortByRelevance && ops.Query != "" {
+		textScore := bson.M{"$meta": "textScore"}
+		sortOps = bson.D{
+			{Key: textScoreField, Value: textScore},
+			{Key: entity.SortByCreatedAt, Value: entity.OrderByDesc},
+		}
+		findOps.SetProjection(bson.M{textScoreField: textScore})
+	}
+	findOps.SetSort(sortOps)
+
 	cursor, err := r.collection.Find(ctx, filter, findOps)
 	if err != nil {
 		return nil, err
@@ -86,9 +216,238 @@ func (r *AdRepoMongoDB) FindAll(ops *entity.Options) ([]*entity.Ad, error) {
 		return nil, err
 	}
 
-	if len(ads) == 0 {
-		return nil, ErrorAdsNotFound
+	return ads, nil
+}
+
+// Count ignores the page and cursor of ops.
+func (r *AdRepoMongoDB) Count(ops *entity.Options) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return r.collection.CountDocuments(ctx, buildFilter(ops))
+}
+
+func buildFilter(ops *entity.Options) bson.M {
+	filter := bson.M{"status": entity.AdStatusActive}
+	if len(ops.Statuses) > 0 {
+		filter["status"] = bson.M{"$in": ops.Statuses}
+	}
+	if ops.MinPrice > 0 || ops.MaxPrice > 0 {
+		priceFilter := bson.M{}
+		if ops.MinPrice > 0 {
+			priceFilter["$gte"] = ops.MinPrice
+		}
+		if ops.MaxPrice > 0 {
+			priceFilter["$lte"] = ops.MaxPrice
+		}
+		filter[entity.SortByPrice] = priceFilter
+	}
+	if ops.Query != "" {
+		filter["$text"] = bson.M{"$search": ops.Query}
+	}
+	if len(ops.CategoryIDs) > 0 {
+		filter["category_id"] = bson.M{"$in": ops.CategoryIDs}
+	}
+	if ops.IDs != nil {
+		filter["_id"] = bson.M{"$in": ops.IDs}
+	}
+	if ops.AuthorID != uuid.Nil {
+		filter["author._id"] = ops.AuthorID
+	}
+	if !ops.ListedAfter.IsZero() || !ops.ListedBefore.IsZero() {
+		listedFilter := bson.M{}
+		if !ops.ListedAfter.IsZero() {
+			listedFilter["$gt"] = ops.ListedAfter
+		}
+		if !ops.ListedBefore.IsZero() {
+			listedFilter["$lte"] = ops.ListedBefore
+		}
+		filter[listedAtField] = listedFilter
 	}
 
-	return ads, nil
+	return filter
+}
+
+// keysetFilter matches the ads strictly after the cursor in (sort field, _id) order.
+func keysetFilter(c *entity.Cursor) bson.M {
+	cmp := "$lt"
+	if c.OrderBy == entity.OrderByAsc {
+		cmp = "$gt"
+	}
+
+	return bson.M{
+		"$or": bson.A{
+			bson.M{c.SortBy: bson.M{cmp: c.Value()}},
+			bson.M{c.SortBy: c.Value(), "_id": bson.M{cmp: c.ID}},
+		},
+	}
+}
+
+func (r *AdRepoMongoDB) FindByID(id uuid.UUID) (*entity.Ad, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var ad entity.Ad
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&ad)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrorAdNotFound
+		}
+
+		return nil, err
+	}
+
+	return &ad, nil
+}
+
+func (r *AdRepoMongoDB) Update(ad *entity.Ad) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	set := bson.M{
+		"title":            ad.Title,
+		"text":             ad.Text,
+		"images":           ad.Images,
+		"cover":            ad.Cover,
+		"price":            ad.Price,
+		"category_id":      ad.CategoryID,
+		"status":           ad.Status,
+		"rejection_reason": ad.RejectionReason,
+		"revision":         ad.Revision,
+		"updated_at":       ad.UpdatedAt,
+	}
+	if !ad.ListedAt.IsZero() {
+		set[listedAtField] = ad.ListedAt
+	}
+	update := bson.M{"$set": set}
+
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": ad.ID}, update)
+	if err != nil {
+		return ErrorFailedToUpdateAd
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrorAdNotFound
+	}
+
+	return nil
+}
+
+func (r *AdRepoMongoDB) IncrementViews(id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"views": 1}})
+	if err != nil {
+		return ErrorFailedToUpdateAd
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrorAdNotFound
+	}
+
+	return nil
+}
+
+// Ads that already show the username are not matched, so a call can be repeated.
+func (r *AdRepoMongoDB) RenameAuthor(authorID uuid.UUID, username string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	filter := bson.M{"author._id": authorID, "author.username": bson.M{"$ne": username}}
+	update := bson.M{"$set": bson.M{"author.username": username}}
+
+	res, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, ErrorFailedToUpdateAd
+	}
+
+	return res.ModifiedCount, nil
+}
+
+// FindPendingIDs returns the ads waiting for their images to be verified.
+func (r *AdRepoMongoDB) FindPendingIDs() ([]uuid.UUID, error) {
+	return r.findIDs(bson.M{"status": entity.AdStatusPending})
+}
+
+func (r *AdRepoMongoDB) FindIDsByAuthor(authorID uuid.UUID) ([]uuid.UUID, error) {
+	return r.findIDs(bson.M{"author._id": authorID})
+}
+
+func (r *AdRepoMongoDB) findIDs(filter bson.M) ([]uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	findOps := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.collection.Find(ctx, filter, findOps)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx) //nolint:errcheck
+
+	var docs []struct {
+		ID uuid.UUID `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+
+	return ids, nil
+}
+
+// UpdateVerification reports false if the ad was edited or deleted meanwhile.
+func (r *AdRepoMongoDB) UpdateVerification(ad *entity.Ad) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": ad.ID, "revision": ad.Revision}
+	set := bson.M{
+		"images":           ad.Images,
+		"status":           ad.Status,
+		"rejection_reason": ad.RejectionReason,
+	}
+	if !ad.ListedAt.IsZero() {
+		set[listedAtField] = ad.ListedAt
+	}
+	update := bson.M{"$set": set}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, ErrorFailedToUpdateAd
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+func (r *AdRepoMongoDB) Delete(id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return ErrorFailedToDeleteAd
+	}
+
+	if res.DeletedCount == 0 {
+		return ErrorAdNotFound
+	}
+
+	return nil
+}
+
+func (r *AdRepoMongoDB) DeleteByAuthor(authorID uuid.UUID) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	res, err := r.collection.DeleteMany(ctx, bson.M{"author._id": authorID})
+	if err != nil {
+		return 0, ErrorFailedToDeleteAd
+	}
+
+	return res.DeletedCount, nil
 }
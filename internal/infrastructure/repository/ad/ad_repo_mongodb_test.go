@@ -7,6 +7,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 	"testing"
+	"time"
 )
 
 func TestAdRepoMongoDB_Save(t *testing.T) {
@@ -81,6 +82,9 @@ func TestAdRepoMongoDB_FindAll(t *testing.T) {
 		assert.Len(t, ads, 2)
 		assert.Equal(t, expected[0], ads[0])
 		assert.Equal(t, expected[1], ads[1])
+
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, string(entity.AdStatusActive), filter.Lookup("status").StringValue())
 	})
 
 	mt.Run("Failure - not found", func(mt *mtest.T) {
@@ -136,6 +140,79 @@ func TestAdRepoMongoDB_FindAll(t *testing.T) {
 		assert.Equal(t, expected[1], ads[1])
 	})
 
+	mt.Run("Success - restricted to IDs", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		ids := []uuid.UUID{uuid.New(), uuid.New()}
+		opts := &entity.Options{
+			Page:  1,
+			Limit: 10,
+			IDs:   ids,
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: ids[1]},
+		}))
+
+		ads, err := repo.FindAll(opts)
+
+		assert.NoError(t, err)
+		assert.Len(t, ads, 1)
+
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		in := filter.Lookup("_id").Document().Lookup("$in").Array()
+		values, err := in.Values()
+		assert.NoError(t, err)
+		assert.Len(t, values, 2)
+	})
+
+	mt.Run("Success - restricted to author", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		opts := &entity.Options{
+			Page:     1,
+			Limit:    10,
+			AuthorID: uuid.New(),
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: uuid.New()},
+		}))
+
+		ads, err := repo.FindAll(opts)
+
+		assert.NoError(t, err)
+		assert.Len(t, ads, 1)
+
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		_, err = filter.LookupErr("author._id")
+		assert.NoError(t, err)
+		assert.Equal(t, string(entity.AdStatusActive), filter.Lookup("status").StringValue())
+	})
+
+	mt.Run("Success - any of statuses", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		opts := &entity.Options{
+			Page:     1,
+			Limit:    10,
+			Statuses: []entity.AdStatus{entity.AdStatusPending, entity.AdStatusRejected},
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: uuid.New()},
+		}))
+
+		_, err := repo.FindAll(opts)
+
+		assert.NoError(t, err)
+
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		values, err := filter.Lookup("status", "$in").Array().Values()
+		assert.NoError(t, err)
+		if assert.Len(t, values, 2) {
+			assert.Equal(t, string(entity.AdStatusPending), values[0].StringValue())
+			assert.Equal(t, string(entity.AdStatusRejected), values[1].StringValue())
+		}
+	})
+
 	mt.Run("Failure - error in find command", func(mt *mtest.T) {
 		repo := NewAdRepoMongoDB(mt.DB)
 		opts := &entity.Options{
@@ -174,3 +251,745 @@ func TestAdRepoMongoDB_FindAll(t *testing.T) {
 		assert.Nil(t, ads)
 	})
 }
+
+func TestAdRepoMongoDB_FindMatches(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success - listed window", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		after := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+		before := after.Add(10 * time.Minute)
+		opts := &entity.Options{
+			Page:         1,
+			Limit:        40,
+			ListedAfter:  after,
+			ListedBefore: before,
+		}
+		id := uuid.New()
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: id},
+		}))
+
+		ads, err := repo.FindMatches(opts)
+
+		assert.NoError(t, err)
+		assert.Len(t, ads, 1)
+		assert.Equal(t, id, ads[0].ID)
+
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		listed := filter.Lookup("listed_at").Document()
+		assert.Equal(t, after, listed.Lookup("$gt").Time().UTC())
+		assert.Equal(t, before, listed.Lookup("$lte").Time().UTC())
+		_, err = filter.LookupErr("created_at")
+		assert.Error(t, err)
+	})
+
+	mt.Run("Success - no matches", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		opts := &entity.Options{
+			Page:  1,
+			Limit: 40,
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch))
+
+		ads, err := repo.FindMatches(opts)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, ads)
+		assert.Empty(t, ads)
+	})
+}
+
+func TestAdRepoMongoDB_FindByID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		expected := &entity.Ad{
+			ID:    uuid.New(),
+			Title: "test",
+			Price: 150,
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: expected.ID},
+			{Key: "title", Value: expected.Title},
+			{Key: "price", Value: expected.Price},
+		}))
+
+		found, err := repo.FindByID(expected.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, found)
+	})
+
+	mt.Run("Failure - not found", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch))
+		found, err := repo.FindByID(uuid.New())
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrorAdNotFound)
+		assert.Nil(t, found)
+	})
+
+	mt.Run("Failure - error in find command", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		found, err := repo.FindByID(uuid.New())
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrorAdNotFound)
+		assert.Nil(t, found)
+	})
+}
+
+func TestAdRepoMongoDB_MigrateImages(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 3},
+			bson.E{Key: "nModified", Value: 3},
+		))
+		migrated, err := repo.MigrateImages()
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), migrated)
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.True(t, update.Lookup("multi").Boolean())
+		_, err = update.LookupErr("q", "images", "$exists")
+		assert.NoError(t, err)
+
+		pipeline := update.Lookup("u").Array()
+		image := pipeline.Index(0).Value().Document().Lookup("$set", "images").Array().Index(0).Value().Document()
+		assert.Equal(t, "$image_url", image.Lookup("url").StringValue())
+		assert.Equal(t, "$image_id", image.Lookup("image_id").StringValue())
+		_, err = pipeline.Index(1).Value().Document().LookupErr("$unset")
+		assert.NoError(t, err)
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		migrated, err := repo.MigrateImages()
+
+		assert.Error(t, err)
+		assert.Zero(t, migrated)
+	})
+}
+
+func TestAdRepoMongoDB_MigrateStatus(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 2},
+			bson.E{Key: "nModified", Value: 2},
+		))
+		migrated, err := repo.MigrateStatus()
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), migrated)
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.True(t, update.Lookup("multi").Boolean())
+		_, err = update.LookupErr("q", "status", "$exists")
+		assert.NoError(t, err)
+		assert.Equal(t, string(entity.AdStatusActive), update.Lookup("u", "$set", "status").StringValue())
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		migrated, err := repo.MigrateStatus()
+
+		assert.Error(t, err)
+		assert.Zero(t, migrated)
+	})
+}
+
+func TestAdRepoMongoDB_MigrateListedAt(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 3},
+			bson.E{Key: "nModified", Value: 3},
+		))
+		migrated, err := repo.MigrateListedAt()
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), migrated)
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, string(entity.AdStatusActive), update.Lookup("q", "status").StringValue())
+		_, err = update.LookupErr("q", "listed_at", "$exists")
+		assert.NoError(t, err)
+		set := update.Lookup("u").Array().Index(0).Value().Document()
+		assert.Equal(t, "$created_at", set.Lookup("$set", "listed_at").StringValue())
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		migrated, err := repo.MigrateListedAt()
+
+		assert.Error(t, err)
+		assert.Zero(t, migrated)
+	})
+}
+
+func TestAdRepoMongoDB_FindPendingIDs(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		first, second := uuid.New(), uuid.New()
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(1, "db.ads", mtest.FirstBatch, bson.D{{Key: "_id", Value: first}}),
+			mtest.CreateCursorResponse(0, "db.ads", mtest.NextBatch, bson.D{{Key: "_id", Value: second}}),
+		)
+		ids, err := repo.FindPendingIDs()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{first, second}, ids)
+
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, string(entity.AdStatusPending), filter.Lookup("status").StringValue())
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		ids, err := repo.FindPendingIDs()
+
+		assert.Error(t, err)
+		assert.Nil(t, ids)
+	})
+}
+
+func TestAdRepoMongoDB_FindIDsByAuthor(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		authorID, adID := uuid.New(), uuid.New()
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch, bson.D{{Key: "_id", Value: adID}}))
+		ids, err := repo.FindIDsByAuthor(authorID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{adID}, ids)
+
+		_, data := mt.GetStartedEvent().Command.Lookup("filter", "author._id").Binary()
+		assert.Equal(t, authorID[:], data)
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		ids, err := repo.FindIDsByAuthor(uuid.New())
+
+		assert.Error(t, err)
+		assert.Nil(t, ids)
+	})
+}
+
+func TestAdRepoMongoDB_UpdateVerification(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		ad := &entity.Ad{
+			ID:              uuid.New(),
+			Images:          []entity.AdImage{{URL: "https://example.com/cat.png", Pending: true}},
+			Status:          entity.AdStatusRejected,
+			RejectionReason: "error - image is not available: 404",
+			Revision:        3,
+		}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "nModified", Value: 1},
+		))
+		stored, err := repo.UpdateVerification(ad)
+
+		assert.NoError(t, err)
+		assert.True(t, stored)
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, int64(3), update.Lookup("q", "revision").Int64())
+		assert.Equal(t, string(entity.AdStatusRejected), update.Lookup("u", "$set", "status").StringValue())
+		assert.Equal(t, ad.RejectionReason, update.Lookup("u", "$set", "rejection_reason").StringValue())
+		image := update.Lookup("u", "$set", "images").Array().Index(0).Value().Document()
+		assert.True(t, image.Lookup("pending").Boolean())
+		_, err = update.LookupErr("u", "$set", "listed_at")
+		assert.Error(t, err)
+	})
+
+	mt.Run("Success - listed", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		listedAt := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+		ad := &entity.Ad{
+			ID:       uuid.New(),
+			Images:   []entity.AdImage{{URL: "https://example.com/cat.png"}},
+			Status:   entity.AdStatusActive,
+			ListedAt: listedAt,
+		}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "nModified", Value: 1},
+		))
+		stored, err := repo.UpdateVerification(ad)
+
+		assert.NoError(t, err)
+		assert.True(t, stored)
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, listedAt, update.Lookup("u", "$set", "listed_at").Time().UTC())
+	})
+
+	mt.Run("Failure - edited meanwhile", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 0},
+			bson.E{Key: "nModified", Value: 0},
+		))
+		stored, err := repo.UpdateVerification(&entity.Ad{ID: uuid.New()})
+
+		assert.NoError(t, err)
+		assert.False(t, stored)
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		stored, err := repo.UpdateVerification(&entity.Ad{ID: uuid.New()})
+
+		assert.ErrorIs(t, err, ErrorFailedToUpdateAd)
+		assert.False(t, stored)
+	})
+}
+
+func TestAdRepoMongoDB_Update(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		ad := &entity.Ad{
+			ID:       uuid.New(),
+			Title:    "test",
+			Status:   entity.AdStatusPending,
+			Revision: 2,
+		}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "nModified", Value: 1},
+		))
+		err := repo.Update(ad)
+
+		assert.NoError(t, err)
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().
+			Lookup("u").Document().Lookup("$set").Document()
+		assert.Equal(t, string(entity.AdStatusPending), update.Lookup("status").StringValue())
+		assert.Equal(t, int64(2), update.Lookup("revision").Int64())
+	})
+
+	mt.Run("Failure - not found", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		ad := &entity.Ad{
+			ID:    uuid.New(),
+			Title: "test",
+		}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 0},
+			bson.E{Key: "nModified", Value: 0},
+		))
+		err := repo.Update(ad)
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrorAdNotFound)
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		ad := &entity.Ad{
+			ID:    uuid.New(),
+			Title: "test",
+		}
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		err := repo.Update(ad)
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrorFailedToUpdateAd)
+	})
+}
+
+func TestAdRepoMongoDB_IncrementViews(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "nModified", Value: 1},
+		))
+		err := repo.IncrementViews(uuid.New())
+
+		assert.NoError(t, err)
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().
+			Lookup("u").Document()
+		assert.Equal(t, int32(1), update.Lookup("$inc", "views").Int32())
+	})
+
+	mt.Run("Failure - not found", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 0},
+			bson.E{Key: "nModified", Value: 0},
+		))
+		err := repo.IncrementViews(uuid.New())
+
+		assert.ErrorIs(t, err, ErrorAdNotFound)
+	})
+}
+
+func TestAdRepoMongoDB_RenameAuthor(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		authorID := uuid.New()
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 2},
+			bson.E{Key: "nModified", Value: 2},
+		))
+		renamed, err := repo.RenameAuthor(authorID, "renamed")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), renamed)
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.True(t, update.Lookup("multi").Boolean())
+
+		filter := update.Lookup("q").Document()
+		_, data := filter.Lookup("author._id").Binary()
+		assert.Equal(t, authorID[:], data)
+		assert.Equal(t, "renamed", filter.Lookup("author.username", "$ne").StringValue())
+		assert.Equal(t, "renamed", update.Lookup("u", "$set", "author.username").StringValue())
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		_, err := repo.RenameAuthor(uuid.New(), "renamed")
+
+		assert.ErrorIs(t, err, ErrorFailedToUpdateAd)
+	})
+}
+
+func TestAdRepoMongoDB_Delete(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+		err := repo.Delete(uuid.New())
+
+		assert.NoError(t, err)
+	})
+
+	mt.Run("Failure - not found", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+		err := repo.Delete(uuid.New())
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrorAdNotFound)
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		err := repo.Delete(uuid.New())
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrorFailedToDeleteAd)
+	})
+}
+
+func TestAdRepoMongoDB_DeleteByAuthor(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		authorID := uuid.New()
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}))
+		deleted, err := repo.DeleteByAuthor(authorID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+
+		deletion := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document()
+		assert.Equal(t, int32(0), deletion.Lookup("limit").Int32())
+		_, data := deletion.Lookup("q", "author._id").Binary()
+		assert.Equal(t, authorID[:], data)
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		_, err := repo.DeleteByAuthor(uuid.New())
+
+		assert.ErrorIs(t, err, ErrorFailedToDeleteAd)
+	})
+}
+
+func TestAdRepoMongoDB_FindAll_TextSearch(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success - sorted by relevance", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		opts := &entity.Options{
+			Page:   1,
+			Limit:  10,
+			SortBy: entity.SortByRelevance,
+			Query:  "blue bike",
+		}
+		expected := &entity.Ad{
+			ID:    uuid.New(),
+			Title: "blue bike",
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: expected.ID},
+			{Key: "title", Value: expected.Title},
+			{Key: "score", Value: 1.5},
+		}))
+
+		ads, err := repo.FindAll(opts)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.Ad{expected}, ads)
+
+		cmd := mt.GetStartedEvent().Command
+		assert.Equal(t, "blue bike",
+			cmd.Lookup("filter", "$text", "$search").StringValue())
+		assert.Equal(t, "score", cmd.Lookup("sort").Document().Index(0).Key())
+		assert.Equal(t, "textScore", cmd.Lookup("projection", "score", "$meta").StringValue())
+	})
+
+	mt.Run("Success - query with regular sort", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		opts := &entity.Options{
+			Page:    1,
+			Limit:   10,
+			SortBy:  entity.SortByPrice,
+			OrderBy: entity.OrderByAsc,
+			Query:   "bike",
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: uuid.New()},
+		}))
+
+		_, err := repo.FindAll(opts)
+
+		assert.NoError(t, err)
+
+		cmd := mt.GetStartedEvent().Command
+		assert.Equal(t, "bike", cmd.Lookup("filter", "$text", "$search").StringValue())
+		assert.Equal(t, "price", cmd.Lookup("sort").Document().Index(0).Key())
+		_, err = cmd.LookupErr("projection")
+		assert.Error(t, err)
+	})
+}
+
+func TestAdRepoMongoDB_EnsureIndexes(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		err := repo.EnsureIndexes()
+
+		assert.NoError(t, err)
+
+		indexes := mt.GetStartedEvent().Command.Lookup("indexes").Array()
+		index := indexes.Index(0).Value().Document()
+		assert.Equal(t, textIndexName, index.Lookup("name").StringValue())
+		assert.Equal(t, "text", index.Lookup("key", "title").StringValue())
+		assert.Equal(t, "text", index.Lookup("key", "text").StringValue())
+
+		index = indexes.Index(1).Value().Document()
+		assert.Equal(t, authorIndexName, index.Lookup("name").StringValue())
+		assert.Equal(t, "author._id", index.Lookup("key").Document().Index(0).Key())
+
+		index = indexes.Index(2).Value().Document()
+		assert.Equal(t, listedIndexName, index.Lookup("name").StringValue())
+		assert.Equal(t, "listed_at", index.Lookup("key").Document().Index(0).Key())
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		err := repo.EnsureIndexes()
+
+		assert.Error(t, err)
+	})
+}
+
+func TestAdRepoMongoDB_FindAll_Cursor(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success - descending by price", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		cursor := &entity.Cursor{
+			SortBy:  entity.SortByPrice,
+			OrderBy: entity.OrderByDesc,
+			ID:      uuid.New(),
+			Price:   150,
+		}
+		opts := &entity.Options{
+			Page:    1,
+			Limit:   10,
+			SortBy:  entity.SortByPrice,
+			OrderBy: entity.OrderByDesc,
+			Cursor:  cursor,
+		}
+		expected := &entity.Ad{
+			ID:    uuid.New(),
+			Price: 100,
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: expected.ID},
+			{Key: "price", Value: expected.Price},
+		}))
+
+		ads, err := repo.FindAll(opts)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.Ad{expected}, ads)
+
+		cmd := mt.GetStartedEvent().Command
+		or := cmd.Lookup("filter", "$and").Array().Index(0).Value().Document().Lookup("$or").Array()
+		assert.Equal(t, 150.0, or.Index(0).Value().Document().Lookup("price", "$lt").Double())
+		assert.Equal(t, 150.0, or.Index(1).Value().Document().Lookup("price").Double())
+		_, err = or.Index(1).Value().Document().LookupErr("_id", "$lt")
+		assert.NoError(t, err)
+		_, err = cmd.LookupErr("skip")
+		assert.Error(t, err)
+		assert.Equal(t, "_id", cmd.Lookup("sort").Document().Index(1).Key())
+	})
+
+	mt.Run("Success - ascending by created_at", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		opts := &entity.Options{
+			Page:    1,
+			Limit:   10,
+			SortBy:  entity.SortByCreatedAt,
+			OrderBy: entity.OrderByAsc,
+			Cursor: &entity.Cursor{
+				SortBy:    entity.SortByCreatedAt,
+				OrderBy:   entity.OrderByAsc,
+				ID:        uuid.New(),
+				CreatedAt: time.Now(),
+			},
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: uuid.New()},
+		}))
+
+		_, err := repo.FindAll(opts)
+
+		assert.NoError(t, err)
+
+		cmd := mt.GetStartedEvent().Command
+		or := cmd.Lookup("filter", "$and").Array().Index(0).Value().Document().Lookup("$or").Array()
+		_, err = or.Index(0).Value().Document().LookupErr("created_at", "$gt")
+		assert.NoError(t, err)
+	})
+}
+
+func TestAdRepoMongoDB_Count(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+		opts := &entity.Options{
+			Page:     3,
+			Limit:    10,
+			MinPrice: 100,
+			MaxPrice: 200,
+			Cursor: &entity.Cursor{
+				SortBy:  entity.SortByPrice,
+				OrderBy: entity.OrderByDesc,
+				ID:      uuid.New(),
+			},
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ads", mtest.FirstBatch, bson.D{
+			{Key: "n", Value: int32(42)},
+		}))
+
+		total, err := repo.Count(opts)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), total)
+
+		match := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Index(0).Value().Document()
+		assert.Equal(t, 100.0, match.Lookup("$match", "price", "$gte").Double())
+		assert.Equal(t, string(entity.AdStatusActive), match.Lookup("$match", "status").StringValue())
+		_, err = match.LookupErr("$match", "$and")
+		assert.Error(t, err)
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewAdRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		total, err := repo.Count(&entity.Options{Page: 1, Limit: 10})
+
+		assert.Error(t, err)
+		assert.Zero(t, total)
+	})
+}
@@ -0,0 +1,98 @@
+package category
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrorCategoryNotFound = errors.New("category not found")
+)
+
+type PgxPool interface {
+	Query(context.Context, string, ...any) (pgx.Rows, error)
+	QueryRow(context.Context, string, ...any) pgx.Row
+}
+
+type CategoryRepoPostgres struct {
+	db PgxPool
+}
+
+func NewCategoryRepoPostgres(db PgxPool) *CategoryRepoPostgres {
+	return &CategoryRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *CategoryRepoPostgres) FindAll() ([]*entity.Category, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		"SELECT id, parent_id, name FROM categories ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*entity.Category
+	for rows.Next() {
+		var c entity.Category
+		if err = rows.Scan(&c.ID, &c.ParentID, &c.Name); err != nil {
+			return nil, err
+		}
+		categories = append(categories, &c)
+	}
+
+	return categories, rows.Err()
+}
+
+func (r *CategoryRepoPostgres) ExistsByID(id int64) (bool, error) {
+	var exists bool
+
+	err := r.db.QueryRow(
+		context.Background(),
+		"SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1)",
+		id).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// FindSubtreeIDs includes id itself.
+func (r *CategoryRepoPostgres) FindSubtreeIDs(id int64) ([]int64, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		`WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = $1
+			UNION ALL
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT id FROM subtree`,
+		id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var categoryID int64
+		if err = rows.Scan(&categoryID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, categoryID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return nil, ErrorCategoryNotFound
+	}
+
+	return ids, nil
+}
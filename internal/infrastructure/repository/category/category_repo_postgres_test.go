@@ -0,0 +1,139 @@
+package category
+
+import (
+	"errors"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCategoryRepoPostgres_FindAll(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewCategoryRepoPostgres(mock)
+	parentID := int64(1)
+
+	t.Run("Success", func(t *testing.T) {
+		rows := mock.NewRows([]string{"id", "parent_id", "name"}).
+			AddRow(int64(1), nil, "Electronics").
+			AddRow(int64(2), &parentID, "Phones")
+
+		mock.ExpectQuery("SELECT id, parent_id, name FROM categories ORDER BY name").
+			WillReturnRows(rows)
+
+		categories, err := repo.FindAll()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.Category{
+			{ID: 1, Name: "Electronics"},
+			{ID: 2, ParentID: &parentID, Name: "Phones"},
+		}, categories)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure - database error", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectQuery("SELECT id, parent_id, name FROM categories ORDER BY name").
+			WillReturnError(testErr)
+
+		categories, err := repo.FindAll()
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, testErr)
+		assert.Nil(t, categories)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCategoryRepoPostgres_ExistsByID(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewCategoryRepoPostgres(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1)").
+			WithArgs(int64(3)).
+			WillReturnRows(mock.NewRows([]string{"exists"}).AddRow(true))
+
+		exists, err := repo.ExistsByID(3)
+
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure - database error", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectQuery("SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1)").
+			WithArgs(int64(3)).
+			WillReturnError(testErr)
+
+		exists, err := repo.ExistsByID(3)
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, testErr)
+		assert.False(t, exists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCategoryRepoPostgres_FindSubtreeIDs(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewCategoryRepoPostgres(mock)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("WITH RECURSIVE subtree").
+			WithArgs(int64(1)).
+			WillReturnRows(mock.NewRows([]string{"id"}).
+				AddRow(int64(1)).
+				AddRow(int64(2)).
+				AddRow(int64(5)))
+
+		ids, err := repo.FindSubtreeIDs(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 5}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure - not found", func(t *testing.T) {
+		mock.ExpectQuery("WITH RECURSIVE subtree").
+			WithArgs(int64(42)).
+			WillReturnRows(mock.NewRows([]string{"id"}))
+
+		ids, err := repo.FindSubtreeIDs(42)
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrorCategoryNotFound)
+		assert.Nil(t, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure - database error", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectQuery("WITH RECURSIVE subtree").
+			WithArgs(int64(1)).
+			WillReturnError(testErr)
+
+		ids, err := repo.FindSubtreeIDs(1)
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, testErr)
+		assert.Nil(t, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,245 @@
+package conversation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	conversationColumns = "c.id, c.ad_id, c.ad_title, c.buyer_id, b.username, c.seller_id, s.username, " +
+		"c.created_at, c.last_message_at"
+	conversationFrom = " FROM conversations c " +
+		"JOIN users b ON b.id = c.buyer_id JOIN users s ON s.id = c.seller_id"
+	messageColumns = "id, conversation_id, sender_id, text, created_at, read_at"
+)
+
+type PgxPool interface {
+	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
+	Query(context.Context, string, ...any) (pgx.Rows, error)
+	QueryRow(context.Context, string, ...any) pgx.Row
+	Begin(context.Context) (pgx.Tx, error)
+}
+
+type ConversationRepoPostgres struct {
+	db PgxPool
+}
+
+func NewConversationRepoPostgres(db PgxPool) *ConversationRepoPostgres {
+	return &ConversationRepoPostgres{
+		db: db,
+	}
+}
+
+// Start reports false if the buyer already had a conversation about the ad;
+// its ID and creation time are then copied into conversation.
+func (r *ConversationRepoPostgres) Start(conversation *entity.Conversation, message *entity.Message) (bool, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	var created bool
+	err = tx.QueryRow(ctx,
+		"INSERT INTO conversations (id, ad_id, ad_title, buyer_id, seller_id, created_at, last_message_at) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7) "+
+			"ON CONFLICT (ad_id, buyer_id) DO UPDATE SET last_message_at = EXCLUDED.last_message_at "+
+			"RETURNING id, created_at, xmax = 0",
+		conversation.ID, conversation.AdID, conversation.AdTitle, conversation.BuyerID, conversation.SellerID,
+		conversation.CreatedAt, message.CreatedAt).
+		Scan(&conversation.ID, &conversation.CreatedAt, &created)
+	if err != nil {
+		return false, err
+	}
+	conversation.LastMessageAt = message.CreatedAt
+	message.ConversationID = conversation.ID
+
+	if err = insertMessage(ctx, tx, message); err != nil {
+		return false, err
+	}
+
+	return created, tx.Commit(ctx)
+}
+
+// FindByID returns nil if there is no such conversation.
+func (r *ConversationRepoPostgres) FindByID(id uuid.UUID) (*entity.Conversation, error) {
+	return r.findOne(
+		"SELECT "+conversationColumns+conversationFrom+" WHERE c.id = $1",
+		id)
+}
+
+func (r *ConversationRepoPostgres) FindByAd(adID, buyerID uuid.UUID) (*entity.Conversation, error) {
+	return r.findOne(
+		"SELECT "+conversationColumns+conversationFrom+" WHERE c.ad_id = $1 AND c.buyer_id = $2",
+		adID, buyerID)
+}
+
+func (r *ConversationRepoPostgres) FindByUser(userID uuid.UUID, limit, offset int) ([]*entity.Conversation, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		"SELECT "+conversationColumns+", "+
+			"(SELECT COUNT(*) FROM messages m "+
+			"WHERE m.conversation_id = c.id AND m.sender_id <> $1 AND m.read_at IS NULL)"+
+			conversationFrom+" WHERE c.buyer_id = $1 OR c.seller_id = $1 "+
+			"ORDER BY c.last_message_at DESC, c.id DESC LIMIT $2 OFFSET $3",
+		userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := make([]*entity.Conversation, 0, limit)
+	for rows.Next() {
+		var c entity.Conversation
+		err = rows.Scan(&c.ID, &c.AdID, &c.AdTitle, &c.BuyerID, &c.BuyerUsername, &c.SellerID, &c.SellerUsername,
+			&c.CreatedAt, &c.LastMessageAt, &c.Unread)
+		if err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, &c)
+	}
+
+	return conversations, rows.Err()
+}
+
+func (r *ConversationRepoPostgres) CountByUser(userID uuid.UUID) (int64, error) {
+	return r.count(
+		"SELECT COUNT(*) FROM conversations WHERE buyer_id = $1 OR seller_id = $1",
+		userID)
+}
+
+func (r *ConversationRepoPostgres) CountUnread(userID uuid.UUID) (int64, error) {
+	return r.count(
+		"SELECT COUNT(*) FROM messages m JOIN conversations c ON c.id = m.conversation_id "+
+			"WHERE (c.buyer_id = $1 OR c.seller_id = $1) AND m.sender_id <> $1 AND m.read_at IS NULL",
+		userID)
+}
+
+func (r *ConversationRepoPostgres) SaveMessage(message *entity.Message) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if err = insertMessage(ctx, tx, message); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx,
+		"UPDATE conversations SET last_message_at = $1 WHERE id = $2",
+		message.CreatedAt, message.ConversationID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ConversationRepoPostgres) FindMessages(conversationID uuid.UUID, limit, offset int) ([]*entity.Message, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		"SELECT "+messageColumns+" FROM messages WHERE conversation_id = $1 "+
+			"ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3",
+		conversationID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]*entity.Message, 0, limit)
+	for rows.Next() {
+		var m entity.Message
+		if err = rows.Scan(&m.ID, &m.ConversationID, &m.SenderID, &m.Text, &m.CreatedAt, &m.ReadAt); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &m)
+	}
+
+	return messages, rows.Err()
+}
+
+func (r *ConversationRepoPostgres) CountMessages(conversationID uuid.UUID) (int64, error) {
+	return r.count(
+		"SELECT COUNT(*) FROM messages WHERE conversation_id = $1",
+		conversationID)
+}
+
+// Ads without messages are left out.
+func (r *ConversationRepoPostgres) CountMessagesByAds(adIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		"SELECT c.ad_id, COUNT(*) FROM messages m JOIN conversations c ON c.id = m.conversation_id "+
+			"WHERE c.ad_id = ANY($1) GROUP BY c.ad_id",
+		adIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[uuid.UUID]int64)
+	for rows.Next() {
+		var adID uuid.UUID
+		var count int64
+		if err = rows.Scan(&adID, &count); err != nil {
+			return nil, err
+		}
+		counts[adID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *ConversationRepoPostgres) MarkRead(conversationID, readerID uuid.UUID, readAt time.Time) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"UPDATE messages SET read_at = $1 "+
+			"WHERE conversation_id = $2 AND sender_id <> $3 AND read_at IS NULL",
+		readAt, conversationID, readerID)
+
+	return err
+}
+
+func (r *ConversationRepoPostgres) findOne(query string, args ...any) (*entity.Conversation, error) {
+	var c entity.Conversation
+
+	err := r.db.QueryRow(context.Background(), query, args...).
+		Scan(&c.ID, &c.AdID, &c.AdTitle, &c.BuyerID, &c.BuyerUsername, &c.SellerID, &c.SellerUsername,
+			&c.CreatedAt, &c.LastMessageAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (r *ConversationRepoPostgres) count(query string, args ...any) (int64, error) {
+	var count int64
+
+	if err := r.db.QueryRow(context.Background(), query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func insertMessage(ctx context.Context, tx pgx.Tx, m *entity.Message) error {
+	_, err := tx.Exec(ctx,
+		"INSERT INTO messages ("+messageColumns+") VALUES ($1, $2, $3, $4, $5, $6)",
+		m.ID, m.ConversationID, m.SenderID, m.Text, m.CreatedAt, m.ReadAt)
+
+	return err
+}
@@ -0,0 +1,355 @@
+package conversation
+
+import (
+	"errors"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+const (
+	startConversationQuery = "INSERT INTO conversations (id, ad_id, ad_title, buyer_id, seller_id, created_at, " +
+		"last_message_at) VALUES ($1, $2, $3, $4, $5, $6, $7) " +
+		"ON CONFLICT (ad_id, buyer_id) DO UPDATE SET last_message_at = EXCLUDED.last_message_at " +
+		"RETURNING id, created_at, xmax = 0"
+	insertMessageQuery = "INSERT INTO messages (id, conversation_id, sender_id, text, created_at, read_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6)"
+	selectConversationQuery = "SELECT c.id, c.ad_id, c.ad_title, c.buyer_id, b.username, c.seller_id, " +
+		"s.username, c.created_at, c.last_message_at"
+	fromConversationsQuery = " FROM conversations c " +
+		"JOIN users b ON b.id = c.buyer_id JOIN users s ON s.id = c.seller_id"
+)
+
+var conversationRows = []string{"id", "ad_id", "ad_title", "buyer_id", "buyer_username", "seller_id",
+	"seller_username", "created_at", "last_message_at"}
+
+func newTestConversation() (*entity.Conversation, *entity.Message) {
+	ad := &entity.Ad{ID: uuid.New(), Title: "Red bike", Author: &entity.Author{ID: uuid.New(), Username: "seller"}}
+	conversation := entity.NewConversation(ad, &entity.User{ID: uuid.New(), Username: "buyer"})
+
+	return conversation, entity.NewMessage(conversation.ID, conversation.BuyerID, "Is it still available?")
+}
+
+func TestConversationRepoPostgres_Start(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewConversationRepoPostgres(mock)
+
+	t.Run("Created", func(t *testing.T) {
+		c, m := newTestConversation()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(startConversationQuery).
+			WithArgs(c.ID, c.AdID, c.AdTitle, c.BuyerID, c.SellerID, c.CreatedAt, m.CreatedAt).
+			WillReturnRows(mock.NewRows([]string{"id", "created_at", "created"}).AddRow(c.ID, c.CreatedAt, true))
+		mock.ExpectExec(insertMessageQuery).
+			WithArgs(m.ID, c.ID, m.SenderID, m.Text, m.CreatedAt, m.ReadAt).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		created, err := repo.Start(c, m)
+
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Existing", func(t *testing.T) {
+		c, m := newTestConversation()
+		existingID, existingCreatedAt := uuid.New(), time.Now().Add(-time.Hour)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(startConversationQuery).
+			WithArgs(c.ID, c.AdID, c.AdTitle, c.BuyerID, c.SellerID, c.CreatedAt, m.CreatedAt).
+			WillReturnRows(mock.NewRows([]string{"id", "created_at", "created"}).
+				AddRow(existingID, existingCreatedAt, false))
+		mock.ExpectExec(insertMessageQuery).
+			WithArgs(m.ID, existingID, m.SenderID, m.Text, m.CreatedAt, m.ReadAt).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		created, err := repo.Start(c, m)
+
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, existingID, c.ID)
+		assert.Equal(t, existingCreatedAt, c.CreatedAt)
+		assert.Equal(t, existingID, m.ConversationID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		c, m := newTestConversation()
+		testErr := errors.New("test error")
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(startConversationQuery).
+			WithArgs(c.ID, c.AdID, c.AdTitle, c.BuyerID, c.SellerID, c.CreatedAt, m.CreatedAt).
+			WillReturnRows(mock.NewRows([]string{"id", "created_at", "created"}).AddRow(c.ID, c.CreatedAt, true))
+		mock.ExpectExec(insertMessageQuery).
+			WithArgs(m.ID, c.ID, m.SenderID, m.Text, m.CreatedAt, m.ReadAt).
+			WillReturnError(testErr)
+		mock.ExpectRollback()
+
+		_, err := repo.Start(c, m)
+
+		assert.ErrorIs(t, err, testErr)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestConversationRepoPostgres_FindByID(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewConversationRepoPostgres(mock)
+	c, _ := newTestConversation()
+	query := selectConversationQuery + fromConversationsQuery + " WHERE c.id = $1"
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(c.ID).
+			WillReturnRows(mock.NewRows(conversationRows).AddRow(c.ID, c.AdID, c.AdTitle, c.BuyerID, "buyer",
+				c.SellerID, "seller", c.CreatedAt, c.LastMessageAt))
+
+		found, err := repo.FindByID(c.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, c.AdID, found.AdID)
+		assert.Equal(t, "buyer", found.BuyerUsername)
+		assert.Equal(t, "seller", found.SellerUsername)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(c.ID).
+			WillReturnError(pgx.ErrNoRows)
+
+		found, err := repo.FindByID(c.ID)
+
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectQuery(query).
+			WithArgs(c.ID).
+			WillReturnError(testErr)
+
+		found, err := repo.FindByID(c.ID)
+
+		assert.ErrorIs(t, err, testErr)
+		assert.Nil(t, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestConversationRepoPostgres_FindByAd(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewConversationRepoPostgres(mock)
+	c, _ := newTestConversation()
+	query := selectConversationQuery + fromConversationsQuery + " WHERE c.ad_id = $1 AND c.buyer_id = $2"
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(c.AdID, c.BuyerID).
+			WillReturnRows(mock.NewRows(conversationRows).AddRow(c.ID, c.AdID, c.AdTitle, c.BuyerID, "buyer",
+				c.SellerID, "seller", c.CreatedAt, c.LastMessageAt))
+
+		found, err := repo.FindByAd(c.AdID, c.BuyerID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, c.ID, found.ID)
+		assert.Equal(t, c.SellerID, found.SellerID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(c.AdID, c.BuyerID).
+			WillReturnError(pgx.ErrNoRows)
+
+		found, err := repo.FindByAd(c.AdID, c.BuyerID)
+
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestConversationRepoPostgres_FindByUser(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewConversationRepoPostgres(mock)
+	c, _ := newTestConversation()
+
+	mock.ExpectQuery(selectConversationQuery+", (SELECT COUNT(*) FROM messages m "+
+		"WHERE m.conversation_id = c.id AND m.sender_id <> $1 AND m.read_at IS NULL)"+fromConversationsQuery+
+		" WHERE c.buyer_id = $1 OR c.seller_id = $1 ORDER BY c.last_message_at DESC, c.id DESC LIMIT $2 OFFSET $3").
+		WithArgs(c.SellerID, 10, 10).
+		WillReturnRows(mock.NewRows(append(conversationRows, "unread")).AddRow(c.ID, c.AdID, c.AdTitle, c.BuyerID,
+			"buyer", c.SellerID, "seller", c.CreatedAt, c.LastMessageAt, int64(3)))
+
+	found, err := repo.FindByUser(c.SellerID, 10, 10)
+
+	assert.NoError(t, err)
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, c.ID, found[0].ID)
+		assert.Equal(t, int64(3), found[0].Unread)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConversationRepoPostgres_CountUnread(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewConversationRepoPostgres(mock)
+	userID := uuid.New()
+
+	mock.ExpectQuery("SELECT COUNT(*) FROM messages m JOIN conversations c ON c.id = m.conversation_id " +
+		"WHERE (c.buyer_id = $1 OR c.seller_id = $1) AND m.sender_id <> $1 AND m.read_at IS NULL").
+		WithArgs(userID).
+		WillReturnRows(mock.NewRows([]string{"count"}).AddRow(int64(5)))
+
+	count, err := repo.CountUnread(userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConversationRepoPostgres_CountMessagesByAds(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewConversationRepoPostgres(mock)
+	adIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mock.ExpectQuery("SELECT c.ad_id, COUNT(*) FROM messages m JOIN conversations c ON c.id = m.conversation_id " +
+		"WHERE c.ad_id = ANY($1) GROUP BY c.ad_id").
+		WithArgs(adIDs).
+		WillReturnRows(mock.NewRows([]string{"ad_id", "count"}).AddRow(adIDs[1], int64(7)))
+
+	counts, err := repo.CountMessagesByAds(adIDs)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]int64{adIDs[1]: 7}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConversationRepoPostgres_SaveMessage(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewConversationRepoPostgres(mock)
+	_, m := newTestConversation()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insertMessageQuery).
+		WithArgs(m.ID, m.ConversationID, m.SenderID, m.Text, m.CreatedAt, m.ReadAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("UPDATE conversations SET last_message_at = $1 WHERE id = $2").
+		WithArgs(m.CreatedAt, m.ConversationID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	err = repo.SaveMessage(m)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConversationRepoPostgres_FindMessages(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewConversationRepoPostgres(mock)
+	_, m := newTestConversation()
+	query := "SELECT id, conversation_id, sender_id, text, created_at, read_at FROM messages " +
+		"WHERE conversation_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3"
+	columns := []string{"id", "conversation_id", "sender_id", "text", "created_at", "read_at"}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(m.ConversationID, 20, 0).
+			WillReturnRows(mock.NewRows(columns).
+				AddRow(m.ID, m.ConversationID, m.SenderID, m.Text, m.CreatedAt, m.ReadAt))
+
+		found, err := repo.FindMessages(m.ConversationID, 20, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.Message{m}, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(m.ConversationID, 20, 40).
+			WillReturnRows(mock.NewRows(columns))
+
+		found, err := repo.FindMessages(m.ConversationID, 20, 40)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, found)
+		assert.Empty(t, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestConversationRepoPostgres_MarkRead(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewConversationRepoPostgres(mock)
+	conversationID, readerID := uuid.New(), uuid.New()
+	readAt := time.Now()
+
+	mock.ExpectExec("UPDATE messages SET read_at = $1 "+
+		"WHERE conversation_id = $2 AND sender_id <> $3 AND read_at IS NULL").
+		WithArgs(readAt, conversationID, readerID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+
+	err = repo.MarkRead(conversationID, readerID, readAt)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,133 @@
+package favorite
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PgxPool interface {
+	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
+	Query(context.Context, string, ...any) (pgx.Rows, error)
+	QueryRow(context.Context, string, ...any) pgx.Row
+}
+
+type FavoriteRepoPostgres struct {
+	db PgxPool
+}
+
+func NewFavoriteRepoPostgres(db PgxPool) *FavoriteRepoPostgres {
+	return &FavoriteRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *FavoriteRepoPostgres) Add(userID, adID uuid.UUID) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"INSERT INTO favorites (user_id, ad_id) VALUES ($1, $2) "+
+			"ON CONFLICT (user_id, ad_id) DO NOTHING",
+		userID, adID)
+
+	return err
+}
+
+func (r *FavoriteRepoPostgres) Remove(userID, adID uuid.UUID) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"DELETE FROM favorites WHERE user_id = $1 AND ad_id = $2",
+		userID, adID)
+
+	return err
+}
+
+func (r *FavoriteRepoPostgres) RemoveAd(adID uuid.UUID) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"DELETE FROM favorites WHERE ad_id = $1",
+		adID)
+
+	return err
+}
+
+func (r *FavoriteRepoPostgres) RemoveAds(adIDs []uuid.UUID) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"DELETE FROM favorites WHERE ad_id = ANY($1)",
+		adIDs)
+
+	return err
+}
+
+func (r *FavoriteRepoPostgres) CountByUser(userID uuid.UUID) (int64, error) {
+	var count int64
+
+	err := r.db.QueryRow(
+		context.Background(),
+		"SELECT COUNT(*) FROM favorites WHERE user_id = $1",
+		userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindAdIDs returns the IDs of all ads favorited by the user, most recent first.
+func (r *FavoriteRepoPostgres) FindAdIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	return r.queryIDs(
+		"SELECT ad_id FROM favorites WHERE user_id = $1 ORDER BY created_at DESC",
+		userID)
+}
+
+// FilterFavorites returns those of adIDs the user has favorited.
+func (r *FavoriteRepoPostgres) FilterFavorites(userID uuid.UUID, adIDs []uuid.UUID) ([]uuid.UUID, error) {
+	return r.queryIDs(
+		"SELECT ad_id FROM favorites WHERE user_id = $1 AND ad_id = ANY($2)",
+		userID, adIDs)
+}
+
+// Ads without favorites are left out.
+func (r *FavoriteRepoPostgres) CountByAds(adIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		"SELECT ad_id, COUNT(*) FROM favorites WHERE ad_id = ANY($1) GROUP BY ad_id",
+		adIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[uuid.UUID]int64)
+	for rows.Next() {
+		var adID uuid.UUID
+		var count int64
+		if err = rows.Scan(&adID, &count); err != nil {
+			return nil, err
+		}
+		counts[adID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *FavoriteRepoPostgres) queryIDs(query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,236 @@
+package favorite
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFavoriteRepoPostgres_Add(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewFavoriteRepoPostgres(mock)
+	userID, adID := uuid.New(), uuid.New()
+
+	mock.ExpectExec("INSERT INTO favorites (user_id, ad_id) VALUES ($1, $2) "+
+		"ON CONFLICT (user_id, ad_id) DO NOTHING").
+		WithArgs(userID, adID).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err = repo.Add(userID, adID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFavoriteRepoPostgres_Remove(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewFavoriteRepoPostgres(mock)
+	userID, adID := uuid.New(), uuid.New()
+
+	mock.ExpectExec("DELETE FROM favorites WHERE user_id = $1 AND ad_id = $2").
+		WithArgs(userID, adID).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	err = repo.Remove(userID, adID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFavoriteRepoPostgres_RemoveAd(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewFavoriteRepoPostgres(mock)
+	adID := uuid.New()
+
+	mock.ExpectExec("DELETE FROM favorites WHERE ad_id = $1").
+		WithArgs(adID).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+
+	err = repo.RemoveAd(adID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFavoriteRepoPostgres_RemoveAds(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewFavoriteRepoPostgres(mock)
+	adIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mock.ExpectExec("DELETE FROM favorites WHERE ad_id = ANY($1)").
+		WithArgs(adIDs).
+		WillReturnResult(pgxmock.NewResult("DELETE", 5))
+
+	err = repo.RemoveAds(adIDs)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFavoriteRepoPostgres_CountByUser(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewFavoriteRepoPostgres(mock)
+	userID := uuid.New()
+	query := "SELECT COUNT(*) FROM favorites WHERE user_id = $1"
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnRows(mock.NewRows([]string{"count"}).AddRow(int64(12)))
+
+		count, err := repo.CountByUser(userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12), count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnError(testErr)
+
+		count, err := repo.CountByUser(userID)
+
+		assert.ErrorIs(t, err, testErr)
+		assert.Zero(t, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFavoriteRepoPostgres_FindAdIDs(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewFavoriteRepoPostgres(mock)
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	query := "SELECT ad_id FROM favorites WHERE user_id = $1 ORDER BY created_at DESC"
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnRows(mock.NewRows([]string{"ad_id"}).AddRow(ids[0]).AddRow(ids[1]))
+
+		found, err := repo.FindAdIDs(userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ids, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnRows(mock.NewRows([]string{"ad_id"}))
+
+		found, err := repo.FindAdIDs(userID)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, found)
+		assert.Empty(t, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnError(testErr)
+
+		found, err := repo.FindAdIDs(userID)
+
+		assert.ErrorIs(t, err, testErr)
+		assert.Nil(t, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFavoriteRepoPostgres_FilterFavorites(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewFavoriteRepoPostgres(mock)
+	userID := uuid.New()
+	adIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	mock.ExpectQuery("SELECT ad_id FROM favorites WHERE user_id = $1 AND ad_id = ANY($2)").
+		WithArgs(userID, adIDs).
+		WillReturnRows(mock.NewRows([]string{"ad_id"}).AddRow(adIDs[1]))
+
+	found, err := repo.FilterFavorites(userID, adIDs)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{adIDs[1]}, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFavoriteRepoPostgres_CountByAds(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewFavoriteRepoPostgres(mock)
+	adIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery("SELECT ad_id, COUNT(*) FROM favorites WHERE ad_id = ANY($1) GROUP BY ad_id").
+			WithArgs(adIDs).
+			WillReturnRows(mock.NewRows([]string{"ad_id", "count"}).AddRow(adIDs[0], int64(3)))
+
+		counts, err := repo.CountByAds(adIDs)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[uuid.UUID]int64{adIDs[0]: 3}, counts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectQuery("SELECT ad_id, COUNT(*) FROM favorites WHERE ad_id = ANY($1) GROUP BY ad_id").
+			WithArgs(adIDs).
+			WillReturnError(testErr)
+
+		counts, err := repo.CountByAds(adIDs)
+
+		assert.ErrorIs(t, err, testErr)
+		assert.Nil(t, counts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,103 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrorImageNotFound       = errors.New("image not found")
+	ErrorFailedToSaveImage   = errors.New("failed to save image")
+	ErrorFailedToDeleteImage = errors.New("failed to delete image")
+)
+
+const collectionName = "images"
+
+type ImageRepoMongoDB struct {
+	collection *mongo.Collection
+}
+
+func NewImageRepoMongoDB(db *mongo.Database) *ImageRepoMongoDB {
+	return &ImageRepoMongoDB{
+		collection: db.Collection(collectionName),
+	}
+}
+
+func (r *ImageRepoMongoDB) Save(image *entity.Image) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.InsertOne(ctx, image); err != nil {
+		return ErrorFailedToSaveImage
+	}
+
+	return nil
+}
+
+func (r *ImageRepoMongoDB) FindByID(id uuid.UUID) (*entity.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var image entity.Image
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&image); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrorImageNotFound
+		}
+
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+// Imported external images have no stored original and are not matched.
+func (r *ImageRepoMongoDB) ExistsForOwner(id, ownerID uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"_id":      id,
+		"owner_id": ownerID,
+		"key":      bson.M{"$ne": ""},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *ImageRepoMongoDB) FindByOwner(ownerID uuid.UUID) ([]*entity.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"owner_id": ownerID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx) //nolint:errcheck
+
+	var images []*entity.Image
+	if err = cursor.All(ctx, &images); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+func (r *ImageRepoMongoDB) Delete(id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return ErrorFailedToDeleteImage
+	}
+
+	return nil
+}
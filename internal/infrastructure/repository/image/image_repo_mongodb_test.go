@@ -0,0 +1,181 @@
+package image
+
+import (
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"testing"
+)
+
+func TestImageRepoMongoDB_Save(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewImageRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		err := repo.Save(entity.NewImage(uuid.New(), "image/png", 100, 10, 10))
+
+		assert.NoError(t, err)
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewImageRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		err := repo.Save(entity.NewImage(uuid.New(), "image/png", 100, 10, 10))
+
+		assert.ErrorIs(t, err, ErrorFailedToSaveImage)
+	})
+}
+
+func TestImageRepoMongoDB_FindByID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewImageRepoMongoDB(mt.DB)
+		expected := &entity.Image{
+			ID:          uuid.New(),
+			OwnerID:     uuid.New(),
+			Key:         "images/test",
+			ContentType: "image/png",
+			Size:        100,
+		}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.images", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: expected.ID},
+			{Key: "owner_id", Value: expected.OwnerID},
+			{Key: "key", Value: expected.Key},
+			{Key: "content_type", Value: expected.ContentType},
+			{Key: "size", Value: expected.Size},
+		}))
+
+		found, err := repo.FindByID(expected.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, found)
+	})
+
+	mt.Run("Failure - not found", func(mt *mtest.T) {
+		repo := NewImageRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.images", mtest.FirstBatch))
+		found, err := repo.FindByID(uuid.New())
+
+		assert.ErrorIs(t, err, ErrorImageNotFound)
+		assert.Nil(t, found)
+	})
+
+	mt.Run("Failure - error in find command", func(mt *mtest.T) {
+		repo := NewImageRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		found, err := repo.FindByID(uuid.New())
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrorImageNotFound)
+		assert.Nil(t, found)
+	})
+}
+
+func TestImageRepoMongoDB_ExistsForOwner(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Exists", func(mt *mtest.T) {
+		repo := NewImageRepoMongoDB(mt.DB)
+		id, ownerID := uuid.New(), uuid.New()
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.images", mtest.FirstBatch, bson.D{
+			{Key: "n", Value: int32(1)},
+		}))
+
+		exists, err := repo.ExistsForOwner(id, ownerID)
+
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		match := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Index(0).Value().Document()
+		_, err = match.LookupErr("$match", "owner_id")
+		assert.NoError(t, err)
+	})
+
+	mt.Run("Does not exist", func(mt *mtest.T) {
+		repo := NewImageRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.images", mtest.FirstBatch))
+		exists, err := repo.ExistsForOwner(uuid.New(), uuid.New())
+
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewImageRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		exists, err := repo.ExistsForOwner(uuid.New(), uuid.New())
+
+		assert.Error(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestImageRepoMongoDB_FindByOwner(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewImageRepoMongoDB(mt.DB)
+		ownerID := uuid.New()
+		ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.images", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: ids[0]}, {Key: "owner_id", Value: ownerID}, {Key: "key", Value: "images/a"}},
+			bson.D{{Key: "_id", Value: ids[1]}, {Key: "owner_id", Value: ownerID}},
+		))
+
+		images, err := repo.FindByOwner(ownerID)
+
+		assert.NoError(t, err)
+		assert.Len(t, images, 2)
+		assert.Equal(t, ids[0], images[0].ID)
+		assert.Equal(t, "images/a", images[0].Key)
+		assert.Equal(t, ids[1], images[1].ID)
+
+		_, data := mt.GetStartedEvent().Command.Lookup("filter", "owner_id").Binary()
+		assert.Equal(t, ownerID[:], data)
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewImageRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		images, err := repo.FindByOwner(uuid.New())
+
+		assert.Error(t, err)
+		assert.Nil(t, images)
+	})
+}
+
+func TestImageRepoMongoDB_Delete(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(mt *mtest.T) {
+		repo := NewImageRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+		err := repo.Delete(uuid.New())
+
+		assert.NoError(t, err)
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		repo := NewImageRepoMongoDB(mt.DB)
+
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{}))
+		err := repo.Delete(uuid.New())
+
+		assert.ErrorIs(t, err, ErrorFailedToDeleteImage)
+	})
+}
@@ -0,0 +1,118 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const notificationColumns = "id, user_id, type, saved_search_id, search_name, ad_id, ad_title, created_at, read_at"
+
+type PgxPool interface {
+	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
+	Query(context.Context, string, ...any) (pgx.Rows, error)
+	QueryRow(context.Context, string, ...any) pgx.Row
+	Begin(context.Context) (pgx.Tx, error)
+}
+
+type NotificationRepoPostgres struct {
+	db PgxPool
+}
+
+func NewNotificationRepoPostgres(db PgxPool) *NotificationRepoPostgres {
+	return &NotificationRepoPostgres{
+		db: db,
+	}
+}
+
+// SaveAll skips search matches that are already stored.
+func (r *NotificationRepoPostgres) SaveAll(notifications []*entity.Notification) error {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	for _, n := range notifications {
+		_, err = tx.Exec(ctx,
+			"INSERT INTO notifications ("+notificationColumns+") "+
+				"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) "+
+				"ON CONFLICT (saved_search_id, ad_id) DO NOTHING",
+			n.ID, n.UserID, n.Type, n.SavedSearchID, n.SearchName, n.AdID, n.AdTitle, n.CreatedAt, n.ReadAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *NotificationRepoPostgres) FindByUser(userID uuid.UUID, unreadOnly bool,
+	limit, offset int) ([]*entity.Notification, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		"SELECT "+notificationColumns+" FROM notifications "+
+			"WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL) "+
+			"ORDER BY created_at DESC, id DESC LIMIT $3 OFFSET $4",
+		userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]*entity.Notification, 0, limit)
+	for rows.Next() {
+		var n entity.Notification
+		err = rows.Scan(&n.ID, &n.UserID, &n.Type, &n.SavedSearchID, &n.SearchName, &n.AdID, &n.AdTitle,
+			&n.CreatedAt, &n.ReadAt)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, &n)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (r *NotificationRepoPostgres) CountByUser(userID uuid.UUID, unreadOnly bool) (int64, error) {
+	var count int64
+
+	err := r.db.QueryRow(
+		context.Background(),
+		"SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)",
+		userID, unreadOnly).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MarkRead keeps the first read time and reports false if there is no such
+// notification.
+func (r *NotificationRepoPostgres) MarkRead(id, userID uuid.UUID, readAt time.Time) (bool, error) {
+	tag, err := r.db.Exec(
+		context.Background(),
+		"UPDATE notifications SET read_at = COALESCE(read_at, $1) WHERE id = $2 AND user_id = $3",
+		readAt, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *NotificationRepoPostgres) MarkAllRead(userID uuid.UUID, readAt time.Time) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"UPDATE notifications SET read_at = $1 WHERE user_id = $2 AND read_at IS NULL",
+		readAt, userID)
+
+	return err
+}
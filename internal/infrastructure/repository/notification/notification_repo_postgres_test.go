@@ -0,0 +1,169 @@
+package notification
+
+import (
+	"errors"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+const insertNotificationQuery = "INSERT INTO notifications (id, user_id, type, saved_search_id, search_name, " +
+	"ad_id, ad_title, created_at, read_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) " +
+	"ON CONFLICT (saved_search_id, ad_id) DO NOTHING"
+
+func newTestNotification() *entity.Notification {
+	search := &entity.SavedSearch{ID: uuid.New(), UserID: uuid.New(), Name: "bikes"}
+	ad := &entity.Ad{ID: uuid.New(), Title: "Red bike"}
+
+	return entity.NewSearchMatchNotification(search, ad)
+}
+
+func TestNotificationRepoPostgres_SaveAll(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewNotificationRepoPostgres(mock)
+	first, second := newTestNotification(), newTestNotification()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		for _, n := range []*entity.Notification{first, second} {
+			mock.ExpectExec(insertNotificationQuery).
+				WithArgs(n.ID, n.UserID, n.Type, n.SavedSearchID, n.SearchName, n.AdID, n.AdTitle, n.CreatedAt, n.ReadAt).
+				WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		}
+		mock.ExpectCommit()
+
+		err := repo.SaveAll([]*entity.Notification{first, second})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectBegin()
+		mock.ExpectExec(insertNotificationQuery).
+			WithArgs(first.ID, first.UserID, first.Type, first.SavedSearchID, first.SearchName, first.AdID,
+				first.AdTitle, first.CreatedAt, first.ReadAt).
+			WillReturnError(testErr)
+		mock.ExpectRollback()
+
+		err := repo.SaveAll([]*entity.Notification{first, second})
+
+		assert.ErrorIs(t, err, testErr)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestNotificationRepoPostgres_FindByUser(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewNotificationRepoPostgres(mock)
+	n := newTestNotification()
+	readAt := time.Now()
+	n.ReadAt = &readAt
+
+	mock.ExpectQuery("SELECT id, user_id, type, saved_search_id, search_name, ad_id, ad_title, created_at, read_at "+
+		"FROM notifications WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL) "+
+		"ORDER BY created_at DESC, id DESC LIMIT $3 OFFSET $4").
+		WithArgs(n.UserID, false, 10, 20).
+		WillReturnRows(mock.NewRows([]string{
+			"id", "user_id", "type", "saved_search_id", "search_name", "ad_id", "ad_title", "created_at", "read_at",
+		}).AddRow(n.ID, n.UserID, n.Type, n.SavedSearchID, n.SearchName, n.AdID, n.AdTitle, n.CreatedAt, n.ReadAt))
+
+	notifications, err := repo.FindByUser(n.UserID, false, 10, 20)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.Notification{n}, notifications)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationRepoPostgres_CountByUser(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewNotificationRepoPostgres(mock)
+	userID := uuid.New()
+
+	mock.ExpectQuery("SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)").
+		WithArgs(userID, true).
+		WillReturnRows(mock.NewRows([]string{"count"}).AddRow(int64(3)))
+
+	count, err := repo.CountByUser(userID, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationRepoPostgres_MarkRead(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewNotificationRepoPostgres(mock)
+	id, userID := uuid.New(), uuid.New()
+	readAt := time.Now()
+	query := "UPDATE notifications SET read_at = COALESCE(read_at, $1) WHERE id = $2 AND user_id = $3"
+
+	t.Run("Found", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(readAt, id, userID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		found, err := repo.MarkRead(id, userID, readAt)
+
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(readAt, id, userID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		found, err := repo.MarkRead(id, userID, readAt)
+
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestNotificationRepoPostgres_MarkAllRead(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewNotificationRepoPostgres(mock)
+	userID := uuid.New()
+	readAt := time.Now()
+
+	mock.ExpectExec("UPDATE notifications SET read_at = $1 WHERE user_id = $2 AND read_at IS NULL").
+		WithArgs(readAt, userID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 5))
+
+	err = repo.MarkAllRead(userID, readAt)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
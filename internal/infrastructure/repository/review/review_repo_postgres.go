@@ -0,0 +1,89 @@
+package review
+
+import (
+	"context"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PgxPool interface {
+	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
+	Query(context.Context, string, ...any) (pgx.Rows, error)
+	QueryRow(context.Context, string, ...any) pgx.Row
+}
+
+type ReviewRepoPostgres struct {
+	db PgxPool
+}
+
+func NewReviewRepoPostgres(db PgxPool) *ReviewRepoPostgres {
+	return &ReviewRepoPostgres{
+		db: db,
+	}
+}
+
+// Save reports false if the reviewer has already reviewed the ad.
+func (r *ReviewRepoPostgres) Save(review *entity.Review) (bool, error) {
+	tag, err := r.db.Exec(
+		context.Background(),
+		"INSERT INTO reviews (id, ad_id, ad_title, reviewer_id, seller_id, rating, text, created_at) "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (ad_id, reviewer_id) DO NOTHING",
+		review.ID, review.AdID, review.AdTitle, review.ReviewerID, review.SellerID, review.Rating, review.Text,
+		review.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *ReviewRepoPostgres) FindBySeller(sellerID uuid.UUID, limit, offset int) ([]*entity.Review, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		"SELECT r.id, r.ad_id, r.ad_title, r.reviewer_id, u.username, r.seller_id, r.rating, r.text, r.created_at "+
+			"FROM reviews r JOIN users u ON u.id = r.reviewer_id WHERE r.seller_id = $1 "+
+			"ORDER BY r.created_at DESC, r.id DESC LIMIT $2 OFFSET $3",
+		sellerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := make([]*entity.Review, 0, limit)
+	for rows.Next() {
+		var rv entity.Review
+		err = rows.Scan(&rv.ID, &rv.AdID, &rv.AdTitle, &rv.ReviewerID, &rv.ReviewerUsername, &rv.SellerID,
+			&rv.Rating, &rv.Text, &rv.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, &rv)
+	}
+
+	return reviews, rows.Err()
+}
+
+func (r *ReviewRepoPostgres) FindRatings(userIDs []uuid.UUID) ([]*entity.Rating, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		"SELECT user_id, review_count, rating_sum FROM seller_ratings WHERE user_id = ANY($1) AND review_count > 0",
+		userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := make([]*entity.Rating, 0, len(userIDs))
+	for rows.Next() {
+		var rating entity.Rating
+		if err = rows.Scan(&rating.UserID, &rating.Count, &rating.Sum); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, &rating)
+	}
+
+	return ratings, rows.Err()
+}
@@ -0,0 +1,140 @@
+package review
+
+import (
+	"errors"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var reviewRows = []string{"id", "ad_id", "ad_title", "reviewer_id", "username", "seller_id", "rating", "text",
+	"created_at"}
+
+func newTestReview() *entity.Review {
+	return entity.NewReview(&entity.Conversation{
+		ID:            uuid.New(),
+		AdID:          uuid.New(),
+		AdTitle:       "Bike",
+		BuyerID:       uuid.New(),
+		BuyerUsername: "buyer",
+		SellerID:      uuid.New(),
+	}, 4, "Friendly seller")
+}
+
+func TestReviewRepoPostgres_Save(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewReviewRepoPostgres(mock)
+	review := newTestReview()
+	query := "INSERT INTO reviews (id, ad_id, ad_title, reviewer_id, seller_id, rating, text, created_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (ad_id, reviewer_id) DO NOTHING"
+
+	t.Run("Created", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(review.ID, review.AdID, review.AdTitle, review.ReviewerID, review.SellerID, review.Rating,
+				review.Text, review.CreatedAt).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		created, err := repo.Save(review)
+
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(review.ID, review.AdID, review.AdTitle, review.ReviewerID, review.SellerID, review.Rating,
+				review.Text, review.CreatedAt).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+		created, err := repo.Save(review)
+
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectExec(query).
+			WithArgs(review.ID, review.AdID, review.AdTitle, review.ReviewerID, review.SellerID, review.Rating,
+				review.Text, review.CreatedAt).
+			WillReturnError(testErr)
+
+		created, err := repo.Save(review)
+
+		assert.ErrorIs(t, err, testErr)
+		assert.False(t, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReviewRepoPostgres_FindBySeller(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewReviewRepoPostgres(mock)
+	review := newTestReview()
+	query := "SELECT r.id, r.ad_id, r.ad_title, r.reviewer_id, u.username, r.seller_id, r.rating, r.text, " +
+		"r.created_at FROM reviews r JOIN users u ON u.id = r.reviewer_id WHERE r.seller_id = $1 " +
+		"ORDER BY r.created_at DESC, r.id DESC LIMIT $2 OFFSET $3"
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(review.SellerID, 10, 20).
+			WillReturnRows(mock.NewRows(reviewRows).AddRow(review.ID, review.AdID, review.AdTitle, review.ReviewerID,
+				"buyer", review.SellerID, 4, review.Text, review.CreatedAt))
+
+		found, err := repo.FindBySeller(review.SellerID, 10, 20)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.Review{review}, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectQuery(query).
+			WithArgs(review.SellerID, 10, 0).
+			WillReturnError(testErr)
+
+		found, err := repo.FindBySeller(review.SellerID, 10, 0)
+
+		assert.ErrorIs(t, err, testErr)
+		assert.Nil(t, found)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReviewRepoPostgres_FindRatings(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewReviewRepoPostgres(mock)
+	userIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mock.ExpectQuery("SELECT user_id, review_count, rating_sum FROM seller_ratings " +
+		"WHERE user_id = ANY($1) AND review_count > 0").
+		WithArgs(userIDs).
+		WillReturnRows(mock.NewRows([]string{"user_id", "review_count", "rating_sum"}).
+			AddRow(userIDs[1], int64(3), int64(13)))
+
+	ratings, err := repo.FindRatings(userIDs)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.Rating{{UserID: userIDs[1], Count: 3, Sum: 13}}, ratings)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,111 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const savedSearchColumns = "id, user_id, name, query, min_price, max_price, category_id, sort_by, order_by, " +
+	"last_run_at, created_at"
+
+type PgxPool interface {
+	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
+	Query(context.Context, string, ...any) (pgx.Rows, error)
+	QueryRow(context.Context, string, ...any) pgx.Row
+}
+
+type SavedSearchRepoPostgres struct {
+	db PgxPool
+}
+
+func NewSavedSearchRepoPostgres(db PgxPool) *SavedSearchRepoPostgres {
+	return &SavedSearchRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *SavedSearchRepoPostgres) Save(search *entity.SavedSearch) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"INSERT INTO saved_searches ("+savedSearchColumns+") "+
+			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)",
+		search.ID, search.UserID, search.Name, search.Query, search.MinPrice, search.MaxPrice,
+		search.CategoryID, search.SortBy, search.OrderBy, search.LastRunAt, search.CreatedAt)
+
+	return err
+}
+
+func (r *SavedSearchRepoPostgres) FindByUser(userID uuid.UUID) ([]*entity.SavedSearch, error) {
+	return r.query(
+		"SELECT "+savedSearchColumns+" FROM saved_searches WHERE user_id = $1 ORDER BY created_at DESC",
+		userID)
+}
+
+func (r *SavedSearchRepoPostgres) CountByUser(userID uuid.UUID) (int64, error) {
+	var count int64
+
+	err := r.db.QueryRow(
+		context.Background(),
+		"SELECT COUNT(*) FROM saved_searches WHERE user_id = $1",
+		userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *SavedSearchRepoPostgres) Delete(id, userID uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(
+		context.Background(),
+		"DELETE FROM saved_searches WHERE id = $1 AND user_id = $2",
+		id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// FindDue pages through the due searches in the order of their IDs.
+func (r *SavedSearchRepoPostgres) FindDue(before time.Time, afterID uuid.UUID, limit int) ([]*entity.SavedSearch, error) {
+	return r.query(
+		"SELECT "+savedSearchColumns+" FROM saved_searches "+
+			"WHERE last_run_at < $1 AND id > $2 ORDER BY id LIMIT $3",
+		before, afterID, limit)
+}
+
+func (r *SavedSearchRepoPostgres) SetLastRunAt(id uuid.UUID, lastRunAt time.Time) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"UPDATE saved_searches SET last_run_at = $1 WHERE id = $2",
+		lastRunAt, id)
+
+	return err
+}
+
+func (r *SavedSearchRepoPostgres) query(query string, args ...any) ([]*entity.SavedSearch, error) {
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	searches := make([]*entity.SavedSearch, 0)
+	for rows.Next() {
+		var s entity.SavedSearch
+		err = rows.Scan(&s.ID, &s.UserID, &s.Name, &s.Query, &s.MinPrice, &s.MaxPrice,
+			&s.CategoryID, &s.SortBy, &s.OrderBy, &s.LastRunAt, &s.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, &s)
+	}
+
+	return searches, rows.Err()
+}
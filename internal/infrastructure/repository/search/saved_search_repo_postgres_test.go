@@ -0,0 +1,198 @@
+package search
+
+import (
+	"errors"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var savedSearchRowColumns = []string{
+	"id", "user_id", "name", "query", "min_price", "max_price", "category_id", "sort_by", "order_by",
+	"last_run_at", "created_at",
+}
+
+func newTestSavedSearch() *entity.SavedSearch {
+	now := time.Now().UTC()
+
+	return &entity.SavedSearch{
+		ID:         uuid.New(),
+		UserID:     uuid.New(),
+		Name:       "bikes",
+		Query:      "bike",
+		MinPrice:   100,
+		MaxPrice:   500,
+		CategoryID: 7,
+		SortBy:     entity.SortByPrice,
+		OrderBy:    entity.OrderByAsc,
+		LastRunAt:  now,
+		CreatedAt:  now,
+	}
+}
+
+func TestSavedSearchRepoPostgres_Save(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewSavedSearchRepoPostgres(mock)
+	s := newTestSavedSearch()
+
+	mock.ExpectExec("INSERT INTO saved_searches (id, user_id, name, query, min_price, max_price, category_id, "+
+		"sort_by, order_by, last_run_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)").
+		WithArgs(s.ID, s.UserID, s.Name, s.Query, s.MinPrice, s.MaxPrice, s.CategoryID, s.SortBy, s.OrderBy,
+			s.LastRunAt, s.CreatedAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err = repo.Save(s)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchRepoPostgres_FindByUser(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewSavedSearchRepoPostgres(mock)
+	s := newTestSavedSearch()
+	query := "SELECT id, user_id, name, query, min_price, max_price, category_id, sort_by, order_by, " +
+		"last_run_at, created_at FROM saved_searches WHERE user_id = $1 ORDER BY created_at DESC"
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(s.UserID).
+			WillReturnRows(mock.NewRows(savedSearchRowColumns).AddRow(
+				s.ID, s.UserID, s.Name, s.Query, s.MinPrice, s.MaxPrice, s.CategoryID, s.SortBy, s.OrderBy,
+				s.LastRunAt, s.CreatedAt))
+
+		searches, err := repo.FindByUser(s.UserID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entity.SavedSearch{s}, searches)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectQuery(query).
+			WithArgs(s.UserID).
+			WillReturnError(testErr)
+
+		searches, err := repo.FindByUser(s.UserID)
+
+		assert.ErrorIs(t, err, testErr)
+		assert.Nil(t, searches)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSavedSearchRepoPostgres_CountByUser(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewSavedSearchRepoPostgres(mock)
+	userID := uuid.New()
+
+	mock.ExpectQuery("SELECT COUNT(*) FROM saved_searches WHERE user_id = $1").
+		WithArgs(userID).
+		WillReturnRows(mock.NewRows([]string{"count"}).AddRow(int64(4)))
+
+	count, err := repo.CountByUser(userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchRepoPostgres_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewSavedSearchRepoPostgres(mock)
+	id, userID := uuid.New(), uuid.New()
+	query := "DELETE FROM saved_searches WHERE id = $1 AND user_id = $2"
+
+	t.Run("Deleted", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(id, userID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+		deleted, err := repo.Delete(id, userID)
+
+		assert.NoError(t, err)
+		assert.True(t, deleted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(id, userID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+		deleted, err := repo.Delete(id, userID)
+
+		assert.NoError(t, err)
+		assert.False(t, deleted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSavedSearchRepoPostgres_FindDue(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewSavedSearchRepoPostgres(mock)
+	before := time.Now()
+	afterID := uuid.New()
+
+	mock.ExpectQuery("SELECT id, user_id, name, query, min_price, max_price, category_id, sort_by, order_by, "+
+		"last_run_at, created_at FROM saved_searches WHERE last_run_at < $1 AND id > $2 ORDER BY id LIMIT $3").
+		WithArgs(before, afterID, 100).
+		WillReturnRows(mock.NewRows(savedSearchRowColumns))
+
+	searches, err := repo.FindDue(before, afterID, 100)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, searches)
+	assert.Empty(t, searches)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedSearchRepoPostgres_SetLastRunAt(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewSavedSearchRepoPostgres(mock)
+	id := uuid.New()
+	lastRunAt := time.Now()
+
+	mock.ExpectExec("UPDATE saved_searches SET last_run_at = $1 WHERE id = $2").
+		WithArgs(lastRunAt, id).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err = repo.SetLastRunAt(id, lastRunAt)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
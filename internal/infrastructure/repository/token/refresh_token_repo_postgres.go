@@ -0,0 +1,105 @@
+package token
+
+import (
+	"context"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PgxPool interface {
+	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
+	QueryRow(context.Context, string, ...any) pgx.Row
+	Begin(context.Context) (pgx.Tx, error)
+}
+
+type RefreshTokenRepoPostgres struct {
+	db PgxPool
+}
+
+func NewRefreshTokenRepoPostgres(db PgxPool) *RefreshTokenRepoPostgres {
+	return &RefreshTokenRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *RefreshTokenRepoPostgres) Save(token *entity.RefreshToken) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at) "+
+			"VALUES ($1, $2, $3, $4, $5, $6)",
+		token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+
+	return err
+}
+
+func (r *RefreshTokenRepoPostgres) GetByHash(hash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+
+	err := r.db.QueryRow(
+		context.Background(),
+		"SELECT id, user_id, family_id, token_hash, expires_at, created_at, revoked_at "+
+			"FROM refresh_tokens WHERE token_hash = $1",
+		hash).Scan(&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash,
+		&token.ExpiresAt, &token.CreatedAt, &token.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Rotate reports false without saving next if oldID has already been revoked.
+func (r *RefreshTokenRepoPostgres) Rotate(oldID uuid.UUID, next *entity.RefreshToken) (bool, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	tag, err := tx.Exec(ctx,
+		"UPDATE refresh_tokens SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL",
+		oldID)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at) "+
+			"VALUES ($1, $2, $3, $4, $5, $6)",
+		next.ID, next.UserID, next.FamilyID, next.TokenHash, next.ExpiresAt, next.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *RefreshTokenRepoPostgres) RevokeFamily(familyID uuid.UUID) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"UPDATE refresh_tokens SET revoked_at = now() WHERE family_id = $1 AND revoked_at IS NULL",
+		familyID)
+
+	return err
+}
+
+func (r *RefreshTokenRepoPostgres) RevokeAllForUser(userID uuid.UUID) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"UPDATE refresh_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL",
+		userID)
+
+	return err
+}
@@ -0,0 +1,192 @@
+package token
+
+import (
+	"errors"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+const (
+	insertQuery = "INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6)"
+	revokeQuery = "UPDATE refresh_tokens SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL"
+)
+
+func newTestToken() *entity.RefreshToken {
+	now := time.Now().UTC()
+
+	return &entity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		TokenHash: "3f79bb7b435b05321651daefd374cdc681dc06faa65e374e38337b88ca046dea",
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	}
+}
+
+func TestRefreshTokenRepoPostgres_Save(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewRefreshTokenRepoPostgres(mock)
+	token := newTestToken()
+
+	mock.ExpectExec(insertQuery).
+		WithArgs(token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err = repo.Save(token)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepoPostgres_GetByHash(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewRefreshTokenRepoPostgres(mock)
+	token := newTestToken()
+	query := "SELECT id, user_id, family_id, token_hash, expires_at, created_at, revoked_at " +
+		"FROM refresh_tokens WHERE token_hash = $1"
+
+	t.Run("Success", func(t *testing.T) {
+		rows := mock.NewRows([]string{"id", "user_id", "family_id", "token_hash", "expires_at", "created_at", "revoked_at"}).
+			AddRow(token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt, nil)
+
+		mock.ExpectQuery(query).
+			WithArgs(token.TokenHash).
+			WillReturnRows(rows)
+
+		got, err := repo.GetByHash(token.TokenHash)
+
+		assert.NoError(t, err)
+		assert.Equal(t, token, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(token.TokenHash).
+			WillReturnError(pgx.ErrNoRows)
+
+		got, err := repo.GetByHash(token.TokenHash)
+
+		assert.Nil(t, got)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRefreshTokenRepoPostgres_Rotate(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewRefreshTokenRepoPostgres(mock)
+	oldID := uuid.New()
+	next := newTestToken()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(revokeQuery).
+			WithArgs(oldID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec(insertQuery).
+			WithArgs(next.ID, next.UserID, next.FamilyID, next.TokenHash, next.ExpiresAt, next.CreatedAt).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		rotated, err := repo.Rotate(oldID, next)
+
+		assert.NoError(t, err)
+		assert.True(t, rotated)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("AlreadyRevoked", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(revokeQuery).
+			WithArgs(oldID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		mock.ExpectRollback()
+
+		rotated, err := repo.Rotate(oldID, next)
+
+		assert.NoError(t, err)
+		assert.False(t, rotated)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("InsertFailure", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectBegin()
+		mock.ExpectExec(revokeQuery).
+			WithArgs(oldID).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec(insertQuery).
+			WithArgs(next.ID, next.UserID, next.FamilyID, next.TokenHash, next.ExpiresAt, next.CreatedAt).
+			WillReturnError(testErr)
+		mock.ExpectRollback()
+
+		rotated, err := repo.Rotate(oldID, next)
+
+		assert.ErrorIs(t, err, testErr)
+		assert.False(t, rotated)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRefreshTokenRepoPostgres_RevokeFamily(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewRefreshTokenRepoPostgres(mock)
+	familyID := uuid.New()
+
+	mock.ExpectExec("UPDATE refresh_tokens SET revoked_at = now() WHERE family_id = $1 AND revoked_at IS NULL").
+		WithArgs(familyID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 3))
+
+	err = repo.RevokeFamily(familyID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepoPostgres_RevokeAllForUser(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewRefreshTokenRepoPostgres(mock)
+	userID := uuid.New()
+
+	mock.ExpectExec("UPDATE refresh_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL").
+		WithArgs(userID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+
+	err = repo.RevokeAllForUser(userID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
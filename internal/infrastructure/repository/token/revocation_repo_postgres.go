@@ -0,0 +1,69 @@
+package token
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RevocationRepoPostgres struct {
+	db PgxPool
+}
+
+func NewRevocationRepoPostgres(db PgxPool) *RevocationRepoPostgres {
+	return &RevocationRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *RevocationRepoPostgres) Revoke(jti, userID uuid.UUID, expiresAt time.Time) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"INSERT INTO revoked_tokens (jti, user_id, expires_at) VALUES ($1, $2, $3) "+
+			"ON CONFLICT (jti) DO NOTHING",
+		jti, userID, expiresAt)
+
+	return err
+}
+
+func (r *RevocationRepoPostgres) RevokeAllForUser(userID uuid.UUID, revokedBefore, expiresAt time.Time) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"INSERT INTO user_token_revocations (user_id, revoked_before, expires_at) "+
+			"SELECT id, $2::timestamptz, $3::timestamptz FROM users WHERE id = $1 "+
+			"ON CONFLICT (user_id) DO UPDATE "+
+			"SET revoked_before = EXCLUDED.revoked_before, expires_at = EXCLUDED.expires_at",
+		userID, revokedBefore, expiresAt)
+
+	return err
+}
+
+// The tokens of a deleted user count as revoked.
+func (r *RevocationRepoPostgres) IsRevoked(jti, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	var revoked bool
+
+	err := r.db.QueryRow(
+		context.Background(),
+		"SELECT NOT EXISTS (SELECT 1 FROM users WHERE id = $2) "+
+			"OR EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1) "+
+			"OR EXISTS (SELECT 1 FROM user_token_revocations WHERE user_id = $2 AND revoked_before >= $3)",
+		jti, userID, issuedAt).Scan(&revoked)
+	if err != nil {
+		return false, err
+	}
+
+	return revoked, nil
+}
+
+func (r *RevocationRepoPostgres) DeleteExpired(now time.Time) error {
+	ctx := context.Background()
+
+	if _, err := r.db.Exec(ctx, "DELETE FROM revoked_tokens WHERE expires_at < $1", now); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(ctx, "DELETE FROM user_token_revocations WHERE expires_at < $1", now)
+
+	return err
+}
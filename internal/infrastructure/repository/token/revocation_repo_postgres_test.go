@@ -0,0 +1,120 @@
+package token
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRevocationRepoPostgres_Revoke(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewRevocationRepoPostgres(mock)
+	jti, userID := uuid.New(), uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+
+	mock.ExpectExec("INSERT INTO revoked_tokens (jti, user_id, expires_at) VALUES ($1, $2, $3) "+
+		"ON CONFLICT (jti) DO NOTHING").
+		WithArgs(jti, userID, expiresAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err = repo.Revoke(jti, userID, expiresAt)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevocationRepoPostgres_RevokeAllForUser(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewRevocationRepoPostgres(mock)
+	userID := uuid.New()
+	revokedBefore := time.Now()
+	expiresAt := revokedBefore.Add(time.Hour)
+
+	mock.ExpectExec("INSERT INTO user_token_revocations (user_id, revoked_before, expires_at) "+
+		"SELECT id, $2::timestamptz, $3::timestamptz FROM users WHERE id = $1 "+
+		"ON CONFLICT (user_id) DO UPDATE "+
+		"SET revoked_before = EXCLUDED.revoked_before, expires_at = EXCLUDED.expires_at").
+		WithArgs(userID, revokedBefore, expiresAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err = repo.RevokeAllForUser(userID, revokedBefore, expiresAt)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevocationRepoPostgres_IsRevoked(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewRevocationRepoPostgres(mock)
+	jti, userID := uuid.New(), uuid.New()
+	issuedAt := time.Now()
+	query := "SELECT NOT EXISTS (SELECT 1 FROM users WHERE id = $2) " +
+		"OR EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1) " +
+		"OR EXISTS (SELECT 1 FROM user_token_revocations WHERE user_id = $2 AND revoked_before >= $3)"
+
+	t.Run("Revoked", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(jti, userID, issuedAt).
+			WillReturnRows(mock.NewRows([]string{"exists"}).AddRow(true))
+
+		revoked, err := repo.IsRevoked(jti, userID, issuedAt)
+
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectQuery(query).
+			WithArgs(jti, userID, issuedAt).
+			WillReturnError(testErr)
+
+		revoked, err := repo.IsRevoked(jti, userID, issuedAt)
+
+		assert.ErrorIs(t, err, testErr)
+		assert.False(t, revoked)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRevocationRepoPostgres_DeleteExpired(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewRevocationRepoPostgres(mock)
+	now := time.Now()
+
+	mock.ExpectExec("DELETE FROM revoked_tokens WHERE expires_at < $1").
+		WithArgs(now).
+		WillReturnResult(pgxmock.NewResult("DELETE", 4))
+	mock.ExpectExec("DELETE FROM user_token_revocations WHERE expires_at < $1").
+		WithArgs(now).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	err = repo.DeleteExpired(now)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
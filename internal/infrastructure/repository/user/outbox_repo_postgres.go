@@ -0,0 +1,81 @@
+package user
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+)
+
+type OutboxRepoPostgres struct {
+	db PgxPool
+}
+
+func NewOutboxRepoPostgres(db PgxPool) *OutboxRepoPostgres {
+	return &OutboxRepoPostgres{
+		db: db,
+	}
+}
+
+// Only the oldest live event of a user is due, so a user's events stay in order.
+func (r *OutboxRepoPostgres) Claim(limit int, lease time.Duration) ([]*entity.OutboxEvent, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		"UPDATE outbox SET attempts = attempts + 1, run_after = now() + $2 * interval '1 second' "+
+			"WHERE id IN (SELECT o.id FROM outbox o "+
+			"WHERE o.dead_at IS NULL AND o.run_after <= now() AND NOT EXISTS "+
+			"(SELECT 1 FROM outbox p WHERE p.user_id = o.user_id AND p.dead_at IS NULL AND p.id < o.id) "+
+			"ORDER BY o.id LIMIT $1 FOR UPDATE SKIP LOCKED) "+
+			"RETURNING id, type, user_id, payload, attempts, created_at",
+		limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*entity.OutboxEvent, 0, limit)
+	for rows.Next() {
+		var event entity.OutboxEvent
+		err = rows.Scan(&event.ID, &event.Type, &event.UserID, &event.Payload, &event.Attempts, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the subquery.
+	slices.SortFunc(events, func(a, b *entity.OutboxEvent) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return events, nil
+}
+
+func (r *OutboxRepoPostgres) Delete(id int64) error {
+	_, err := r.db.Exec(context.Background(), "DELETE FROM outbox WHERE id = $1", id)
+
+	return err
+}
+
+func (r *OutboxRepoPostgres) Retry(id int64, delay time.Duration, lastError string) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"UPDATE outbox SET run_after = now() + $2 * interval '1 second', last_error = $3 WHERE id = $1",
+		id, delay.Seconds(), lastError)
+
+	return err
+}
+
+func (r *OutboxRepoPostgres) DeadLetter(id int64, lastError string) error {
+	_, err := r.db.Exec(
+		context.Background(),
+		"UPDATE outbox SET dead_at = now(), last_error = $2 WHERE id = $1",
+		id, lastError)
+
+	return err
+}
@@ -0,0 +1,124 @@
+package user
+
+import (
+	"errors"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+const claimOutboxQuery = "UPDATE outbox SET attempts = attempts + 1, run_after = now() + $2 * interval '1 second' " +
+	"WHERE id IN (SELECT o.id FROM outbox o " +
+	"WHERE o.dead_at IS NULL AND o.run_after <= now() AND NOT EXISTS " +
+	"(SELECT 1 FROM outbox p WHERE p.user_id = o.user_id AND p.dead_at IS NULL AND p.id < o.id) " +
+	"ORDER BY o.id LIMIT $1 FOR UPDATE SKIP LOCKED) " +
+	"RETURNING id, type, user_id, payload, attempts, created_at"
+
+func TestOutboxRepoPostgres_Claim(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewOutboxRepoPostgres(mock)
+	userID := uuid.New()
+	createdAt := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(claimOutboxQuery).
+			WithArgs(20, float64(300)).
+			WillReturnRows(mock.NewRows([]string{"id", "type", "user_id", "payload", "attempts", "created_at"}).
+				AddRow(int64(7), entity.OutboxUserDeleted, uuid.New(), []byte(`{}`), 1, createdAt).
+				AddRow(int64(3), entity.OutboxUserRenamed, userID, []byte(`{"username": "renamed"}`), 2,
+					createdAt))
+
+		events, err := repo.Claim(20, 5*time.Minute)
+
+		assert.NoError(t, err)
+		if assert.Len(t, events, 2) {
+			assert.Equal(t, &entity.OutboxEvent{
+				ID:        3,
+				Type:      entity.OutboxUserRenamed,
+				UserID:    userID,
+				Payload:   []byte(`{"username": "renamed"}`),
+				Attempts:  2,
+				CreatedAt: createdAt,
+			}, events[0])
+			assert.Equal(t, int64(7), events[1].ID)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		testErr := errors.New("test error")
+		mock.ExpectQuery(claimOutboxQuery).
+			WithArgs(20, float64(300)).
+			WillReturnError(testErr)
+
+		_, err := repo.Claim(20, 5*time.Minute)
+
+		assert.ErrorIs(t, err, testErr)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOutboxRepoPostgres_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewOutboxRepoPostgres(mock)
+
+	mock.ExpectExec("DELETE FROM outbox WHERE id = $1").
+		WithArgs(int64(3)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	err = repo.Delete(3)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepoPostgres_Retry(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewOutboxRepoPostgres(mock)
+
+	mock.ExpectExec("UPDATE outbox SET run_after = now() + $2 * interval '1 second', last_error = $3 WHERE id = $1").
+		WithArgs(int64(3), float64(40), "failed to update ad").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err = repo.Retry(3, 40*time.Second, "failed to update ad")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepoPostgres_DeadLetter(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewOutboxRepoPostgres(mock)
+
+	mock.ExpectExec("UPDATE outbox SET dead_at = now(), last_error = $2 WHERE id = $1").
+		WithArgs(int64(3), "failed to update ad").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err = repo.DeadLetter(3, "failed to update ad")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
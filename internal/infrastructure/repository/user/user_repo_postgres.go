@@ -2,6 +2,8 @@ package user
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/alishashelby/marketplace/internal/domain/entity"
 	"github.com/google/uuid"
@@ -9,9 +11,15 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+var ErrorUserNotFound = errors.New("user not found")
+
+const uniqueViolation = "23505"
+
 type PgxPool interface {
 	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
+	Query(context.Context, string, ...any) (pgx.Rows, error)
 	QueryRow(context.Context, string, ...any) pgx.Row
+	Begin(context.Context) (pgx.Tx, error)
 	Close()
 	Ping(context.Context) error
 }
@@ -29,8 +37,8 @@ func NewUserRepoPostgres(db PgxPool) *UserRepoPostgres {
 func (r *UserRepoPostgres) Save(user *entity.User) error {
 	_, err := r.db.Exec(
 		context.Background(),
-		"INSERT INTO users (id, username, password) VALUES ($1, $2, $3)",
-		user.ID, user.Username, user.Password)
+		"INSERT INTO users (id, username, password, role, created_at) VALUES ($1, $2, $3, $4, $5)",
+		user.ID, user.Username, user.Password, user.Role, user.CreatedAt)
 
 	return err
 }
@@ -40,8 +48,8 @@ func (r *UserRepoPostgres) GetByUsername(username string) (*entity.User, error)
 
 	err := r.db.QueryRow(
 		context.Background(),
-		"SELECT id, username, password FROM users WHERE username = $1",
-		username).Scan(&user.ID, &user.Username, &user.Password)
+		"SELECT id, username, password, role, banned_at, created_at FROM users WHERE username = $1",
+		username).Scan(&user.ID, &user.Username, &user.Password, &user.Role, &user.BannedAt, &user.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -54,11 +62,119 @@ func (r *UserRepoPostgres) GetByID(id uuid.UUID) (*entity.User, error) {
 
 	err := r.db.QueryRow(
 		context.Background(),
-		"SELECT id, username, password FROM users WHERE id = $1",
-		id).Scan(&user.ID, &user.Username, &user.Password)
+		"SELECT id, username, password, role, banned_at, created_at FROM users WHERE id = $1",
+		id).Scan(&user.ID, &user.Username, &user.Password, &user.Role, &user.BannedAt, &user.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 
 	return &user, nil
 }
+
+func (r *UserRepoPostgres) FindAll(limit, offset int) ([]*entity.User, error) {
+	rows, err := r.db.Query(
+		context.Background(),
+		"SELECT id, username, password, role, banned_at, created_at FROM users ORDER BY username LIMIT $1 OFFSET $2",
+		limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*entity.User, 0, limit)
+	for rows.Next() {
+		var user entity.User
+		if err = rows.Scan(&user.ID, &user.Username, &user.Password, &user.Role, &user.BannedAt, &user.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *UserRepoPostgres) Count() (int64, error) {
+	var count int64
+
+	err := r.db.QueryRow(context.Background(), "SELECT COUNT(*) FROM users").Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *UserRepoPostgres) SetBannedAt(id uuid.UUID, bannedAt *time.Time) error {
+	tag, err := r.db.Exec(
+		context.Background(),
+		"UPDATE users SET banned_at = $1 WHERE id = $2",
+		bannedAt, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrorUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateUsername reports false if another user has the username.
+func (r *UserRepoPostgres) UpdateUsername(id uuid.UUID, username string) (bool, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	tag, err := tx.Exec(ctx, "UPDATE users SET username = $1 WHERE id = $2", username, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return false, nil
+		}
+
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, ErrorUserNotFound
+	}
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO outbox (type, user_id, payload) VALUES ($1, $2, jsonb_build_object('username', $3::text))",
+		entity.OutboxUserRenamed, id, username)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+func (r *UserRepoPostgres) Delete(id uuid.UUID) (bool, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	tag, err := tx.Exec(ctx, "DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO outbox (type, user_id) VALUES ($1, $2)",
+		entity.OutboxUserDeleted, id)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
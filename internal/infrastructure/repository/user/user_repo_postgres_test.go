@@ -5,9 +5,11 @@ import (
 	"github.com/alishashelby/marketplace/internal/domain/entity"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestUserRepoPostgres_Save(t *testing.T) {
@@ -19,14 +21,16 @@ func TestUserRepoPostgres_Save(t *testing.T) {
 
 	repo := NewUserRepoPostgres(mock)
 	testUser := &entity.User{
-		ID:       uuid.New(),
-		Username: "testUser",
-		Password: "test",
+		ID:        uuid.New(),
+		Username:  "testUser",
+		Password:  "test",
+		Role:      entity.RoleUser,
+		CreatedAt: time.Now(),
 	}
 
 	t.Run("Success", func(t *testing.T) {
 		mock.ExpectExec("INSERT INTO users").
-			WithArgs(testUser.ID, testUser.Username, testUser.Password).
+			WithArgs(testUser.ID, testUser.Username, testUser.Password, testUser.Role, testUser.CreatedAt).
 			WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 		err = repo.Save(testUser)
@@ -38,7 +42,7 @@ func TestUserRepoPostgres_Save(t *testing.T) {
 	t.Run("Failure", func(t *testing.T) {
 		testErr := errors.New("test error")
 		mock.ExpectExec("INSERT INTO users").
-			WithArgs(testUser.ID, testUser.Username, testUser.Password).
+			WithArgs(testUser.ID, testUser.Username, testUser.Password, testUser.Role, testUser.CreatedAt).
 			WillReturnError(testErr)
 
 		err = repo.Save(testUser)
@@ -59,16 +63,19 @@ func TestUserRepoPostgres_GetByUsername(t *testing.T) {
 
 	repo := NewUserRepoPostgres(mock)
 	testUser := &entity.User{
-		ID:       uuid.New(),
-		Username: "testUser",
-		Password: "test",
+		ID:        uuid.New(),
+		Username:  "testUser",
+		Password:  "test",
+		Role:      entity.RoleUser,
+		CreatedAt: time.Now(),
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		rows := mock.NewRows([]string{"id", "username", "password"}).
-			AddRow(testUser.ID, testUser.Username, testUser.Password)
+		rows := mock.NewRows([]string{"id", "username", "password", "role", "banned_at", "created_at"}).
+			AddRow(testUser.ID, testUser.Username, testUser.Password, testUser.Role, testUser.BannedAt,
+				testUser.CreatedAt)
 
-		mock.ExpectQuery("SELECT id, username, password FROM users WHERE username = $1").
+		mock.ExpectQuery("SELECT id, username, password, role, banned_at, created_at FROM users WHERE username = $1").
 			WithArgs(testUser.Username).
 			WillReturnRows(rows)
 
@@ -80,7 +87,7 @@ func TestUserRepoPostgres_GetByUsername(t *testing.T) {
 	})
 
 	t.Run("Failure - not found", func(t *testing.T) {
-		mock.ExpectQuery("SELECT id, username, password FROM users WHERE username = $1").
+		mock.ExpectQuery("SELECT id, username, password, role, banned_at, created_at FROM users WHERE username = $1").
 			WithArgs(testUser.Username).
 			WillReturnError(pgx.ErrNoRows)
 
@@ -94,7 +101,7 @@ func TestUserRepoPostgres_GetByUsername(t *testing.T) {
 
 	t.Run("Failure - database error", func(t *testing.T) {
 		testErr := errors.New("test error")
-		mock.ExpectQuery("SELECT id, username, password FROM users WHERE username = $1").
+		mock.ExpectQuery("SELECT id, username, password, role, banned_at, created_at FROM users WHERE username = $1").
 			WithArgs(testUser.Username).
 			WillReturnError(testErr)
 
@@ -117,15 +124,18 @@ func TestUserRepoPostgres_GetByID(t *testing.T) {
 
 	repo := NewUserRepoPostgres(mock)
 	testUser := &entity.User{
-		ID:       uuid.New(),
-		Username: "testUser",
-		Password: "test",
+		ID:        uuid.New(),
+		Username:  "testUser",
+		Password:  "test",
+		Role:      entity.RoleUser,
+		CreatedAt: time.Now(),
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		rows := mock.NewRows([]string{"id", "username", "password"}).
-			AddRow(testUser.ID, testUser.Username, testUser.Password)
-		mock.ExpectQuery("SELECT id, username, password FROM users WHERE id = $1").
+		rows := mock.NewRows([]string{"id", "username", "password", "role", "banned_at", "created_at"}).
+			AddRow(testUser.ID, testUser.Username, testUser.Password, testUser.Role, testUser.BannedAt,
+				testUser.CreatedAt)
+		mock.ExpectQuery("SELECT id, username, password, role, banned_at, created_at FROM users WHERE id = $1").
 			WithArgs(testUser.ID).
 			WillReturnRows(rows)
 
@@ -137,7 +147,7 @@ func TestUserRepoPostgres_GetByID(t *testing.T) {
 	})
 
 	t.Run("Failure - not found", func(t *testing.T) {
-		mock.ExpectQuery("SELECT id, username, password FROM users WHERE id = $1").
+		mock.ExpectQuery("SELECT id, username, password, role, banned_at, created_at FROM users WHERE id = $1").
 			WithArgs(testUser.ID).
 			WillReturnError(pgx.ErrNoRows)
 
@@ -151,7 +161,7 @@ func TestUserRepoPostgres_GetByID(t *testing.T) {
 
 	t.Run("Failure - database error", func(t *testing.T) {
 		testErr := errors.New("test error")
-		mock.ExpectQuery("SELECT id, username, password FROM users WHERE id = $1").
+		mock.ExpectQuery("SELECT id, username, password, role, banned_at, created_at FROM users WHERE id = $1").
 			WithArgs(testUser.ID).
 			WillReturnError(testErr)
 
@@ -163,3 +173,226 @@ func TestUserRepoPostgres_GetByID(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestUserRepoPostgres_FindAll(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewUserRepoPostgres(mock)
+	bannedAt := time.Now()
+	testUsers := []*entity.User{
+		{ID: uuid.New(), Username: "alice", Password: "test", Role: entity.RoleAdmin,
+			CreatedAt: time.Now()},
+		{ID: uuid.New(), Username: "bob", Password: "test", Role: entity.RoleUser, BannedAt: &bannedAt,
+			CreatedAt: time.Now()},
+	}
+
+	rows := mock.NewRows([]string{"id", "username", "password", "role", "banned_at", "created_at"})
+	for _, u := range testUsers {
+		rows.AddRow(u.ID, u.Username, u.Password, u.Role, u.BannedAt, u.CreatedAt)
+	}
+
+	mock.ExpectQuery("SELECT id, username, password, role, banned_at, created_at FROM users ORDER BY username LIMIT $1 OFFSET $2").
+		WithArgs(10, 20).
+		WillReturnRows(rows)
+
+	users, err := repo.FindAll(10, 20)
+
+	assert.NoError(t, err)
+	assert.Equal(t, testUsers, users)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepoPostgres_Count(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewUserRepoPostgres(mock)
+
+	mock.ExpectQuery("SELECT COUNT(*) FROM users").
+		WillReturnRows(mock.NewRows([]string{"count"}).AddRow(int64(3)))
+
+	count, err := repo.Count()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepoPostgres_SetBannedAt(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewUserRepoPostgres(mock)
+	id := uuid.New()
+	bannedAt := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users SET banned_at = $1 WHERE id = $2").
+			WithArgs(&bannedAt, id).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		err = repo.SetBannedAt(id, &bannedAt)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure - not found", func(t *testing.T) {
+		mock.ExpectExec("UPDATE users SET banned_at = $1 WHERE id = $2").
+			WithArgs((*time.Time)(nil), id).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		err = repo.SetBannedAt(id, nil)
+
+		assert.ErrorIs(t, err, ErrorUserNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepoPostgres_UpdateUsername(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewUserRepoPostgres(mock)
+	id := uuid.New()
+	updateQuery := "UPDATE users SET username = $1 WHERE id = $2"
+	outboxQuery := "INSERT INTO outbox (type, user_id, payload) VALUES ($1, $2, jsonb_build_object('username', $3::text))"
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(updateQuery).
+			WithArgs("renamed", id).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec(outboxQuery).
+			WithArgs(entity.OutboxUserRenamed, id, "renamed").
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		updated, err := repo.UpdateUsername(id, "renamed")
+
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Taken", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(updateQuery).
+			WithArgs("taken", id).
+			WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+		mock.ExpectRollback()
+
+		updated, err := repo.UpdateUsername(id, "taken")
+
+		assert.NoError(t, err)
+		assert.False(t, updated)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure - not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(updateQuery).
+			WithArgs("renamed", id).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		mock.ExpectRollback()
+
+		_, err := repo.UpdateUsername(id, "renamed")
+
+		assert.ErrorIs(t, err, ErrorUserNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure - outbox", func(t *testing.T) {
+		testErr := errors.New("test error")
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateQuery).
+			WithArgs("renamed", id).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectExec(outboxQuery).
+			WithArgs(entity.OutboxUserRenamed, id, "renamed").
+			WillReturnError(testErr)
+		mock.ExpectRollback()
+
+		_, err := repo.UpdateUsername(id, "renamed")
+
+		assert.ErrorIs(t, err, testErr)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepoPostgres_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := NewUserRepoPostgres(mock)
+	id := uuid.New()
+	deleteQuery := "DELETE FROM users WHERE id = $1"
+	outboxQuery := "INSERT INTO outbox (type, user_id) VALUES ($1, $2)"
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteQuery).
+			WithArgs(id).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectExec(outboxQuery).
+			WithArgs(entity.OutboxUserDeleted, id).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectCommit()
+
+		deleted, err := repo.Delete(id)
+
+		assert.NoError(t, err)
+		assert.True(t, deleted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure - not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteQuery).
+			WithArgs(id).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectRollback()
+
+		deleted, err := repo.Delete(id)
+
+		assert.NoError(t, err)
+		assert.False(t, deleted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure - outbox", func(t *testing.T) {
+		testErr := errors.New("test error")
+
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteQuery).
+			WithArgs(id).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectExec(outboxQuery).
+			WithArgs(entity.OutboxUserDeleted, id).
+			WillReturnError(testErr)
+		mock.ExpectRollback()
+
+		deleted, err := repo.Delete(id)
+
+		assert.ErrorIs(t, err, testErr)
+		assert.False(t, deleted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
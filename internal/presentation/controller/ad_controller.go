@@ -3,42 +3,70 @@ package controller
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/alishashelby/marketplace/internal/application/dto"
 	"github.com/alishashelby/marketplace/internal/application/service"
 	"github.com/alishashelby/marketplace/internal/application/validator"
 	"github.com/alishashelby/marketplace/internal/domain/entity"
 	"github.com/alishashelby/marketplace/internal/infrastructure/repository/ad"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/category"
 	"github.com/alishashelby/marketplace/pkg"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 )
 
 const (
 	unauthorizedError = "invalid or missing user ID"
+	pathParamID       = "id"
+	pathParamUsername = "username"
+	nextCursorHeader  = "X-Next-Cursor"
+	paramEnvelope     = "envelope"
+	paramStatus       = "status"
+
+	reportInvalidStatus = "status must be one of %v"
 )
 
 type AdController struct {
-	adService   *service.AdService
-	userService *service.UserService
-	validator   *validator.AdValidator
+	adService           *service.AdService
+	userService         *service.UserService
+	categoryService     *service.CategoryService
+	imageService        *service.ImageService
+	verificationService *service.AdVerificationService
+	favoriteService     *service.FavoriteService
+	conversationService *service.ConversationService
+	reviewService       *service.ReviewService
+	validator           *validator.AdValidator
 }
 
-func NewAdController(adService *service.AdService,
-	userService *service.UserService, validator *validator.AdValidator) *AdController {
+func NewAdController(adService *service.AdService, userService *service.UserService,
+	categoryService *service.CategoryService, imageService *service.ImageService,
+	verificationService *service.AdVerificationService, favoriteService *service.FavoriteService,
+	conversationService *service.ConversationService, reviewService *service.ReviewService,
+	validator *validator.AdValidator) *AdController {
 	return &AdController{
-		adService:   adService,
-		userService: userService,
-		validator:   validator,
+		adService:           adService,
+		userService:         userService,
+		categoryService:     categoryService,
+		imageService:        imageService,
+		verificationService: verificationService,
+		favoriteService:     favoriteService,
+		conversationService: conversationService,
+		reviewService:       reviewService,
+		validator:           validator,
 	}
 }
 
 // CreateAd godoc
 //
 //	@Summary		Create a new advertisement
-//	@Description	Publishes a new ad for the authenticated user
+//	@Description	Publishes a new ad for the authenticated user. Images are given as an ordered list of external URLs or IDs of images uploaded via /api/images, cover is the index of the one shown in listings. Older clients may send a single image_url or image_id instead. An ad with external images is pending until they have been downloaded and checked in the background, then it becomes active or rejected. A draft is kept unlisted until it is published via /api/ads/{id}/publish
 //	@Tags			Ads
 //	@Security		BearerAuth
 //	@Accept			json
@@ -58,15 +86,15 @@ func (ac *AdController) CreateAd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	errs := ac.validator.Validate(adDTO)
-	if errs != nil {
+	userID, authErr := ac.getIDFromToken(r)
+
+	if errs := ac.validator.Validate(adDTO, userID); errs != nil {
 		pkg.SendValidationError(w, http.StatusBadRequest, errs)
 		return
 	}
 
-	userID, err := ac.getIDFromToken(r)
-	if err != nil {
-		pkg.SendError(w, http.StatusUnauthorized, err.Error())
+	if authErr != nil {
+		pkg.SendError(w, http.StatusUnauthorized, authErr.Error())
 		return
 	}
 
@@ -79,34 +107,48 @@ func (ac *AdController) CreateAd(w http.ResponseWriter, r *http.Request) {
 	newAdd := entity.NewAd(
 		adDTO.Title,
 		adDTO.Text,
-		adDTO.ImageURL,
+		ac.adImages(adDTO.ImageList(), nil),
+		adDTO.Cover,
 		adDTO.Price,
+		adDTO.CategoryID,
 		user,
 	)
+	if adDTO.Draft {
+		newAdd.MakeDraft()
+	}
 
 	if err = ac.adService.Create(newAdd); err != nil {
 		pkg.SendError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	if newAdd.Status == entity.AdStatusPending {
+		ac.verificationService.Enqueue(newAdd.ID)
+	}
 
 	adResp := dto.NewAdResponse(newAdd)
+	adResp.ProcessOwner(newAdd, userID)
 	pkg.SendJSON(w, http.StatusCreated, adResp)
 }
 
 // GetAdsWithOwned godoc
 //
 //	@Summary		Get ads with ownership info
-//	@Description	Returns a list of advertisements with ownership flag for the authenticated user
+//	@Description	Returns a list of advertisements with ownership and favorite flags for the authenticated user
 //	@Tags			Ads
 //	@Security		BearerAuth
 //	@Produce		json
-//	@Param			page		query		int		false	"Page number"						default(1)
-//	@Param			limit		query		int		false	"Items per page"					default(10)	minimum(1)	maximum(40)
-//	@Param			sortBy		query		string	false	"Sort field (created_at, price)"	default(created_at)
-//	@Param			orderBy		query		int		false	"Order (1 asc, -1 desc)"			default(-1)
+//	@Param			page		query		int		false	"Page number"		default(1)
+//	@Param			limit		query		int		false	"Items per page"	default(10)	minimum(1)	maximum(40)
+//	@Param			q			query		string	false	"Full-text search over title and text"
+//	@Param			sortBy		query		string	false	"Sort field (created_at, price, relevance)"	default(created_at)
+//	@Param			orderBy		query		int		false	"Order (1 asc, -1 desc)"					default(-1)
 //	@Param			minPrice	query		number	false	"Minimum price"
 //	@Param			maxPrice	query		number	false	"Maximum price"
+//	@Param			category	query		int		false	"Category ID, descendant categories are included"
+//	@Param			cursor		query		string	false	"Opaque cursor from X-Next-Cursor, replaces page"
+//	@Param			envelope	query		bool	false	"Wrap the list into dto.AdListResponse with total count and links; an empty page is then returned with 200 instead of 404"
 //	@Success		200			{array}		dto.AdResponse
+//	@Header			200			{string}	X-Next-Cursor		"Cursor of the next page, set when the page is full"
 //	@Failure		400			{object}	pkg.ErrorResponse	"Invalid query params"
 //	@Failure		401			{object}	pkg.ErrorResponse	"Unauthorized"
 //	@Failure		404			{object}	pkg.ErrorResponse	"No ads found"
@@ -121,7 +163,7 @@ func (ac *AdController) GetAdsWithOwned(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	ac.getAds(w, r, userID)
+	ac.getAds(w, r, userID, nil)
 }
 
 // GetAllAds godoc
@@ -130,13 +172,18 @@ func (ac *AdController) GetAdsWithOwned(w http.ResponseWriter, r *http.Request)
 //	@Description	Returns a list of all published ads
 //	@Tags			Ads
 //	@Produce		json
-//	@Param			page		query		int		false	"Page number"						default(1)
-//	@Param			limit		query		int		false	"Items per page"					default(10)	minimum(1)	maximum(40)
-//	@Param			sortBy		query		string	false	"Sort field (created_at, price)"	default(created_at)
-//	@Param			orderBy		query		int		false	"Order (1 asc, -1 desc)"			default(-1)
+//	@Param			page		query		int		false	"Page number"		default(1)
+//	@Param			limit		query		int		false	"Items per page"	default(10)	minimum(1)	maximum(40)
+//	@Param			q			query		string	false	"Full-text search over title and text"
+//	@Param			sortBy		query		string	false	"Sort field (created_at, price, relevance)"	default(created_at)
+//	@Param			orderBy		query		int		false	"Order (1 asc, -1 desc)"					default(-1)
 //	@Param			minPrice	query		number	false	"Minimum price"
 //	@Param			maxPrice	query		number	false	"Maximum price"
+//	@Param			category	query		int		false	"Category ID, descendant categories are included"
+//	@Param			cursor		query		string	false	"Opaque cursor from X-Next-Cursor, replaces page"
+//	@Param			envelope	query		bool	false	"Wrap the list into dto.AdListResponse with total count and links; an empty page is then returned with 200 instead of 404"
 //	@Success		200			{array}		dto.AdResponse
+//	@Header			200			{string}	X-Next-Cursor		"Cursor of the next page, set when the page is full"
 //	@Failure		400			{object}	pkg.ErrorResponse	"Invalid query params"
 //	@Failure		404			{object}	pkg.ErrorResponse	"No ads found"
 //	@Failure		500			{object}	pkg.ErrorResponse	"Internal server error"
@@ -144,7 +191,571 @@ func (ac *AdController) GetAdsWithOwned(w http.ResponseWriter, r *http.Request)
 func (ac *AdController) GetAllAds(w http.ResponseWriter, r *http.Request) {
 	log.Print("AdController.GetAllAds called")
 
-	ac.getAds(w, r, uuid.Nil)
+	ac.getAds(w, r, uuid.Nil, nil)
+}
+
+// GetMyAds godoc
+//
+//	@Summary		Get own ads
+//	@Description	Returns the ads of the authenticated user in any state, with their status and their views, favorites and messages. The filters and sorting are those of /api/ads
+//	@Tags			Ads
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			status		query		string	false	"Only ads in this state"	Enums(draft, pending, active, rejected, expired)
+//	@Param			page		query		int		false	"Page number"				default(1)
+//	@Param			limit		query		int		false	"Items per page"			default(10)	minimum(1)	maximum(40)
+//	@Param			q			query		string	false	"Full-text search over title and text"
+//	@Param			sort_by		query		string	false	"Sort field (created_at, price, relevance)"	default(created_at)
+//	@Param			order_by	query		int		false	"Order (1 asc, -1 desc)"					default(-1)
+//	@Param			min_price	query		number	false	"Minimum price"
+//	@Param			max_price	query		number	false	"Maximum price"
+//	@Param			category	query		int		false	"Category ID, descendant categories are included"
+//	@Param			cursor		query		string	false	"Opaque cursor from X-Next-Cursor, replaces page"
+//	@Param			envelope	query		bool	false	"Wrap the list into dto.AdListResponse with total count and links; an empty page is then returned with 200 instead of 404"
+//	@Success		200			{array}		dto.AdResponse
+//	@Header			200			{string}	X-Next-Cursor		"Cursor of the next page, set when the page is full"
+//	@Failure		400			{object}	pkg.ErrorResponse	"Invalid query params"
+//	@Failure		401			{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	pkg.ErrorResponse	"No ads found"
+//	@Failure		500			{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/me/ads [get]
+func (ac *AdController) GetMyAds(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdController.GetMyAds called")
+
+	userID, err := ac.getIDFromToken(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	statuses := entity.AdStatuses
+	if statusStr := r.URL.Query().Get(paramStatus); statusStr != "" {
+		status := entity.AdStatus(statusStr)
+		if !slices.Contains(entity.AdStatuses, status) {
+			pkg.SendError(w, http.StatusBadRequest, fmt.Sprintf(reportInvalidStatus, entity.AdStatuses))
+			return
+		}
+		statuses = []entity.AdStatus{status}
+	}
+
+	ac.getAds(w, r, userID, func(ops *entity.Options) error {
+		ops.AuthorID = userID
+		ops.Statuses = statuses
+
+		return nil
+	})
+}
+
+// GetUserAds godoc
+//
+//	@Summary		Get ads of a user
+//	@Description	Returns the published ads of a user with the filters and sorting of /api/ads; is_owner and is_favorite are set when the caller is authenticated
+//	@Tags			Ads
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Param			page		query		int		false	"Page number"		default(1)
+//	@Param			limit		query		int		false	"Items per page"	default(10)	minimum(1)	maximum(40)
+//	@Param			q			query		string	false	"Full-text search over title and text"
+//	@Param			sort_by		query		string	false	"Sort field (created_at, price, relevance)"	default(created_at)
+//	@Param			order_by	query		int		false	"Order (1 asc, -1 desc)"					default(-1)
+//	@Param			min_price	query		number	false	"Minimum price"
+//	@Param			max_price	query		number	false	"Maximum price"
+//	@Param			category	query		int		false	"Category ID, descendant categories are included"
+//	@Param			cursor		query		string	false	"Opaque cursor from X-Next-Cursor, replaces page"
+//	@Param			envelope	query		bool	false	"Wrap the list into dto.AdListResponse with total count and links; an empty page is then returned with 200 instead of 404"
+//	@Success		200			{array}		dto.AdResponse
+//	@Header			200			{string}	X-Next-Cursor		"Cursor of the next page, set when the page is full"
+//	@Failure		400			{object}	pkg.ErrorResponse	"Invalid query params"
+//	@Failure		404			{object}	pkg.ErrorResponse	"User or ads not found"
+//	@Failure		500			{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/users/{username}/ads [get]
+func (ac *AdController) GetUserAds(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdController.GetUserAds called")
+
+	author, err := ac.userService.GetByUsername(mux.Vars(r)[pathParamUsername])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	userID, _ := ac.getIDFromToken(r)
+
+	ac.getAds(w, r, userID, func(ops *entity.Options) error {
+		ops.AuthorID = author.ID
+
+		return nil
+	})
+}
+
+// GetAdByID godoc
+//
+//	@Summary		Get ad by ID
+//	@Description	Returns a single advertisement with the rating of its author; is_owner and is_favorite are set when the caller is authenticated. Pending and rejected ads are only shown to their author, together with the status and rejection reason
+//	@Tags			Ads
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Ad ID"	format(uuid)
+//	@Success		200	{object}	dto.AdResponse
+//	@Failure		404	{object}	pkg.ErrorResponse	"Ad not found"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/ads/{id} [get]
+func (ac *AdController) GetAdByID(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdController.GetAdByID called")
+
+	adID, err := ac.getAdIDFromPath(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	foundAd, err := ac.adService.GetByID(adID)
+	if err != nil {
+		ac.handleAdError(w, err)
+		return
+	}
+
+	resp := dto.NewAdResponse(foundAd)
+	userID, authErr := ac.getIDFromToken(r)
+	if authErr == nil {
+		resp.ProcessOwner(foundAd, userID)
+	}
+
+	if foundAd.Status != entity.AdStatusActive && !resp.IsOwner {
+		pkg.SendError(w, http.StatusNotFound, ad.ErrorAdNotFound.Error())
+		return
+	}
+
+	if !resp.IsOwner {
+		// A lost view only understates the stats.
+		if err = ac.adService.RecordView(foundAd.ID); err != nil {
+			log.Printf("failed to count a view of ad %s: %v", foundAd.ID, err)
+		}
+	}
+
+	if authErr == nil {
+		favorites, err := ac.favoriteService.Favorites(userID, []uuid.UUID{foundAd.ID})
+		if err != nil {
+			pkg.SendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		_, resp.IsFavorite = favorites[foundAd.ID]
+	}
+
+	rating, err := ac.reviewService.Rating(foundAd.Author.ID)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp.SetSellerRating(rating)
+
+	pkg.SendJSON(w, http.StatusOK, resp)
+}
+
+// UpdateAd godoc
+//
+//	@Summary		Replace an advertisement
+//	@Description	Replaces all fields of an ad owned by the authenticated user. New external images put the ad back to pending until they have been verified
+//	@Tags			Ads
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string		true	"Ad ID"	format(uuid)
+//	@Param			ad	body		dto.AdDTO	true	"Ad data"
+//	@Success		200	{object}	dto.AdResponse
+//	@Failure		400	{object}	pkg.ValidationErrorResponse	"Validation error"
+//	@Failure		401	{object}	pkg.ErrorResponse			"Unauthorized"
+//	@Failure		403	{object}	pkg.ErrorResponse			"Not the author of the ad"
+//	@Failure		404	{object}	pkg.ErrorResponse			"Ad not found"
+//	@Failure		500	{object}	pkg.ErrorResponse			"Internal server error"
+//	@Router			/api/ads/{id} [put]
+func (ac *AdController) UpdateAd(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdController.UpdateAd called")
+
+	var adDTO dto.AdDTO
+	if err := json.NewDecoder(r.Body).Decode(&adDTO); err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ac.editAd(w, r, func(dto.AdDTO) dto.AdDTO {
+		return adDTO
+	})
+}
+
+// PatchAd godoc
+//
+//	@Summary		Partially update an advertisement
+//	@Description	Updates only the given fields of an ad owned by the authenticated user. New external images put the ad back to pending until they have been verified
+//	@Tags			Ads
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string			true	"Ad ID"	format(uuid)
+//	@Param			ad	body		dto.AdPatchDTO	true	"Fields to update"
+//	@Success		200	{object}	dto.AdResponse
+//	@Failure		400	{object}	pkg.ValidationErrorResponse	"Validation error"
+//	@Failure		401	{object}	pkg.ErrorResponse			"Unauthorized"
+//	@Failure		403	{object}	pkg.ErrorResponse			"Not the author of the ad"
+//	@Failure		404	{object}	pkg.ErrorResponse			"Ad not found"
+//	@Failure		500	{object}	pkg.ErrorResponse			"Internal server error"
+//	@Router			/api/ads/{id} [patch]
+func (ac *AdController) PatchAd(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdController.PatchAd called")
+
+	var patchDTO dto.AdPatchDTO
+	if err := json.NewDecoder(r.Body).Decode(&patchDTO); err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ac.editAd(w, r, patchDTO.Apply)
+}
+
+// PublishAd godoc
+//
+//	@Summary		Publish an advertisement
+//	@Description	Lists a draft or an expired ad owned by the authenticated user. An ad with external images that have not been verified yet is pending until they have been checked
+//	@Tags			Ads
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Ad ID"	format(uuid)
+//	@Success		200	{object}	dto.AdResponse
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	pkg.ErrorResponse	"Not the author of the ad"
+//	@Failure		404	{object}	pkg.ErrorResponse	"Ad not found"
+//	@Failure		409	{object}	pkg.ErrorResponse	"Ad is neither a draft nor expired"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/ads/{id}/publish [post]
+func (ac *AdController) PublishAd(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdController.PublishAd called")
+
+	adID, err := ac.getAdIDFromPath(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	userID, err := ac.getIDFromToken(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	published, err := ac.adService.Publish(adID, userID)
+	if err != nil {
+		ac.handleAdError(w, err)
+		return
+	}
+	if published.Status == entity.AdStatusPending {
+		ac.verificationService.Enqueue(published.ID)
+	}
+
+	resp := dto.NewAdResponse(published)
+	resp.ProcessOwner(published, userID)
+	pkg.SendJSON(w, http.StatusOK, resp)
+}
+
+// DeleteAd godoc
+//
+//	@Summary		Delete an advertisement
+//	@Description	Deletes an ad owned by the authenticated user
+//	@Tags			Ads
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Ad ID"	format(uuid)
+//	@Success		204
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	pkg.ErrorResponse	"Not the author of the ad"
+//	@Failure		404	{object}	pkg.ErrorResponse	"Ad not found"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/ads/{id} [delete]
+func (ac *AdController) DeleteAd(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdController.DeleteAd called")
+
+	adID, err := ac.getAdIDFromPath(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	userID, err := ac.getIDFromToken(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err = ac.adService.Delete(adID, userID); err != nil {
+		ac.handleAdError(w, err)
+		return
+	}
+	removeFavorites(ac.favoriteService, adID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddFavorite godoc
+//
+//	@Summary		Favorite an advertisement
+//	@Description	Adds a listed ad to the favorites of the authenticated user. Favoriting an ad twice is not an error
+//	@Tags			Favorites
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Ad ID"	format(uuid)
+//	@Success		204
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	pkg.ErrorResponse	"Ad not found"
+//	@Failure		409	{object}	pkg.ErrorResponse	"Too many favorites"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/ads/{id}/favorite [post]
+func (ac *AdController) AddFavorite(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdController.AddFavorite called")
+
+	adID, err := ac.getAdIDFromPath(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	userID, err := ac.getIDFromToken(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	foundAd, err := ac.adService.GetByID(adID)
+	if err != nil {
+		ac.handleAdError(w, err)
+		return
+	}
+	if foundAd.Status != entity.AdStatusActive {
+		pkg.SendError(w, http.StatusNotFound, ad.ErrorAdNotFound.Error())
+		return
+	}
+
+	if err = ac.favoriteService.Add(userID, adID); err != nil {
+		if errors.Is(err, service.ErrorTooManyFavorites) {
+			pkg.SendError(w, http.StatusConflict, err.Error())
+			return
+		}
+
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveFavorite godoc
+//
+//	@Summary		Unfavorite an advertisement
+//	@Description	Removes an ad from the favorites of the authenticated user
+//	@Tags			Favorites
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Ad ID"	format(uuid)
+//	@Success		204
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	pkg.ErrorResponse	"Ad not found"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/ads/{id}/favorite [delete]
+func (ac *AdController) RemoveFavorite(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdController.RemoveFavorite called")
+
+	adID, err := ac.getAdIDFromPath(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	userID, err := ac.getIDFromToken(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err = ac.favoriteService.Remove(userID, adID); err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetFavorites godoc
+//
+//	@Summary		Get favorite ads
+//	@Description	Returns the listed ads the authenticated user has favorited, filtered, sorted and paginated like /api/ads
+//	@Tags			Favorites
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page		query		int		false	"Page number"		default(1)
+//	@Param			limit		query		int		false	"Items per page"	default(10)	minimum(1)	maximum(40)
+//	@Param			q			query		string	false	"Full-text search over title and text"
+//	@Param			sortBy		query		string	false	"Sort field (created_at, price, relevance)"	default(created_at)
+//	@Param			orderBy		query		int		false	"Order (1 asc, -1 desc)"					default(-1)
+//	@Param			minPrice	query		number	false	"Minimum price"
+//	@Param			maxPrice	query		number	false	"Maximum price"
+//	@Param			category	query		int		false	"Category ID, descendant categories are included"
+//	@Param			cursor		query		string	false	"Opaque cursor from X-Next-Cursor, replaces page"
+//	@Param			envelope	query		bool	false	"Wrap the list into dto.AdListResponse with total count and links; an empty page is then returned with 200 instead of 404"
+//	@Success		200			{array}		dto.AdResponse
+//	@Header			200			{string}	X-Next-Cursor		"Cursor of the next page, set when the page is full"
+//	@Failure		400			{object}	pkg.ErrorResponse	"Invalid query params"
+//	@Failure		401			{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	pkg.ErrorResponse	"No ads found"
+//	@Failure		500			{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/me/favorites [get]
+func (ac *AdController) GetFavorites(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdController.GetFavorites called")
+
+	userID, err := ac.getIDFromToken(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	ac.getAds(w, r, userID, func(ops *entity.Options) error {
+		ids, err := ac.favoriteService.GetAdIDs(userID)
+		if err != nil {
+			return err
+		}
+		ops.IDs = ids
+
+		return nil
+	})
+}
+
+func (ac *AdController) setStats(ads []*entity.Ad, adsResp []*dto.AdResponse) error {
+	ids := make([]uuid.UUID, 0, len(ads))
+	for _, a := range ads {
+		ids = append(ids, a.ID)
+	}
+
+	favorites, err := ac.favoriteService.CountByAds(ids)
+	if err != nil {
+		return err
+	}
+	messages, err := ac.conversationService.CountMessagesByAds(ids)
+	if err != nil {
+		return err
+	}
+
+	for i, a := range ads {
+		adsResp[i].Stats = &dto.AdStatsResponse{
+			Views:     a.Views,
+			Favorites: favorites[a.ID],
+			Messages:  messages[a.ID],
+		}
+	}
+
+	return nil
+}
+
+// removeFavorites only logs a failure: leftover rows match no listing.
+func removeFavorites(favoriteService *service.FavoriteService, adID uuid.UUID) {
+	if err := favoriteService.RemoveAd(adID); err != nil {
+		log.Printf("failed to remove favorites of deleted ad %s: %v", adID, err)
+	}
+}
+
+func (ac *AdController) editAd(w http.ResponseWriter, r *http.Request, apply func(dto.AdDTO) dto.AdDTO) {
+	adID, err := ac.getAdIDFromPath(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	userID, err := ac.getIDFromToken(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	ownedAd, err := ac.adService.GetOwned(adID, userID)
+	if err != nil {
+		ac.handleAdError(w, err)
+		return
+	}
+
+	adDTO := apply(dto.NewAdDTO(ownedAd))
+	if errs := ac.validator.Validate(adDTO, userID); errs != nil {
+		pkg.SendValidationError(w, http.StatusBadRequest, errs)
+		return
+	}
+
+	images := ac.adImages(adDTO.ImageList(), ownedAd.Images)
+	ownedAd.Edit(adDTO.Title, adDTO.Text, images, adDTO.Cover, adDTO.Price, adDTO.CategoryID)
+	if err = ac.adService.Update(ownedAd); err != nil {
+		ac.handleAdError(w, err)
+		return
+	}
+	if ownedAd.Status == entity.AdStatusPending {
+		ac.verificationService.Enqueue(ownedAd.ID)
+	}
+
+	resp := dto.NewAdResponse(ownedAd)
+	resp.ProcessOwner(ownedAd, userID)
+	pkg.SendJSON(w, http.StatusOK, resp)
+}
+
+// adImages resolves uploaded images to their media URLs and variants and
+// leaves new external images pending.
+func (ac *AdController) adImages(images []dto.AdImageDTO, prev []entity.AdImage) []entity.AdImage {
+	known := make(map[string]entity.AdImage, len(prev))
+	for _, image := range prev {
+		known[image.URL] = image
+	}
+
+	adImages := make([]entity.AdImage, 0, len(images))
+	for _, image := range images {
+		adImage := entity.AdImage{URL: image.URL, ImageID: image.ImageID}
+		if image.ImageID != nil {
+			adImage.URL = ac.imageService.URL(*image.ImageID)
+		}
+
+		if prevImage, ok := known[adImage.URL]; ok && (prevImage.ImageID == nil || prevImage.Thumbnails != nil) {
+			adImages = append(adImages, prevImage)
+			continue
+		}
+
+		if image.ImageID == nil {
+			adImage.Pending = true
+			adImages = append(adImages, adImage)
+			continue
+		}
+
+		stored, err := ac.imageService.GetByID(*image.ImageID)
+		if err != nil {
+			log.Printf("AdController failed to get variants of %s: %v", adImage.URL, err)
+		} else {
+			adImage.Thumbnails = ac.imageService.VariantURLs(stored)
+			known[adImage.URL] = adImage
+		}
+
+		adImages = append(adImages, adImage)
+	}
+
+	return adImages
+}
+
+func (ac *AdController) handleAdError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ad.ErrorAdNotFound):
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrorNotAdAuthor):
+		pkg.SendError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrorAdNotPublishable):
+		pkg.SendError(w, http.StatusConflict, err.Error())
+	default:
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (ac *AdController) getAdIDFromPath(r *http.Request) (uuid.UUID, error) {
+	adID, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		return uuid.Nil, ad.ErrorAdNotFound
+	}
+
+	return adID, nil
 }
 
 func (ac *AdController) getIDFromToken(r *http.Request) (uuid.UUID, error) {
@@ -183,6 +794,10 @@ func (ac *AdController) parseOptions(r *http.Request) (*entity.Options, error) {
 		ops.Limit = limit
 	}
 
+	if q := strings.TrimSpace(query.Get(entity.ParamQuery)); q != "" {
+		ops.Query = q
+	}
+
 	if sortBy := query.Get(entity.ParamSortBy); sortBy != "" {
 		ops.SortBy = sortBy
 	}
@@ -195,20 +810,24 @@ func (ac *AdController) parseOptions(r *http.Request) (*entity.Options, error) {
 		ops.OrderBy = orderBy
 	}
 
-	if minPriceStr := query.Get(entity.ParamMinPrice); minPriceStr != "" {
-		minPrice, err := strconv.ParseFloat(minPriceStr, 64)
+	if err := parsePrices(query, ops); err != nil {
+		return nil, err
+	}
+
+	if cursorStr := query.Get(entity.ParamCursor); cursorStr != "" {
+		cursor, err := entity.DecodeCursor(cursorStr)
 		if err != nil {
 			return nil, err
 		}
-		ops.MinPrice = minPrice
+		ops.Cursor = cursor
 	}
 
-	if maxPriceStr := query.Get(entity.ParamMaxPrice); maxPriceStr != "" {
-		maxPrice, err := strconv.ParseFloat(maxPriceStr, 64)
+	if categoryStr := query.Get(entity.ParamCategory); categoryStr != "" {
+		categoryID, err := strconv.ParseInt(categoryStr, 10, 64)
 		if err != nil {
 			return nil, err
 		}
-		ops.MaxPrice = maxPrice
+		ops.CategoryID = categoryID
 	}
 
 	err := ac.validator.ValidateOptions(ops)
@@ -219,20 +838,93 @@ func (ac *AdController) parseOptions(r *http.Request) (*entity.Options, error) {
 	return ops, nil
 }
 
-func (ac *AdController) getAds(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+func parsePrices(query url.Values, ops *entity.Options) error {
+	if minPriceStr := query.Get(entity.ParamMinPrice); minPriceStr != "" {
+		minPrice, err := strconv.ParseFloat(minPriceStr, 64)
+		if err != nil {
+			return err
+		}
+		ops.MinPrice = minPrice
+	}
+
+	if maxPriceStr := query.Get(entity.ParamMaxPrice); maxPriceStr != "" {
+		maxPrice, err := strconv.ParseFloat(maxPriceStr, 64)
+		if err != nil {
+			return err
+		}
+		ops.MaxPrice = maxPrice
+	}
+
+	return nil
+}
+
+// restrict, if given, narrows the options down or asks for unlisted ads.
+func (ac *AdController) getAds(w http.ResponseWriter, r *http.Request, userID uuid.UUID,
+	restrict func(ops *entity.Options) error) {
 	ops, err := ac.parseOptions(r)
 	if err != nil {
 		pkg.SendError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ads, err := ac.adService.GetAds(ops)
+	if restrict != nil {
+		if err = restrict(ops); err != nil {
+			pkg.SendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	envelope, err := parseEnvelope(r)
 	if err != nil {
-		if errors.Is(err, ad.ErrorAdsNotFound) {
-			pkg.SendError(w, http.StatusNotFound, err.Error())
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if ops.CategoryID > 0 {
+		ops.CategoryIDs, err = ac.categoryService.GetSubtreeIDs(ops.CategoryID)
+		if err != nil {
+			if errors.Is(err, category.ErrorCategoryNotFound) {
+				pkg.SendError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			pkg.SendError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+	}
 
+	ads, err := ac.adService.GetAds(ops)
+	switch {
+	case errors.Is(err, ad.ErrorAdsNotFound) && envelope:
+		// an empty page is a regular result in the envelope format
+	case errors.Is(err, ad.ErrorAdsNotFound):
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	favorites := make(map[uuid.UUID]struct{})
+	if userID != uuid.Nil {
+		ids := make([]uuid.UUID, 0, len(ads))
+		for _, a := range ads {
+			ids = append(ids, a.ID)
+		}
+
+		favorites, err = ac.favoriteService.Favorites(userID, ids)
+		if err != nil {
+			pkg.SendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	authorIDs := make([]uuid.UUID, 0, len(ads))
+	for _, a := range ads {
+		authorIDs = append(authorIDs, a.Author.ID)
+	}
+	ratings, err := ac.reviewService.Ratings(authorIDs)
+	if err != nil {
 		pkg.SendError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -242,9 +934,91 @@ func (ac *AdController) getAds(w http.ResponseWriter, r *http.Request, userID uu
 		resp := dto.NewAdResponse(a)
 		if userID != uuid.Nil {
 			resp.ProcessOwner(a, userID)
+			_, resp.IsFavorite = favorites[a.ID]
 		}
+		resp.SetSellerRating(ratings[a.Author.ID])
 		adsResp = append(adsResp, resp)
 	}
 
-	pkg.SendJSON(w, http.StatusOK, adsResp)
+	if userID != uuid.Nil && ops.AuthorID == userID {
+		if err = ac.setStats(ads, adsResp); err != nil {
+			pkg.SendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	var nextCursor string
+	if len(ads) == ops.Limit && ops.SortBy != entity.SortByRelevance {
+		nextCursor = entity.NewCursor(ads[len(ads)-1], ops.SortBy, ops.OrderBy).Encode()
+		w.Header().Set(nextCursorHeader, nextCursor)
+	}
+
+	if !envelope {
+		pkg.SendJSON(w, http.StatusOK, adsResp)
+		return
+	}
+
+	total, err := ac.adService.CountAds(ops)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	pkg.SendJSON(w, http.StatusOK, newAdListResponse(r, ops, adsResp, total, nextCursor))
+}
+
+func parseEnvelope(r *http.Request) (bool, error) {
+	envelopeStr := r.URL.Query().Get(paramEnvelope)
+	if envelopeStr == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(envelopeStr)
+}
+
+// Keyset pages are not addressable backwards, so cursor mode only links next.
+func newAdListResponse(r *http.Request, ops *entity.Options, items []*dto.AdResponse,
+	total int64, nextCursor string) *dto.AdListResponse {
+	list := &dto.AdListResponse{
+		Items: items,
+		Total: total,
+		Page:  ops.Page,
+		Limit: ops.Limit,
+	}
+
+	if ops.Cursor != nil {
+		list.HasNext = nextCursor != ""
+	} else {
+		list.HasNext = int64(ops.Page*ops.Limit) < total
+	}
+
+	if !list.HasNext {
+		nextCursor = ""
+	}
+	list.NextCursor = nextCursor
+
+	link := func(set func(query url.Values)) string {
+		query := r.URL.Query()
+		set(query)
+
+		return r.URL.Path + "?" + query.Encode()
+	}
+
+	if list.HasNext {
+		list.Links.Next = link(func(query url.Values) {
+			if ops.Cursor != nil {
+				query.Set(entity.ParamCursor, nextCursor)
+				return
+			}
+			query.Set(entity.ParamPage, strconv.Itoa(ops.Page+1))
+		})
+	}
+
+	if ops.Cursor == nil && ops.Page > 1 {
+		list.Links.Prev = link(func(query url.Values) {
+			query.Set(entity.ParamPage, strconv.Itoa(ops.Page-1))
+		})
+	}
+
+	return list
 }
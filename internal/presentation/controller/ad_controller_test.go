@@ -5,18 +5,27 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/alishashelby/marketplace/internal/application/dto"
 	"github.com/alishashelby/marketplace/internal/application/service"
 	"github.com/alishashelby/marketplace/internal/application/validator"
 	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/fetch"
+	"github.com/alishashelby/marketplace/internal/infrastructure/pubsub"
 	"github.com/alishashelby/marketplace/internal/infrastructure/repository/ad"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/category"
 	"github.com/alishashelby/marketplace/pkg"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 const (
@@ -24,13 +33,23 @@ const (
 	textConst     = "test text 20 symbols"
 	imageUrlConst = "https://upload.wikimedia.org/wikipedia/commons/c/c7/Tabby_cat_with_blue_eyes-3336579.jpg"
 	priceConst    = 1000.1
+
+	categoryIDConst int64 = 7
 )
 
 type adControllerTest struct {
-	ctrl         *gomock.Controller
-	adRepo       *service.MockAdRepository
-	userRepo     *service.MockUserRepository
-	adController *AdController
+	ctrl                *gomock.Controller
+	adRepo              *service.MockAdRepository
+	userRepo            *service.MockUserRepository
+	categoryRepo        *service.MockCategoryRepository
+	imageRepo           *service.MockImageRepository
+	blobStore           *service.MockBlobStore
+	favoriteRepo        *service.MockFavoriteRepository
+	conversationRepo    *service.MockConversationRepository
+	reviewRepo          *service.MockReviewRepository
+	events              *pubsub.MemoryPubSub
+	verificationService *service.AdVerificationService
+	adController        *AdController
 }
 
 func setUpAdControllerTest(t *testing.T) *adControllerTest {
@@ -41,25 +60,74 @@ func setUpAdControllerTest(t *testing.T) *adControllerTest {
 	mockUserRepo := service.NewMockUserRepository(ctrl)
 	userService := service.NewUserService(mockUserRepo, nil)
 
+	events := pubsub.NewMemoryPubSub()
 	mockAdRepo := service.NewMockAdRepository(ctrl)
-	adService := service.NewAdService(mockAdRepo)
+	adService := service.NewAdService(mockAdRepo, events)
+
+	mockCategoryRepo := service.NewMockCategoryRepository(ctrl)
+	categoryService := service.NewCategoryService(mockCategoryRepo)
+
+	mockImageRepo := service.NewMockImageRepository(ctrl)
+	mockBlobStore := service.NewMockBlobStore(ctrl)
+	imageService := service.NewImageService(mockImageRepo, mockBlobStore, "")
+
+	// Test servers listen on the loopback interface.
+	imageFetcher, err := fetch.NewClient(fetch.Config{Allowlist: []string{"127.0.0.1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	adValidator := validator.NewAdValidator(categoryService, imageService, imageFetcher)
+
+	verificationService := service.NewAdVerificationService(mockAdRepo, imageService, adValidator, events,
+		time.Millisecond)
+
+	mockFavoriteRepo := service.NewMockFavoriteRepository(ctrl)
+	favoriteService := service.NewFavoriteService(mockFavoriteRepo)
 
-	adValidator := validator.NewAdValidator()
+	mockConversationRepo := service.NewMockConversationRepository(ctrl)
+	conversationService := service.NewConversationService(mockConversationRepo, events)
 
-	adController := NewAdController(adService, userService, adValidator)
+	mockReviewRepo := service.NewMockReviewRepository(ctrl)
+	reviewService := service.NewReviewService(mockReviewRepo, conversationService)
+
+	adController := NewAdController(adService, userService, categoryService, imageService,
+		verificationService, favoriteService, conversationService, reviewService, adValidator)
 
 	return &adControllerTest{
-		ctrl:         ctrl,
-		adRepo:       mockAdRepo,
-		userRepo:     mockUserRepo,
-		adController: adController,
+		ctrl:                ctrl,
+		adRepo:              mockAdRepo,
+		userRepo:            mockUserRepo,
+		categoryRepo:        mockCategoryRepo,
+		imageRepo:           mockImageRepo,
+		blobStore:           mockBlobStore,
+		favoriteRepo:        mockFavoriteRepo,
+		conversationRepo:    mockConversationRepo,
+		reviewRepo:          mockReviewRepo,
+		events:              events,
+		verificationService: verificationService,
+		adController:        adController,
+	}
+}
+
+func newUploadedImage(id, ownerID uuid.UUID) *entity.Image {
+	image := entity.NewImage(ownerID, "image/png", 100, 10, 10)
+	image.ID = id
+	image.Variants = map[string]entity.ImageVariant{
+		entity.VariantThumbnail: {Key: image.VariantKey(entity.VariantThumbnail)},
+		entity.VariantMedium:    {Key: image.VariantKey(entity.VariantMedium)},
 	}
+
+	return image
 }
 
 func TestAdController_CreateAd(t *testing.T) {
 	test := setUpAdControllerTest(t)
 	defer test.ctrl.Finish()
 
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
 	user := &entity.User{
 		ID:       uuid.New(),
 		Username: usernameConst,
@@ -74,10 +142,11 @@ func TestAdController_CreateAd(t *testing.T) {
 		Return(nil)
 
 	testAd := &dto.AdDTO{
-		Title:    titleConst,
-		Text:     textConst,
-		ImageURL: imageUrlConst,
-		Price:    priceConst,
+		Title:      titleConst,
+		Text:       textConst,
+		ImageURL:   imageUrlConst,
+		Price:      priceConst,
+		CategoryID: categoryIDConst,
 	}
 
 	body, err := json.Marshal(testAd)
@@ -102,6 +171,63 @@ func TestAdController_CreateAd(t *testing.T) {
 	assert.Equal(t, imageUrlConst, resp.ImageURL)
 	assert.Equal(t, priceConst, resp.Price)
 	assert.Equal(t, user.Username, resp.Username)
+	assert.Equal(t, entity.AdStatusPending, resp.Status)
+	assert.Nil(t, resp.Thumbnails)
+}
+
+func TestAdController_CreateAd_Draft(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	test.userRepo.EXPECT().
+		GetByID(user.ID).
+		Return(user, nil)
+
+	var saved *entity.Ad
+	test.adRepo.EXPECT().
+		Save(gomock.Any()).
+		Do(func(ad *entity.Ad) {
+			saved = ad
+		}).
+		Return(nil)
+
+	body, err := json.Marshal(&dto.AdDTO{
+		Title:      titleConst,
+		Text:       textConst,
+		ImageURL:   imageUrlConst,
+		Price:      priceConst,
+		CategoryID: categoryIDConst,
+		Draft:      true,
+	})
+	if err != nil {
+		t.Errorf("error marshalling ad: %v", err)
+	}
+
+	listed := test.events.Subscribe(service.AdsTopic, 1)
+	defer listed.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ads", bytes.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.CreateAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, entity.AdStatusDraft, saved.Status)
+	assert.True(t, saved.ListedAt.IsZero())
+	assert.Empty(t, listed.Events(), "draft published as listed")
+
+	var resp dto.AdResponse
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.AdStatusDraft, resp.Status)
 }
 
 func TestAdController_CreateAd_BadJSON(t *testing.T) {
@@ -139,337 +265,817 @@ func TestAdController_CreateAd_ValidationErrors(t *testing.T) {
 	assert.Contains(t, errs, "Text")
 	assert.Contains(t, errs, "ImageURL")
 	assert.Contains(t, errs, "Price")
+	assert.Contains(t, errs, "CategoryID")
 }
 
-func TestAdController_CreateAd_Unauthorized(t *testing.T) {
+func TestAdController_CreateAd_WithImageID(t *testing.T) {
 	test := setUpAdControllerTest(t)
 	defer test.ctrl.Finish()
 
-	testAd := &dto.AdDTO{
-		Title:    titleConst,
-		Text:     textConst,
-		ImageURL: imageUrlConst,
-		Price:    priceConst,
+	user := &entity.User{
+		ID:       uuid.New(),
+		Username: usernameConst,
 	}
+	imageID := uuid.New()
 
-	body, err := json.Marshal(testAd)
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	test.imageRepo.EXPECT().
+		ExistsForOwner(imageID, user.ID).
+		Return(true, nil)
+
+	test.imageRepo.EXPECT().
+		FindByID(imageID).
+		Return(newUploadedImage(imageID, user.ID), nil)
+
+	test.userRepo.EXPECT().
+		GetByID(user.ID).
+		Return(user, nil)
+
+	test.adRepo.EXPECT().
+		Save(gomock.Any()).
+		Do(func(saved *entity.Ad) {
+			assert.Equal(t, []entity.AdImage{{
+				URL:     service.MediaPathPrefix + imageID.String(),
+				ImageID: &imageID,
+				Thumbnails: map[string]string{
+					entity.VariantThumbnail: service.MediaPathPrefix + imageID.String() + "/thumbnail",
+					entity.VariantMedium:    service.MediaPathPrefix + imageID.String() + "/medium",
+				},
+			}}, saved.Images)
+		}).
+		Return(nil)
+
+	body, err := json.Marshal(&dto.AdDTO{
+		Title:      titleConst,
+		Text:       textConst,
+		ImageID:    &imageID,
+		Price:      priceConst,
+		CategoryID: categoryIDConst,
+	})
 	if err != nil {
 		t.Errorf("error marshalling ad: %v", err)
 	}
 
+	sub := test.events.Subscribe(service.AdsTopic, 1)
+	defer sub.Close()
+
 	req := httptest.NewRequest(http.MethodPost, "/api/ads", bytes.NewReader(body))
-	w := httptest.NewRecorder()
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
 
+	w := httptest.NewRecorder()
 	handler := http.HandlerFunc(test.adController.CreateAd)
 	handler.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, http.StatusCreated, w.Code)
 
-	var resp pkg.ErrorResponse
+	var resp dto.AdResponse
 	err = json.NewDecoder(w.Body).Decode(&resp)
 	assert.NoError(t, err)
-	assert.Equal(t, unauthorizedError, resp.Error)
+
+	select {
+	case event := <-sub.Events():
+		assert.Equal(t, entity.EventAdCreated, event.Type)
+		assert.Equal(t, resp.ID, event.Payload.(*entity.Ad).ID)
+	default:
+		t.Error("listed ad not published")
+	}
+
+	assert.Equal(t, &imageID, resp.ImageID)
+	assert.Equal(t, service.MediaPathPrefix+imageID.String(), resp.ImageURL)
+	assert.Equal(t, &dto.Thumbnails{
+		Thumbnail: service.MediaPathPrefix + imageID.String() + "/thumbnail",
+		Medium:    service.MediaPathPrefix + imageID.String() + "/medium",
+	}, resp.Thumbnails)
 }
 
-func TestAdController_CreateAd_UserDoesNotExist(t *testing.T) {
-	test := setUpAdControllerTest(t)
-	defer test.ctrl.Finish()
+// createPendingAd publishes an ad with the external image through the
+// controller and returns the ad as it was saved.
+func createPendingAd(t *testing.T, test *adControllerTest, imageURL string) *entity.Ad {
+	t.Helper()
 
-	userID := uuid.New()
+	user := &entity.User{
+		ID:       uuid.New(),
+		Username: usernameConst,
+	}
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
 
 	test.userRepo.EXPECT().
-		GetByID(userID).
-		Return(nil, service.ErrorUserWithIDDoesNotExists)
+		GetByID(user.ID).
+		Return(user, nil)
 
-	testAd := &dto.AdDTO{
-		Title:    titleConst,
-		Text:     textConst,
-		ImageURL: imageUrlConst,
-		Price:    priceConst,
-	}
+	var saved *entity.Ad
+	test.adRepo.EXPECT().
+		Save(gomock.Any()).
+		Do(func(ad *entity.Ad) {
+			saved = ad
+		}).
+		Return(nil)
 
-	body, err := json.Marshal(testAd)
+	body, err := json.Marshal(&dto.AdDTO{
+		Title:      titleConst,
+		Text:       textConst,
+		ImageURL:   imageURL,
+		Price:      priceConst,
+		CategoryID: categoryIDConst,
+	})
 	if err != nil {
 		t.Errorf("error marshalling ad: %v", err)
 	}
 
+	listed := test.events.Subscribe(service.AdsTopic, 1)
+	defer listed.Close()
+
 	req := httptest.NewRequest(http.MethodPost, "/api/ads", bytes.NewReader(body))
-	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
-	w := httptest.NewRecorder()
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
 
+	w := httptest.NewRecorder()
 	handler := http.HandlerFunc(test.adController.CreateAd)
 	handler.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, entity.AdStatusPending, saved.Status)
+	assert.True(t, saved.Images[0].Pending)
+	assert.Empty(t, listed.Events(), "pending ad published as listed")
 
-	var resp pkg.ErrorResponse
-	err = json.NewDecoder(w.Body).Decode(&resp)
-	assert.NoError(t, err)
-	assert.Equal(t, service.ErrorUserWithIDDoesNotExists.Error(), resp.Error)
+	test.adRepo.EXPECT().
+		FindByID(saved.ID).
+		Return(saved, nil)
+
+	return saved
 }
 
-func TestAdController_CreateAd_AdServiceError(t *testing.T) {
+func TestAdController_CreateAd_VerifiesExternalImage(t *testing.T) {
 	test := setUpAdControllerTest(t)
 	defer test.ctrl.Finish()
 
-	userID := uuid.New()
-	user := &entity.User{ID: userID}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(newPNG(t, 1000, 500)) //nolint:errcheck
+	}))
+	defer server.Close()
 
-	test.userRepo.EXPECT().
-		GetByID(userID).
-		Return(user, nil)
+	imageURL := server.URL + "/cat.png"
+	saved := createPendingAd(t, test, imageURL)
 
-	test.adRepo.EXPECT().
+	var imported *entity.Image
+	test.blobStore.EXPECT().
+		Put(gomock.Any(), "image/png", gomock.Any()).
+		Times(2).
+		Return(nil)
+
+	test.imageRepo.EXPECT().
 		Save(gomock.Any()).
-		Return(ad.ErrorFailedToSaveAd)
+		Do(func(image *entity.Image) {
+			imported = image
+		}).
+		Return(nil)
 
-	testAd := &dto.AdDTO{
-		Title:    titleConst,
-		Text:     textConst,
-		ImageURL: imageUrlConst,
-		Price:    priceConst,
-	}
+	var verified *entity.Ad
+	test.adRepo.EXPECT().
+		UpdateVerification(gomock.Any()).
+		Do(func(ad *entity.Ad) {
+			verified = ad
+		}).
+		Return(true, nil)
 
-	body, err := json.Marshal(testAd)
-	if err != nil {
-		t.Errorf("error marshalling ad: %v", err)
-	}
+	sub := test.events.Subscribe(service.UserTopic(saved.Author.ID), 1)
+	defer sub.Close()
+	listed := test.events.Subscribe(service.AdsTopic, 1)
+	defer listed.Close()
 
-	req := httptest.NewRequest(http.MethodPost, "/api/ads", bytes.NewReader(body))
-	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
-	w := httptest.NewRecorder()
+	err := test.verificationService.Verify(context.Background(), saved.ID)
+	assert.NoError(t, err)
 
-	handler := http.HandlerFunc(test.adController.CreateAd)
-	handler.ServeHTTP(w, req)
+	select {
+	case event := <-sub.Events():
+		assert.Equal(t, entity.EventAdStatusChanged, event.Type)
+		assert.Equal(t, verified, event.Payload)
+	default:
+		t.Error("no status change published to the author")
+	}
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	select {
+	case event := <-listed.Events():
+		assert.Equal(t, entity.EventAdCreated, event.Type)
+		assert.Equal(t, verified, event.Payload)
+	default:
+		t.Error("activated ad not published as listed")
+	}
 
-	var resp pkg.ErrorResponse
-	err = json.NewDecoder(w.Body).Decode(&resp)
-	assert.NoError(t, err)
-	assert.Equal(t, ad.ErrorFailedToSaveAd.Error(), resp.Error)
+	assert.Equal(t, imageURL, imported.SourceURL)
+	assert.Empty(t, imported.Key)
+	assert.Equal(t, 240, imported.Variants[entity.VariantThumbnail].Width)
+	assert.Equal(t, 120, imported.Variants[entity.VariantThumbnail].Height)
+	assert.Equal(t, 800, imported.Variants[entity.VariantMedium].Width)
+	assert.Equal(t, 400, imported.Variants[entity.VariantMedium].Height)
+
+	assert.Equal(t, entity.AdStatusActive, verified.Status)
+	assert.Empty(t, verified.RejectionReason)
+	assert.False(t, verified.Images[0].Pending)
+	assert.Equal(t, map[string]string{
+		entity.VariantThumbnail: service.MediaPathPrefix + imported.ID.String() + "/thumbnail",
+		entity.VariantMedium:    service.MediaPathPrefix + imported.ID.String() + "/medium",
+	}, verified.Images[0].Thumbnails)
 }
 
-func TestAdController_GetAllAds(t *testing.T) {
+func TestAdController_CreateAd_VerifiesGalleryConcurrently(t *testing.T) {
 	test := setUpAdControllerTest(t)
 	defer test.ctrl.Finish()
 
-	ad1 := entity.NewAd("title1", "text1", "image1", 100, &entity.User{ID: uuid.New()})
-	ad2 := entity.NewAd("title2", "text2", "image2", 200, &entity.User{ID: uuid.New()})
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		if r.URL.Path == "/missing.png" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(newPNG(t, 40, 30)) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	images := make([]entity.AdImage, 8)
+	for i := range images {
+		images[i] = entity.AdImage{URL: fmt.Sprintf("%s/%d.png", server.URL, i), Pending: true}
+	}
 
-	test.adRepo.EXPECT().
-		FindAll(gomock.Any()).
-		Return([]*entity.Ad{ad1, ad2}, nil)
+	t.Run("Active", func(t *testing.T) {
+		gallery := entity.NewAd(titleConst, textConst, slices.Clone(images), 5, priceConst, categoryIDConst, user)
+		maxInFlight.Store(0)
+
+		test.adRepo.EXPECT().
+			FindByID(gallery.ID).
+			Return(gallery, nil)
+
+		test.blobStore.EXPECT().
+			Put(gomock.Any(), "image/png", gomock.Any()).
+			Times(2 * len(images)).
+			Return(nil)
+
+		imported := make(map[string]uuid.UUID)
+		test.imageRepo.EXPECT().
+			Save(gomock.Any()).
+			Do(func(image *entity.Image) {
+				imported[image.SourceURL] = image.ID
+			}).
+			Times(len(images)).
+			Return(nil)
+
+		test.adRepo.EXPECT().
+			UpdateVerification(gallery).
+			Return(true, nil)
+
+		err := test.verificationService.Verify(context.Background(), gallery.ID)
+		assert.NoError(t, err)
+
+		assert.Greater(t, maxInFlight.Load(), int32(1))
+		assert.LessOrEqual(t, maxInFlight.Load(), int32(4))
+		assert.Equal(t, entity.AdStatusActive, gallery.Status)
+		assert.Equal(t, 5, gallery.Cover)
+		for i, image := range gallery.Images {
+			assert.Equal(t, images[i].URL, image.URL)
+			assert.False(t, image.Pending)
+			assert.Equal(t, service.MediaPathPrefix+imported[image.URL].String()+"/thumbnail",
+				image.Thumbnails[entity.VariantThumbnail])
+		}
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		rejected := slices.Clone(images[:4])
+		rejected[2].URL = server.URL + "/missing.png"
+		gallery := entity.NewAd(titleConst, textConst, rejected, 0, priceConst, categoryIDConst, user)
+
+		test.adRepo.EXPECT().
+			FindByID(gallery.ID).
+			Return(gallery, nil)
+
+		test.blobStore.EXPECT().
+			Put(gomock.Any(), "image/png", gomock.Any()).
+			Times(4).
+			Return(nil)
+
+		test.imageRepo.EXPECT().
+			Save(gomock.Any()).
+			Times(2).
+			Return(nil)
+
+		test.adRepo.EXPECT().
+			UpdateVerification(gallery).
+			Return(true, nil)
+
+		err := test.verificationService.Verify(context.Background(), gallery.ID)
+		assert.NoError(t, err)
+
+		assert.Equal(t, entity.AdStatusRejected, gallery.Status)
+		assert.True(t, strings.HasPrefix(gallery.RejectionReason, "images[2].url: "), gallery.RejectionReason)
+		assert.False(t, gallery.Images[1].Pending)
+		assert.True(t, gallery.Images[2].Pending)
+		assert.True(t, gallery.Images[3].Pending)
+	})
+}
 
-	req := httptest.NewRequest(http.MethodGet, "/api/ads?page=1", nil)
-	w := httptest.NewRecorder()
+func TestAdController_CreateAd_RetriesUnavailableImage(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
 
-	handler := http.HandlerFunc(test.adController.GetAllAds)
-	handler.ServeHTTP(w, req)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(newPNG(t, 40, 30)) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	saved := createPendingAd(t, test, server.URL+"/cat.png")
+
+	test.blobStore.EXPECT().
+		Put(gomock.Any(), "image/png", gomock.Any()).
+		Times(2).
+		Return(nil)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	test.imageRepo.EXPECT().
+		Save(gomock.Any()).
+		Return(nil)
 
-	var resp []dto.AdResponse
-	err := json.NewDecoder(w.Body).Decode(&resp)
+	test.adRepo.EXPECT().
+		UpdateVerification(gomock.Any()).
+		Return(true, nil)
+
+	err := test.verificationService.Verify(context.Background(), saved.ID)
 	assert.NoError(t, err)
-	assert.Len(t, resp, 2)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, entity.AdStatusActive, saved.Status)
 }
 
-func TestAdController_GetAdsWithOwned(t *testing.T) {
+func TestAdController_EditAd_ReverifiedNotAnnounced(t *testing.T) {
 	test := setUpAdControllerTest(t)
 	defer test.ctrl.Finish()
 
-	owner, other := "owner", "other"
-
-	user := &entity.User{ID: uuid.New(), Username: owner}
-	otherUser := &entity.User{ID: uuid.New(), Username: other}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(newPNG(t, 40, 30)) //nolint:errcheck
+	}))
+	defer server.Close()
 
-	ad1 := entity.NewAd("title1", "text1", "image1", 100, user)
-	ad2 := entity.NewAd("title2", "text2", "image2", 200, otherUser)
+	saved := createPendingAd(t, test, server.URL+"/cat.png")
+	listedAt := time.Now().Add(-time.Hour)
+	saved.ListedAt = listedAt
 
-	test.adRepo.EXPECT().
-		FindAll(gomock.Any()).
-		Return([]*entity.Ad{ad1, ad2}, nil)
+	test.blobStore.EXPECT().
+		Put(gomock.Any(), "image/png", gomock.Any()).
+		Times(2).
+		Return(nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/ads/?page=1", nil)
-	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
-	w := httptest.NewRecorder()
+	test.imageRepo.EXPECT().
+		Save(gomock.Any()).
+		Return(nil)
 
-	handler := http.HandlerFunc(test.adController.GetAdsWithOwned)
-	handler.ServeHTTP(w, req)
+	test.adRepo.EXPECT().
+		UpdateVerification(saved).
+		Return(true, nil)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	listed := test.events.Subscribe(service.AdsTopic, 1)
+	defer listed.Close()
 
-	var resp []dto.AdResponse
-	err := json.NewDecoder(w.Body).Decode(&resp)
+	err := test.verificationService.Verify(context.Background(), saved.ID)
 	assert.NoError(t, err)
-	assert.Len(t, resp, 2)
+	assert.Equal(t, entity.AdStatusActive, saved.Status)
+	assert.Equal(t, listedAt, saved.ListedAt)
+	assert.Empty(t, listed.Events(), "edited ad announced as listed again")
+}
 
-	assert.True(t, resp[0].IsOwner)
-	assert.False(t, resp[1].IsOwner)
-	assert.Equal(t, owner, resp[0].Username)
-	assert.Equal(t, other, resp[1].Username)
+func TestAdController_CreateAd_RejectsUnavailableImage(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/missing.png" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		path     string
+		status   int
+		attempts int32
+	}{
+		{path: "/missing.png", status: http.StatusNotFound, attempts: 1},
+		{path: "/flaky.png", status: http.StatusBadGateway, attempts: 3},
+	} {
+		requests.Store(0)
+		saved := createPendingAd(t, test, server.URL+tc.path)
+
+		test.adRepo.EXPECT().
+			UpdateVerification(saved).
+			Return(true, nil)
+
+		err := test.verificationService.Verify(context.Background(), saved.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.attempts, requests.Load(), tc.path)
+		assert.Equal(t, entity.AdStatusRejected, saved.Status, tc.path)
+		assert.Equal(t, fmt.Sprintf(service.ReportRejectedImage, 0,
+			fmt.Sprintf(validator.ReportErrorUnavailableImg, tc.status)), saved.RejectionReason)
+		assert.True(t, saved.Images[0].Pending)
+	}
 }
 
-func TestAdController_GetAdsWithOwned_Unauthorized(t *testing.T) {
+func TestAdController_CreateAd_VerificationOutdated(t *testing.T) {
 	test := setUpAdControllerTest(t)
 	defer test.ctrl.Finish()
 
-	req := httptest.NewRequest(http.MethodGet, "/api/ads/?page=1", nil)
-	w := httptest.NewRecorder()
+	saved := createPendingAd(t, test, "http://169.254.169.254/latest/meta-data/")
 
-	handler := http.HandlerFunc(test.adController.GetAdsWithOwned)
-	handler.ServeHTTP(w, req)
+	test.adRepo.EXPECT().
+		UpdateVerification(saved).
+		Return(false, nil)
 
-	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	sub := test.events.Subscribe(service.UserTopic(saved.Author.ID), 1)
+	defer sub.Close()
 
-	var resp pkg.ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&resp)
-	assert.NoError(t, err)
-	assert.Equal(t, unauthorizedError, resp.Error)
+	err := test.verificationService.Verify(context.Background(), saved.ID)
+	assert.ErrorIs(t, err, service.ErrorAdEdited)
+	assert.Empty(t, sub.Events())
 }
 
-func TestAdController_GetAllAds_WithOptions(t *testing.T) {
+func TestAdController_CreateAd_ForeignImageID(t *testing.T) {
 	test := setUpAdControllerTest(t)
 	defer test.ctrl.Finish()
 
-	user := &entity.User{ID: uuid.New()}
-	ad1 := entity.NewAd("title1", "text1", "image1", 100, user)
-	ad2 := entity.NewAd("title2", "text2", "image2", 200, user)
+	userID := uuid.New()
+	imageID := uuid.New()
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	test.imageRepo.EXPECT().
+		ExistsForOwner(imageID, userID).
+		Return(false, nil)
 
 	test.adRepo.EXPECT().
-		FindAll(gomock.Any()).
-		Do(func(ops *entity.Options) {
-			assert.Equal(t, 2, ops.Page)
-			assert.Equal(t, 20, ops.Limit)
-			assert.Equal(t, entity.SortByPrice, ops.SortBy)
-			assert.Equal(t, entity.OrderByAsc, ops.OrderBy)
-			assert.Equal(t, 50.0, ops.MinPrice)
-			assert.Equal(t, 300.0, ops.MaxPrice)
-		}).
-		Return([]*entity.Ad{ad1, ad2}, nil)
+		Save(gomock.Any()).
+		Times(0)
+
+	body, err := json.Marshal(&dto.AdDTO{
+		Title:      titleConst,
+		Text:       textConst,
+		ImageID:    &imageID,
+		Price:      priceConst,
+		CategoryID: categoryIDConst,
+	})
+	if err != nil {
+		t.Errorf("error marshalling ad: %v", err)
+	}
 
-	req := httptest.NewRequest(
-		http.MethodGet,
-		"/api/ads?page=2&limit=20&sort_by=price&order_by=1&min_price=50&max_price=300",
-		nil,
-	)
-	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/ads", bytes.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
 
-	handler := http.HandlerFunc(test.adController.GetAllAds)
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.adController.CreateAd)
 	handler.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var resp []dto.AdResponse
-	err := json.NewDecoder(w.Body).Decode(&resp)
+	var resp pkg.ValidationErrorResponse
+	err = json.NewDecoder(w.Body).Decode(&resp)
 	assert.NoError(t, err)
-	assert.Len(t, resp, 2)
-	assert.False(t, resp[0].IsOwner)
+	assert.Equal(t, fmt.Sprintf(validator.ReportUnknownImage, imageID), resp.Errors[validator.ImageIDField])
 }
 
-func TestAdController_GetAds_InvalidOptions(t *testing.T) {
+func TestAdController_CreateAd_Gallery(t *testing.T) {
 	test := setUpAdControllerTest(t)
 	defer test.ctrl.Finish()
 
-	testCases := []struct {
-		name           string
-		query          string
-		expectedStatus int
-		expectedError  string
-	}{
-		{
-			name:           "invalid page",
-			query:          "page=invalid",
-			expectedStatus: http.StatusBadRequest,
-			expectedError:  "strconv.Atoi: parsing \"invalid\": invalid syntax",
-		},
-		{
-			name:           "invalid limit",
-			query:          "page=1&limit=invalid",
-			expectedStatus: http.StatusBadRequest,
-			expectedError:  "strconv.Atoi: parsing \"invalid\": invalid syntax",
-		},
-		{
-			name:           "invalid order_by",
-			query:          "page=1&order_by=invalid",
-			expectedStatus: http.StatusBadRequest,
-			expectedError:  "strconv.Atoi: parsing \"invalid\": invalid syntax",
-		},
-		{
-			name:           "invalid min_price",
-			query:          "page=1&min_price=invalid",
-			expectedStatus: http.StatusBadRequest,
-			expectedError:  "strconv.ParseFloat: parsing \"invalid\": invalid syntax",
-		},
-		{
-			name:           "invalid max_price",
-			query:          "page=1&max_price=invalid",
-			expectedStatus: http.StatusBadRequest,
-			expectedError:  "strconv.ParseFloat: parsing \"invalid\": invalid syntax",
-		},
-		{
-			name:           "invalid sort_by",
-			query:          "page=1&sort_by=invalid_field",
-			expectedStatus: http.StatusBadRequest,
-			expectedError:  validator.ReportInvalidSortBy,
-		},
-		{
-			name:           "invalid order_by value",
-			query:          "page=1&order_by=3",
-			expectedStatus: http.StatusBadRequest,
-			expectedError:  validator.ReportInvalidOrderBy,
+	user := &entity.User{
+		ID:       uuid.New(),
+		Username: usernameConst,
+	}
+	imageIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	images := make([]dto.AdImageDTO, 0, len(imageIDs))
+	for i := range imageIDs {
+		test.imageRepo.EXPECT().
+			ExistsForOwner(imageIDs[i], user.ID).
+			Return(true, nil)
+		test.imageRepo.EXPECT().
+			FindByID(imageIDs[i]).
+			Return(newUploadedImage(imageIDs[i], user.ID), nil)
+		images = append(images, dto.AdImageDTO{ImageID: &imageIDs[i]})
+	}
+
+	test.userRepo.EXPECT().
+		GetByID(user.ID).
+		Return(user, nil)
+
+	test.adRepo.EXPECT().
+		Save(gomock.Any()).
+		Do(func(saved *entity.Ad) {
+			assert.Len(t, saved.Images, len(imageIDs))
+			assert.Equal(t, 2, saved.Cover)
+			for i, image := range saved.Images {
+				assert.Equal(t, &imageIDs[i], image.ImageID)
+			}
+		}).
+		Return(nil)
+
+	body, err := json.Marshal(&dto.AdDTO{
+		Title:      titleConst,
+		Text:       textConst,
+		Images:     images,
+		Cover:      2,
+		Price:      priceConst,
+		CategoryID: categoryIDConst,
+	})
+	if err != nil {
+		t.Errorf("error marshalling ad: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ads", bytes.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.adController.CreateAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp dto.AdResponse
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Images, len(imageIDs))
+	assert.Equal(t, 2, resp.Cover)
+	assert.Equal(t, &imageIDs[2], resp.ImageID)
+	assert.Equal(t, service.MediaPathPrefix+imageIDs[2].String(), resp.ImageURL)
+}
+
+func TestAdController_CreateAd_GalleryValidationErrors(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	ownImage, foreignImage := uuid.New(), uuid.New()
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	test.imageRepo.EXPECT().
+		ExistsForOwner(ownImage, userID).
+		Return(true, nil)
+
+	test.imageRepo.EXPECT().
+		ExistsForOwner(foreignImage, userID).
+		Return(false, nil)
+
+	test.adRepo.EXPECT().
+		Save(gomock.Any()).
+		Times(0)
+
+	body, err := json.Marshal(&dto.AdDTO{
+		Title: titleConst,
+		Text:  textConst,
+		Images: []dto.AdImageDTO{
+			{ImageID: &ownImage},
+			{URL: "not a url"},
+			{ImageID: &foreignImage},
+			{},
 		},
+		Cover:      4,
+		Price:      priceConst,
+		CategoryID: categoryIDConst,
+	})
+	if err != nil {
+		t.Errorf("error marshalling ad: %v", err)
 	}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			req := httptest.NewRequest(http.MethodGet, "/api/ads?"+tc.query, nil)
-			w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/ads", bytes.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
 
-			handler := http.HandlerFunc(test.adController.GetAllAds)
-			handler.ServeHTTP(w, req)
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.adController.CreateAd)
+	handler.ServeHTTP(w, req)
 
-			assert.Equal(t, tc.expectedStatus, w.Code)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-			var resp pkg.ErrorResponse
-			err := json.NewDecoder(w.Body).Decode(&resp)
-			assert.NoError(t, err)
-			assert.Equal(t, tc.expectedError, resp.Error)
-		})
+	var resp pkg.ValidationErrorResponse
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"Images[1].URL":      fmt.Sprintf(validator.ReportNeedURL, "Images[1].URL"),
+		"Images[3].URL":      validator.ReportNeedImage,
+		"images[2].image_id": fmt.Sprintf(validator.ReportUnknownImage, foreignImage),
+		validator.CoverField: fmt.Sprintf(validator.ReportCoverOutOfRange, 4),
+	}, resp.Errors)
+}
+
+func TestAdController_CreateAd_InternalImageURL(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	for _, imageURL := range []string{"http://169.254.169.254/latest/meta-data/", "http://[::1]/cat.png"} {
+		saved := createPendingAd(t, test, imageURL)
+
+		test.adRepo.EXPECT().
+			UpdateVerification(saved).
+			Return(true, nil)
+
+		err := test.verificationService.Verify(context.Background(), saved.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.AdStatusRejected, saved.Status, imageURL)
+		assert.Contains(t, saved.RejectionReason, fetch.ErrorForbiddenAddress.Error(), imageURL)
 	}
 }
 
-func TestAdController_GetAds_NotFound(t *testing.T) {
+func TestAdController_CreateAd_EmptyGallery(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ads", bytes.NewBufferString(
+		fmt.Sprintf(`{"title":%q,"text":%q,"images":[],"price":1,"category_id":%d}`, titleConst, textConst, categoryIDConst)))
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, uuid.New()))
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.adController.CreateAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp pkg.ValidationErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Images": validator.ReportNeedImage}, resp.Errors)
+}
+
+func TestAdController_CreateAd_Unauthorized(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	testAd := &dto.AdDTO{
+		Title:      titleConst,
+		Text:       textConst,
+		ImageURL:   imageUrlConst,
+		Price:      priceConst,
+		CategoryID: categoryIDConst,
+	}
+
+	body, err := json.Marshal(testAd)
+	if err != nil {
+		t.Errorf("error marshalling ad: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ads", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.CreateAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var resp pkg.ErrorResponse
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, unauthorizedError, resp.Error)
+}
+
+func TestAdController_CreateAd_UserDoesNotExist(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	userID := uuid.New()
+
+	test.userRepo.EXPECT().
+		GetByID(userID).
+		Return(nil, service.ErrorUserWithIDDoesNotExists)
+
+	testAd := &dto.AdDTO{
+		Title:      titleConst,
+		Text:       textConst,
+		ImageURL:   imageUrlConst,
+		Price:      priceConst,
+		CategoryID: categoryIDConst,
+	}
+
+	body, err := json.Marshal(testAd)
+	if err != nil {
+		t.Errorf("error marshalling ad: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ads", bytes.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.CreateAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var resp pkg.ErrorResponse
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, service.ErrorUserWithIDDoesNotExists.Error(), resp.Error)
+}
+
+func TestAdController_CreateAd_AdServiceError(t *testing.T) {
 	test := setUpAdControllerTest(t)
 	defer test.ctrl.Finish()
 
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	userID := uuid.New()
+	user := &entity.User{ID: userID}
+
+	test.userRepo.EXPECT().
+		GetByID(userID).
+		Return(user, nil)
+
 	test.adRepo.EXPECT().
-		FindAll(gomock.Any()).
-		Return(nil, ad.ErrorAdsNotFound)
+		Save(gomock.Any()).
+		Return(ad.ErrorFailedToSaveAd)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/ads?page=1", nil)
+	testAd := &dto.AdDTO{
+		Title:      titleConst,
+		Text:       textConst,
+		ImageURL:   imageUrlConst,
+		Price:      priceConst,
+		CategoryID: categoryIDConst,
+	}
+
+	body, err := json.Marshal(testAd)
+	if err != nil {
+		t.Errorf("error marshalling ad: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ads", bytes.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
 	w := httptest.NewRecorder()
 
-	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler := http.HandlerFunc(test.adController.CreateAd)
 	handler.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
 	var resp pkg.ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&resp)
+	err = json.NewDecoder(w.Body).Decode(&resp)
 	assert.NoError(t, err)
-	assert.Equal(t, ad.ErrorAdsNotFound.Error(), resp.Error)
+	assert.Equal(t, ad.ErrorFailedToSaveAd.Error(), resp.Error)
 }
 
-func TestAdController_GetAds_ServiceError(t *testing.T) {
+func TestAdController_GetAllAds(t *testing.T) {
 	test := setUpAdControllerTest(t)
 	defer test.ctrl.Finish()
 
-	bdErr := "database error"
+	ad1 := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 100, categoryIDConst, &entity.User{ID: uuid.New()})
+	ad2 := entity.NewAd("title2", "text2", []entity.AdImage{{URL: "image2"}}, 0, 200, categoryIDConst, &entity.User{ID: uuid.New()})
+
 	test.adRepo.EXPECT().
 		FindAll(gomock.Any()).
-		Return(nil, errors.New(bdErr))
+		Return([]*entity.Ad{ad1, ad2}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings(gomock.Any()).
+		Return(nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/ads?page=1", nil)
 	w := httptest.NewRecorder()
@@ -477,12 +1083,1786 @@ func TestAdController_GetAds_ServiceError(t *testing.T) {
 	handler := http.HandlerFunc(test.adController.GetAllAds)
 	handler.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, http.StatusOK, w.Code)
 
-	var resp pkg.ErrorResponse
+	var resp []dto.AdResponse
 	err := json.NewDecoder(w.Body).Decode(&resp)
 	assert.NoError(t, err)
-	assert.Equal(t, bdErr, resp.Error)
+	assert.Len(t, resp, 2)
+}
+
+func TestAdController_GetAdsWithOwned(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	owner, other := "owner", "other"
+
+	user := &entity.User{ID: uuid.New(), Username: owner}
+	otherUser := &entity.User{ID: uuid.New(), Username: other}
+
+	ad1 := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 100, categoryIDConst, user)
+	ad2 := entity.NewAd("title2", "text2", []entity.AdImage{{URL: "image2"}}, 0, 200, categoryIDConst, otherUser)
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Return([]*entity.Ad{ad1, ad2}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings([]uuid.UUID{user.ID, otherUser.ID}).
+		Return([]*entity.Rating{{UserID: otherUser.ID, Count: 2, Sum: 9}}, nil)
+
+	test.favoriteRepo.EXPECT().
+		FilterFavorites(user.ID, []uuid.UUID{ad1.ID, ad2.ID}).
+		Return([]uuid.UUID{ad2.ID}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads/?page=1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAdsWithOwned)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+
+	assert.True(t, resp[0].IsOwner)
+	assert.False(t, resp[1].IsOwner)
+	assert.False(t, resp[0].IsFavorite)
+	assert.True(t, resp[1].IsFavorite)
+	assert.Equal(t, owner, resp[0].Username)
+	assert.Equal(t, other, resp[1].Username)
+	assert.Zero(t, resp[0].SellerReviews)
+	assert.Equal(t, 4.5, resp[1].SellerRating)
+	assert.Equal(t, int64(2), resp[1].SellerReviews)
+}
+
+func TestAdController_GetAdsWithOwned_Unauthorized(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads/?page=1", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAdsWithOwned)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, unauthorizedError, resp.Error)
+}
+
+func TestAdController_GetAllAds_WithOptions(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New()}
+	ad1 := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 100, categoryIDConst, user)
+	ad2 := entity.NewAd("title2", "text2", []entity.AdImage{{URL: "image2"}}, 0, 200, categoryIDConst, user)
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Do(func(ops *entity.Options) {
+			assert.Equal(t, 2, ops.Page)
+			assert.Equal(t, 20, ops.Limit)
+			assert.Equal(t, entity.SortByPrice, ops.SortBy)
+			assert.Equal(t, entity.OrderByAsc, ops.OrderBy)
+			assert.Equal(t, 50.0, ops.MinPrice)
+			assert.Equal(t, 300.0, ops.MaxPrice)
+		}).
+		Return([]*entity.Ad{ad1, ad2}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings(gomock.Any()).
+		Return(nil, nil)
+
+	req := httptest.NewRequest(
+		http.MethodGet,
+		"/api/ads?page=2&limit=20&sort_by=price&order_by=1&min_price=50&max_price=300",
+		nil,
+	)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+	assert.False(t, resp[0].IsOwner)
+}
+
+func TestAdController_GetAllAds_WithQuery(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	ad1 := entity.NewAd("blue bike", "text1", []entity.AdImage{{URL: "image1"}}, 0, 100, categoryIDConst, &entity.User{ID: uuid.New()})
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Do(func(ops *entity.Options) {
+			assert.Equal(t, "blue bike", ops.Query)
+			assert.Equal(t, entity.SortByRelevance, ops.SortBy)
+		}).
+		Return([]*entity.Ad{ad1}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings(gomock.Any()).
+		Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads?page=1&q=+blue+bike+&sort_by=relevance", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+}
+
+func TestAdController_GetAllAds_WithCategory(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	ad1 := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 100, 5, &entity.User{ID: uuid.New()})
+	subtree := []int64{2, 5, 6}
+
+	test.categoryRepo.EXPECT().
+		FindSubtreeIDs(int64(2)).
+		Return(subtree, nil)
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Do(func(ops *entity.Options) {
+			assert.Equal(t, int64(2), ops.CategoryID)
+			assert.Equal(t, subtree, ops.CategoryIDs)
+		}).
+		Return([]*entity.Ad{ad1}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings(gomock.Any()).
+		Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads?page=1&category=2", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, int64(5), resp[0].CategoryID)
+}
+
+func TestAdController_GetAllAds_UnknownCategory(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.categoryRepo.EXPECT().
+		FindSubtreeIDs(int64(42)).
+		Return(nil, category.ErrorCategoryNotFound)
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Times(0)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads?page=1&category=42", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, category.ErrorCategoryNotFound.Error(), resp.Error)
+}
+
+func TestAdController_GetAllAds_NextCursor(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New()}
+	ad1 := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 200, categoryIDConst, user)
+	ad2 := entity.NewAd("title2", "text2", []entity.AdImage{{URL: "image2"}}, 0, 100, categoryIDConst, user)
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Return([]*entity.Ad{ad1, ad2}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings(gomock.Any()).
+		Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads?page=1&limit=2&sort_by=price", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	cursor, err := entity.DecodeCursor(w.Header().Get(nextCursorHeader))
+	assert.NoError(t, err)
+	assert.Equal(t, ad2.ID, cursor.ID)
+	assert.Equal(t, ad2.Price, cursor.Price)
+	assert.Equal(t, entity.SortByPrice, cursor.SortBy)
+	assert.Equal(t, entity.OrderByDesc, cursor.OrderBy)
+}
+
+func TestAdController_GetAllAds_NoNextCursorOnLastPage(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	ad1 := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 200, categoryIDConst, &entity.User{ID: uuid.New()})
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Return([]*entity.Ad{ad1}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings(gomock.Any()).
+		Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads?page=1&limit=2", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(nextCursorHeader))
+}
+
+func TestAdController_GetAllAds_WithCursor(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New()}
+	last := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 200, categoryIDConst, user)
+	ad1 := entity.NewAd("title2", "text2", []entity.AdImage{{URL: "image2"}}, 0, 100, categoryIDConst, user)
+	cursor := entity.NewCursor(last, entity.SortByCreatedAt, entity.OrderByDesc)
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Do(func(ops *entity.Options) {
+			assert.Equal(t, 1, ops.Page)
+			assert.NotNil(t, ops.Cursor)
+			assert.Equal(t, last.ID, ops.Cursor.ID)
+			assert.True(t, last.CreatedAt.Equal(ops.Cursor.CreatedAt))
+		}).
+		Return([]*entity.Ad{ad1}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings(gomock.Any()).
+		Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads?cursor="+cursor.Encode(), nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+}
+
+func TestAdController_GetAllAds_Envelope(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New()}
+	ad1 := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 200, categoryIDConst, user)
+	ad2 := entity.NewAd("title2", "text2", []entity.AdImage{{URL: "image2"}}, 0, 100, categoryIDConst, user)
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Return([]*entity.Ad{ad1, ad2}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings(gomock.Any()).
+		Return(nil, nil)
+
+	test.adRepo.EXPECT().
+		Count(gomock.Any()).
+		Do(func(ops *entity.Options) {
+			assert.Equal(t, 2, ops.Page)
+			assert.Equal(t, 100.0, ops.MinPrice)
+		}).
+		Return(int64(5), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads?envelope=true&page=2&limit=2&min_price=100&max_price=300", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AdListResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Items, 2)
+	assert.Equal(t, int64(5), resp.Total)
+	assert.Equal(t, 2, resp.Page)
+	assert.Equal(t, 2, resp.Limit)
+	assert.True(t, resp.HasNext)
+	assert.Equal(t, w.Header().Get(nextCursorHeader), resp.NextCursor)
+	assert.Equal(t, "/api/ads?envelope=true&limit=2&max_price=300&min_price=100&page=3", resp.Links.Next)
+	assert.Equal(t, "/api/ads?envelope=true&limit=2&max_price=300&min_price=100&page=1", resp.Links.Prev)
+}
+
+func TestAdController_GetAllAds_EnvelopeEmptyPage(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Return(nil, ad.ErrorAdsNotFound)
+
+	test.adRepo.EXPECT().
+		Count(gomock.Any()).
+		Return(int64(3), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads?envelope=1&page=2&limit=10", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AdListResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.Items)
+	assert.Empty(t, resp.Items)
+	assert.Equal(t, int64(3), resp.Total)
+	assert.False(t, resp.HasNext)
+	assert.Empty(t, resp.NextCursor)
+	assert.Empty(t, resp.Links.Next)
+	assert.Equal(t, "/api/ads?envelope=1&limit=10&page=1", resp.Links.Prev)
+}
+
+func TestAdController_GetAllAds_EnvelopeCursor(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New()}
+	last := entity.NewAd("title0", "text0", []entity.AdImage{{URL: "image0"}}, 0, 300, categoryIDConst, user)
+	ad1 := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 200, categoryIDConst, user)
+	cursor := entity.NewCursor(last, entity.SortByCreatedAt, entity.OrderByDesc).Encode()
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Return([]*entity.Ad{ad1}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings(gomock.Any()).
+		Return(nil, nil)
+
+	test.adRepo.EXPECT().
+		Count(gomock.Any()).
+		Return(int64(10), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads?envelope=true&limit=1&cursor="+cursor, nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AdListResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.True(t, resp.HasNext)
+
+	next, err := entity.DecodeCursor(resp.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, ad1.ID, next.ID)
+	assert.Equal(t, "/api/ads?cursor="+resp.NextCursor+"&envelope=true&limit=1", resp.Links.Next)
+	assert.Empty(t, resp.Links.Prev)
+}
+
+func TestAdController_GetAllAds_EnvelopeCountError(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	bdErr := "database error"
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Return(nil, ad.ErrorAdsNotFound)
+
+	test.adRepo.EXPECT().
+		Count(gomock.Any()).
+		Return(int64(0), errors.New(bdErr))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads?envelope=true&page=1", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, bdErr, resp.Error)
+}
+
+func TestAdController_GetAds_InvalidOptions(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	priceCursor := entity.NewCursor(
+		entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 200, categoryIDConst, &entity.User{ID: uuid.New()}),
+		entity.SortByPrice, entity.OrderByDesc,
+	).Encode()
+
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "invalid page",
+			query:          "page=invalid",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "strconv.Atoi: parsing \"invalid\": invalid syntax",
+		},
+		{
+			name:           "invalid limit",
+			query:          "page=1&limit=invalid",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "strconv.Atoi: parsing \"invalid\": invalid syntax",
+		},
+		{
+			name:           "invalid order_by",
+			query:          "page=1&order_by=invalid",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "strconv.Atoi: parsing \"invalid\": invalid syntax",
+		},
+		{
+			name:           "invalid min_price",
+			query:          "page=1&min_price=invalid",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "strconv.ParseFloat: parsing \"invalid\": invalid syntax",
+		},
+		{
+			name:           "invalid max_price",
+			query:          "page=1&max_price=invalid",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "strconv.ParseFloat: parsing \"invalid\": invalid syntax",
+		},
+		{
+			name:           "invalid sort_by",
+			query:          "page=1&sort_by=invalid_field",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  validator.ReportInvalidSortBy,
+		},
+		{
+			name:           "invalid category",
+			query:          "page=1&category=invalid",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "strconv.ParseInt: parsing \"invalid\": invalid syntax",
+		},
+		{
+			name:           "relevance without query",
+			query:          "page=1&sort_by=relevance",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  validator.ReportRelevanceWithoutQuery,
+		},
+		{
+			name:           "too long query",
+			query:          "page=1&q=" + strings.Repeat("a", entity.QueryMaxLength+1),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "q must be at most 100",
+		},
+		{
+			name:           "invalid order_by value",
+			query:          "page=1&order_by=3",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  validator.ReportInvalidOrderBy,
+		},
+		{
+			name:           "invalid envelope",
+			query:          "page=1&envelope=maybe",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "strconv.ParseBool: parsing \"maybe\": invalid syntax",
+		},
+		{
+			name:           "malformed cursor",
+			query:          "cursor=not-a-cursor",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  entity.ErrorInvalidCursor.Error(),
+		},
+		{
+			name:           "cursor with page",
+			query:          "page=2&sort_by=price&cursor=" + priceCursor,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  validator.ReportCursorWithPage,
+		},
+		{
+			name:           "cursor with different sort",
+			query:          "sort_by=created_at&cursor=" + priceCursor,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  validator.ReportCursorMismatch,
+		},
+		{
+			name:           "cursor with relevance",
+			query:          "q=bike&sort_by=relevance&cursor=" + priceCursor,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  validator.ReportCursorWithRelevance,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/ads?"+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(test.adController.GetAllAds)
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp pkg.ErrorResponse
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedError, resp.Error)
+		})
+	}
+}
+
+func TestAdController_GetAds_NotFound(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Return(nil, ad.ErrorAdsNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads?page=1", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, ad.ErrorAdsNotFound.Error(), resp.Error)
+}
+
+func TestAdController_GetAds_ServiceError(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	bdErr := "database error"
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Return(nil, errors.New(bdErr))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads?page=1", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAllAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, bdErr, resp.Error)
+}
+
+func TestAdController_GetMyAds(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	pending := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1", Pending: true}}, 0, 100,
+		categoryIDConst, user)
+	listed := entity.NewAd("title2", "text2", []entity.AdImage{{URL: "image2"}}, 0, 200, categoryIDConst, user)
+	listed.Views = 12
+	ids := []uuid.UUID{pending.ID, listed.ID}
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		DoAndReturn(func(ops *entity.Options) ([]*entity.Ad, error) {
+			assert.Equal(t, user.ID, ops.AuthorID)
+			assert.Equal(t, entity.AdStatuses, ops.Statuses)
+			assert.Equal(t, entity.SortByPrice, ops.SortBy)
+			assert.Equal(t, entity.OrderByAsc, ops.OrderBy)
+
+			return []*entity.Ad{pending, listed}, nil
+		})
+
+	test.favoriteRepo.EXPECT().
+		FilterFavorites(user.ID, ids).
+		Return(nil, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings([]uuid.UUID{user.ID}).
+		Return(nil, nil)
+
+	test.favoriteRepo.EXPECT().
+		CountByAds(ids).
+		Return(map[uuid.UUID]int64{listed.ID: 4}, nil)
+
+	test.conversationRepo.EXPECT().
+		CountMessagesByAds(ids).
+		Return(map[uuid.UUID]int64{listed.ID: 9}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me/ads?page=1&sort_by=price&order_by=1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetMyAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	if assert.Len(t, resp, 2) {
+		assert.True(t, resp[0].IsOwner)
+		assert.Equal(t, entity.AdStatusPending, resp[0].Status)
+		assert.Equal(t, &dto.AdStatsResponse{}, resp[0].Stats)
+		assert.Equal(t, entity.AdStatusActive, resp[1].Status)
+		assert.Equal(t, &dto.AdStatsResponse{Views: 12, Favorites: 4, Messages: 9}, resp[1].Stats)
+	}
+}
+
+func TestAdController_GetMyAds_Status(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+
+	for _, status := range []entity.AdStatus{entity.AdStatusRejected, entity.AdStatusDraft, entity.AdStatusExpired} {
+		test.adRepo.EXPECT().
+			FindAll(gomock.Any()).
+			DoAndReturn(func(ops *entity.Options) ([]*entity.Ad, error) {
+				assert.Equal(t, []entity.AdStatus{status}, ops.Statuses)
+
+				return nil, ad.ErrorAdsNotFound
+			})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/me/ads?page=1&status="+string(status), nil)
+		req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+		w := httptest.NewRecorder()
+
+		handler := http.HandlerFunc(test.adController.GetMyAds)
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code, status)
+	}
+}
+
+func TestAdController_GetMyAds_InvalidStatus(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me/ads?page=1&status=sold", nil)
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, uuid.New()))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetMyAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(reportInvalidStatus, entity.AdStatuses), resp.Error)
+}
+
+func TestAdController_GetMyAds_Unauthorized(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me/ads?page=1", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetMyAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAdController_GetUserAds(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	author := &entity.User{ID: uuid.New(), Username: usernameConst}
+	viewerID := uuid.New()
+	ad1 := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 100, categoryIDConst, author)
+
+	test.userRepo.EXPECT().
+		GetByUsername(usernameConst).
+		Return(author, nil)
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		DoAndReturn(func(ops *entity.Options) ([]*entity.Ad, error) {
+			assert.Equal(t, author.ID, ops.AuthorID)
+			assert.Equal(t, entity.SortByPrice, ops.SortBy)
+			assert.Equal(t, 50.0, ops.MinPrice)
+
+			return []*entity.Ad{ad1}, nil
+		})
+
+	test.favoriteRepo.EXPECT().
+		FilterFavorites(viewerID, []uuid.UUID{ad1.ID}).
+		Return([]uuid.UUID{ad1.ID}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings([]uuid.UUID{author.ID}).
+		Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/users/"+usernameConst+"/ads?page=1&sort_by=price&min_price=50&max_price=500", nil)
+	req = mux.SetURLVars(req, map[string]string{"username": usernameConst})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, viewerID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetUserAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	if assert.Len(t, resp, 1) {
+		assert.Equal(t, ad1.ID, resp[0].ID)
+		assert.False(t, resp[0].IsOwner)
+		assert.True(t, resp[0].IsFavorite)
+	}
+}
+
+func TestAdController_GetUserAds_UserNotFound(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.userRepo.EXPECT().
+		GetByUsername("nobody").
+		Return(nil, errors.New("no rows in result set"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/nobody/ads?page=1", nil)
+	req = mux.SetURLVars(req, map[string]string{"username": "nobody"})
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetUserAds)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, service.ErrorUserWithUsernameDoesNotExists.Error(), resp.Error)
+}
+
+func TestAdController_GetAdByID(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	testAd := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 100, categoryIDConst, &entity.User{ID: uuid.New(), Username: usernameConst})
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.adRepo.EXPECT().
+		IncrementViews(testAd.ID).
+		Return(nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings([]uuid.UUID{testAd.Author.ID}).
+		Return([]*entity.Rating{{UserID: testAd.Author.ID, Count: 3, Sum: 13}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads/"+testAd.ID.String(), nil)
+	req = mux.SetURLVars(req, map[string]string{"id": testAd.ID.String()})
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAdByID)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, testAd.ID, resp.ID)
+	assert.Equal(t, testAd.Title, resp.Title)
+	assert.Equal(t, usernameConst, resp.Username)
+	assert.False(t, resp.IsOwner)
+	assert.Equal(t, 4.33, resp.SellerRating)
+	assert.Equal(t, int64(3), resp.SellerReviews)
+}
+
+func TestAdController_GetAdByID_Owner(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	testAd := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 100, categoryIDConst, user)
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings(gomock.Any()).
+		Return(nil, nil)
+
+	test.favoriteRepo.EXPECT().
+		FilterFavorites(user.ID, []uuid.UUID{testAd.ID}).
+		Return([]uuid.UUID{testAd.ID}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads/"+testAd.ID.String(), nil)
+	req = mux.SetURLVars(req, map[string]string{"id": testAd.ID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAdByID)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.True(t, resp.IsOwner)
+	assert.True(t, resp.IsFavorite)
+	assert.Equal(t, entity.AdStatusActive, resp.Status)
+}
+
+func TestAdController_GetAdByID_Rejected(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	testAd := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1", Pending: true}}, 0, 100, categoryIDConst, user)
+	testAd.Reject("images[0].url: error - image is not available: 404")
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil).
+		Times(3)
+
+	test.reviewRepo.EXPECT().
+		FindRatings(gomock.Any()).
+		Return(nil, nil)
+
+	test.favoriteRepo.EXPECT().
+		FilterFavorites(user.ID, []uuid.UUID{testAd.ID}).
+		Return([]uuid.UUID{}, nil)
+
+	get := func(userID *uuid.UUID) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/ads/"+testAd.ID.String(), nil)
+		req = mux.SetURLVars(req, map[string]string{"id": testAd.ID.String()})
+		if userID != nil {
+			req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, *userID))
+		}
+		w := httptest.NewRecorder()
+
+		handler := http.HandlerFunc(test.adController.GetAdByID)
+		handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	otherID := uuid.New()
+	assert.Equal(t, http.StatusNotFound, get(nil).Code)
+	assert.Equal(t, http.StatusNotFound, get(&otherID).Code)
+
+	w := get(&user.ID)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.True(t, resp.IsOwner)
+	assert.Equal(t, entity.AdStatusRejected, resp.Status)
+	assert.Equal(t, testAd.RejectionReason, resp.RejectionReason)
+}
+
+func TestAdController_GetAdByID_NotFound(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	adID := uuid.New()
+	test.adRepo.EXPECT().
+		FindByID(adID).
+		Return(nil, ad.ErrorAdNotFound)
+
+	testCases := []struct {
+		name string
+		id   string
+	}{
+		{
+			name: "unknown id",
+			id:   adID.String(),
+		},
+		{
+			name: "malformed id",
+			id:   "not-a-uuid",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/ads/"+tc.id, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.id})
+			w := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(test.adController.GetAdByID)
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+
+			var resp pkg.ErrorResponse
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Equal(t, ad.ErrorAdNotFound.Error(), resp.Error)
+		})
+	}
+}
+
+func TestAdController_GetAdByID_ServiceError(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	adID := uuid.New()
+	bdErr := "database error"
+	test.adRepo.EXPECT().
+		FindByID(adID).
+		Return(nil, errors.New(bdErr))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ads/"+adID.String(), nil)
+	req = mux.SetURLVars(req, map[string]string{"id": adID.String()})
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetAdByID)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, bdErr, resp.Error)
+}
+
+func TestAdController_PatchAd(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, user)
+	newTitle := "new title"
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.adRepo.EXPECT().
+		Update(gomock.Any()).
+		Do(func(updated *entity.Ad) {
+			assert.Equal(t, newTitle, updated.Title)
+			assert.Equal(t, textConst, updated.Text)
+			assert.False(t, updated.UpdatedAt.IsZero())
+		}).
+		Return(nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/ads/"+testAd.ID.String(),
+		bytes.NewBufferString(`{"title":"`+newTitle+`"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": testAd.ID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.PatchAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, newTitle, resp.Title)
+	assert.Equal(t, imageUrlConst, resp.ImageURL)
+	assert.True(t, resp.IsOwner)
+	assert.Equal(t, entity.AdStatusActive, resp.Status)
+	assert.NotNil(t, resp.UpdatedAt)
+}
+
+func TestAdController_PatchAd_NewExternalImage(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, user)
+	newImageURL := imageUrlConst + "?new"
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.adRepo.EXPECT().
+		Update(gomock.Any()).
+		Do(func(updated *entity.Ad) {
+			assert.Equal(t, entity.AdStatusPending, updated.Status)
+			assert.Equal(t, int64(1), updated.Revision)
+			assert.False(t, updated.Images[0].Pending)
+			assert.True(t, updated.Images[1].Pending)
+		}).
+		Return(nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/ads/"+testAd.ID.String(),
+		bytes.NewBufferString(`{"images":[{"url":"`+imageUrlConst+`"},{"url":"`+newImageURL+`"}]}`))
+	req = mux.SetURLVars(req, map[string]string{"id": testAd.ID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.PatchAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.AdStatusPending, resp.Status)
+	assert.Len(t, resp.Images, 2)
+}
+
+func TestAdController_PatchAd_ValidationErrors(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, user)
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.adRepo.EXPECT().
+		Update(gomock.Any()).
+		Times(0)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/ads/"+testAd.ID.String(),
+		bytes.NewBufferString(`{"title":"abc","price":-1}`))
+	req = mux.SetURLVars(req, map[string]string{"id": testAd.ID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.PatchAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp pkg.ValidationErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Contains(t, resp.Errors, "Title")
+	assert.Contains(t, resp.Errors, "Price")
+	assert.NotContains(t, resp.Errors, validator.ImageURLField)
+}
+
+func TestAdController_PatchAd_ImageID(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, user)
+	imageID := uuid.New()
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	test.imageRepo.EXPECT().
+		ExistsForOwner(imageID, user.ID).
+		Return(true, nil)
+
+	test.imageRepo.EXPECT().
+		FindByID(imageID).
+		Return(nil, errors.New("db is down"))
+
+	test.adRepo.EXPECT().
+		Update(gomock.Any()).
+		Return(nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/ads/"+testAd.ID.String(),
+		bytes.NewBufferString(`{"image_id":"`+imageID.String()+`"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": testAd.ID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.PatchAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, &imageID, resp.ImageID)
+	assert.Equal(t, service.MediaPathPrefix+imageID.String(), resp.ImageURL)
+	assert.Nil(t, resp.Thumbnails)
+}
+
+func TestAdController_PatchAd_Cover(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	testAd := entity.NewAd(titleConst, textConst,
+		[]entity.AdImage{{URL: imageUrlConst}, {URL: imageUrlConst + "?second"}}, 0, priceConst, categoryIDConst, user)
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	test.adRepo.EXPECT().
+		Update(gomock.Any()).
+		Do(func(updated *entity.Ad) {
+			assert.Equal(t, 1, updated.Cover)
+			assert.Len(t, updated.Images, 2)
+		}).
+		Return(nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/ads/"+testAd.ID.String(),
+		bytes.NewBufferString(`{"cover":1}`))
+	req = mux.SetURLVars(req, map[string]string{"id": testAd.ID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.PatchAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, imageUrlConst+"?second", resp.ImageURL)
+	assert.Equal(t, 1, resp.Cover)
+}
+
+func TestAdController_PatchAd_UnknownCategory(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, user)
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(int64(999)).
+		Return(false, nil)
+
+	test.adRepo.EXPECT().
+		Update(gomock.Any()).
+		Times(0)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/ads/"+testAd.ID.String(),
+		bytes.NewBufferString(`{"category_id":999}`))
+	req = mux.SetURLVars(req, map[string]string{"id": testAd.ID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.PatchAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp pkg.ValidationErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(validator.ReportUnknownCategory, 999), resp.Errors[validator.CategoryField])
+}
+
+func TestAdController_UpdateAd_Forbidden(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, &entity.User{ID: uuid.New()})
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.adRepo.EXPECT().
+		Update(gomock.Any()).
+		Times(0)
+
+	body, err := json.Marshal(dto.NewAdDTO(testAd))
+	if err != nil {
+		t.Errorf("error marshalling ad: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/ads/"+testAd.ID.String(), bytes.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"id": testAd.ID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, uuid.New()))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.UpdateAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var resp pkg.ErrorResponse
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, service.ErrorNotAdAuthor.Error(), resp.Error)
+}
+
+func TestAdController_UpdateAd_NotFound(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	adID := uuid.New()
+	test.adRepo.EXPECT().
+		FindByID(adID).
+		Return(nil, ad.ErrorAdNotFound)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/ads/"+adID.String(), bytes.NewBufferString(`{}`))
+	req = mux.SetURLVars(req, map[string]string{"id": adID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, uuid.New()))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.UpdateAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func publishAd(test *adControllerTest, adID, userID uuid.UUID) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/ads/"+adID.String()+"/publish", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": adID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.PublishAd)
+	handler.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestAdController_PublishAd(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	draft := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, user)
+	draft.MakeDraft()
+
+	test.adRepo.EXPECT().
+		FindByID(draft.ID).
+		Return(draft, nil)
+
+	test.adRepo.EXPECT().
+		Update(draft).
+		Return(nil)
+
+	listed := test.events.Subscribe(service.AdsTopic, 1)
+	defer listed.Close()
+
+	w := publishAd(test, draft.ID, user.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, entity.AdStatusActive, draft.Status)
+	assert.False(t, draft.ListedAt.IsZero())
+
+	select {
+	case event := <-listed.Events():
+		assert.Equal(t, entity.EventAdCreated, event.Type)
+		assert.Equal(t, draft, event.Payload)
+	default:
+		t.Error("published draft not announced as listed")
+	}
+
+	var resp dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.AdStatusActive, resp.Status)
+}
+
+func TestAdController_PublishAd_Expired(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	expired := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, user)
+	listedAt := time.Now().Add(-60 * 24 * time.Hour)
+	expired.Status = entity.AdStatusExpired
+	expired.ListedAt = listedAt
+
+	test.adRepo.EXPECT().
+		FindByID(expired.ID).
+		Return(expired, nil)
+
+	test.adRepo.EXPECT().
+		Update(expired).
+		Return(nil)
+
+	listed := test.events.Subscribe(service.AdsTopic, 1)
+	defer listed.Close()
+
+	w := publishAd(test, expired.ID, user.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, entity.AdStatusActive, expired.Status)
+	assert.Equal(t, listedAt, expired.ListedAt)
+	assert.Empty(t, listed.Events(), "relisted ad announced as new")
+}
+
+func TestAdController_PublishAd_Errors(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	active := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, user)
+	missingID := uuid.New()
+
+	test.adRepo.EXPECT().
+		FindByID(active.ID).
+		Return(active, nil).
+		Times(2)
+
+	test.adRepo.EXPECT().
+		FindByID(missingID).
+		Return(nil, ad.ErrorAdNotFound)
+
+	test.adRepo.EXPECT().
+		Update(gomock.Any()).
+		Times(0)
+
+	assert.Equal(t, http.StatusConflict, publishAd(test, active.ID, user.ID).Code)
+	assert.Equal(t, http.StatusForbidden, publishAd(test, active.ID, uuid.New()).Code)
+	assert.Equal(t, http.StatusNotFound, publishAd(test, missingID, user.ID).Code)
+}
+
+func TestAdController_PatchAd_Draft(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	draft := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, user)
+	draft.MakeDraft()
+
+	test.adRepo.EXPECT().
+		FindByID(draft.ID).
+		Return(draft, nil)
+
+	test.adRepo.EXPECT().
+		Update(draft).
+		Return(nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/ads/"+draft.ID.String(),
+		bytes.NewBufferString(`{"title":"new title"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": draft.ID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.PatchAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, entity.AdStatusDraft, draft.Status)
+	assert.True(t, draft.ListedAt.IsZero())
+}
+
+func TestAdController_DeleteAd(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New()}
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, user)
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.adRepo.EXPECT().
+		Delete(testAd.ID).
+		Return(nil)
+
+	test.favoriteRepo.EXPECT().
+		RemoveAd(testAd.ID).
+		Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/ads/"+testAd.ID.String(), nil)
+	req = mux.SetURLVars(req, map[string]string{"id": testAd.ID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.DeleteAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestAdController_DeleteAd_Forbidden(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, &entity.User{ID: uuid.New()})
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.adRepo.EXPECT().
+		Delete(gomock.Any()).
+		Times(0)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/ads/"+testAd.ID.String(), nil)
+	req = mux.SetURLVars(req, map[string]string{"id": testAd.ID.String()})
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, uuid.New()))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.DeleteAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAdController_DeleteAd_Unauthorized(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	adID := uuid.New()
+	req := httptest.NewRequest(http.MethodDelete, "/api/ads/"+adID.String(), nil)
+	req = mux.SetURLVars(req, map[string]string{"id": adID.String()})
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.DeleteAd)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, unauthorizedError, resp.Error)
+}
+
+func favoriteRequest(method string, adID, userID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(method, "/api/ads/"+adID.String()+"/favorite", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": adID.String()})
+
+	return req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+}
+
+func TestAdController_AddFavorite(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, &entity.User{ID: uuid.New()})
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.favoriteRepo.EXPECT().
+		CountByUser(userID).
+		Return(int64(3), nil)
+
+	test.favoriteRepo.EXPECT().
+		Add(userID, testAd.ID).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.adController.AddFavorite)
+	handler.ServeHTTP(w, favoriteRequest(http.MethodPost, testAd.ID, userID))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestAdController_AddFavorite_TooMany(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, &entity.User{ID: uuid.New()})
+	favoriteAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst, categoryIDConst, &entity.User{ID: uuid.New()})
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.adRepo.EXPECT().
+		FindByID(favoriteAd.ID).
+		Return(favoriteAd, nil)
+
+	test.favoriteRepo.EXPECT().
+		CountByUser(userID).
+		Return(int64(service.MaxFavorites), nil).
+		Times(2)
+
+	test.favoriteRepo.EXPECT().
+		FilterFavorites(userID, []uuid.UUID{testAd.ID}).
+		Return([]uuid.UUID{}, nil)
+
+	test.favoriteRepo.EXPECT().
+		FilterFavorites(userID, []uuid.UUID{favoriteAd.ID}).
+		Return([]uuid.UUID{favoriteAd.ID}, nil)
+
+	test.favoriteRepo.EXPECT().
+		Add(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	handler := http.HandlerFunc(test.adController.AddFavorite)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, favoriteRequest(http.MethodPost, testAd.ID, userID))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, service.ErrorTooManyFavorites.Error(), resp.Error)
+
+	// Favoriting an ad that already is a favorite stays idempotent at the cap.
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, favoriteRequest(http.MethodPost, favoriteAd.ID, userID))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestAdController_AddFavorite_NotFound(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	missingID := uuid.New()
+	pendingAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst, Pending: true}}, 0, priceConst, categoryIDConst, &entity.User{ID: uuid.New()})
+
+	test.adRepo.EXPECT().
+		FindByID(missingID).
+		Return(nil, ad.ErrorAdNotFound)
+
+	test.adRepo.EXPECT().
+		FindByID(pendingAd.ID).
+		Return(pendingAd, nil)
+
+	test.favoriteRepo.EXPECT().
+		Add(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	handler := http.HandlerFunc(test.adController.AddFavorite)
+
+	for _, adID := range []uuid.UUID{missingID, pendingAd.ID} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, favoriteRequest(http.MethodPost, adID, userID))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	}
+}
+
+func TestAdController_AddFavorite_Unauthorized(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	adID := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/ads/"+adID.String()+"/favorite", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": adID.String()})
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.AddFavorite)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAdController_RemoveFavorite(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	adID := uuid.New()
+
+	test.favoriteRepo.EXPECT().
+		Remove(userID, adID).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.adController.RemoveFavorite)
+	handler.ServeHTTP(w, favoriteRequest(http.MethodDelete, adID, userID))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestAdController_GetFavorites(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	seller := &entity.User{ID: uuid.New(), Username: "seller"}
+	ad1 := entity.NewAd("title1", "text1", []entity.AdImage{{URL: "image1"}}, 0, 100, categoryIDConst, seller)
+	ad2 := entity.NewAd("title2", "text2", []entity.AdImage{{URL: "image2"}}, 0, 200, categoryIDConst, seller)
+	deletedID := uuid.New()
+
+	test.favoriteRepo.EXPECT().
+		FindAdIDs(user.ID).
+		Return([]uuid.UUID{ad2.ID, deletedID, ad1.ID}, nil)
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Do(func(ops *entity.Options) {
+			assert.Equal(t, []uuid.UUID{ad2.ID, deletedID, ad1.ID}, ops.IDs)
+			assert.Equal(t, 2, ops.Limit)
+			assert.Equal(t, entity.SortByPrice, ops.SortBy)
+		}).
+		Return([]*entity.Ad{ad1, ad2}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings(gomock.Any()).
+		Return(nil, nil)
+
+	test.favoriteRepo.EXPECT().
+		FilterFavorites(user.ID, []uuid.UUID{ad1.ID, ad2.ID}).
+		Return([]uuid.UUID{ad1.ID, ad2.ID}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me/favorites?page=1&limit=2&sort_by=price", nil)
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, user.ID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetFavorites)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get("X-Next-Cursor"))
+
+	var resp []dto.AdResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+
+	for _, a := range resp {
+		assert.True(t, a.IsFavorite)
+		assert.False(t, a.IsOwner)
+	}
+}
+
+func TestAdController_GetFavorites_Empty(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+
+	test.favoriteRepo.EXPECT().
+		FindAdIDs(userID).
+		Return([]uuid.UUID{}, nil)
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Do(func(ops *entity.Options) {
+			assert.NotNil(t, ops.IDs)
+			assert.Empty(t, ops.IDs)
+		}).
+		Return(nil, ad.ErrorAdsNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me/favorites?page=1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetFavorites)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdController_GetFavorites_RepositoryError(t *testing.T) {
+	test := setUpAdControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+
+	test.favoriteRepo.EXPECT().
+		FindAdIDs(userID).
+		Return(nil, errors.New("connection refused"))
+
+	test.adRepo.EXPECT().
+		FindAll(gomock.Any()).
+		Times(0)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me/favorites?page=1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.adController.GetFavorites)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
 func TestAdController_GetIDFromToken(t *testing.T) {
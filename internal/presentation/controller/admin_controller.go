@@ -0,0 +1,256 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/ad"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const reportInvalidPagination = "page must be at least 1 and limit between 1 and %d"
+
+type AdminController struct {
+	userService     *service.UserService
+	adService       *service.AdService
+	favoriteService *service.FavoriteService
+}
+
+func NewAdminController(userService *service.UserService, adService *service.AdService,
+	favoriteService *service.FavoriteService) *AdminController {
+	return &AdminController{
+		userService:     userService,
+		adService:       adService,
+		favoriteService: favoriteService,
+	}
+}
+
+// GetUsers godoc
+//
+//	@Summary		List users
+//	@Description	Returns users sorted by username. Admins only
+//	@Tags			Admin
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page	query		int	false	"Page number"		default(1)
+//	@Param			limit	query		int	false	"Users per page"	default(10)
+//	@Success		200		{object}	dto.UserListResponse
+//	@Failure		400		{object}	pkg.ErrorResponse	"Invalid pagination"
+//	@Failure		401		{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		403		{object}	pkg.ErrorResponse	"Forbidden"
+//	@Failure		500		{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/admin/users [get]
+func (ac *AdminController) GetUsers(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdminController.GetUsers called")
+
+	page, limit, err := parsePagination(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	users, err := ac.userService.GetUsers(page, limit)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	total, err := ac.userService.CountUsers()
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := dto.UserListResponse{
+		Items: make([]*dto.UserResponse, 0, len(users)),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	for _, user := range users {
+		resp.Items = append(resp.Items, dto.NewUserResponse(user))
+	}
+
+	pkg.SendJSON(w, http.StatusOK, resp)
+}
+
+// BanUser godoc
+//
+//	@Summary		Ban user
+//	@Description	Blocks the user from logging in and revokes all their tokens. Admins only
+//	@Tags			Admin
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"User ID"	format(uuid)
+//	@Success		204
+//	@Failure		400	{object}	pkg.ErrorResponse	"Admin tried to ban themselves"
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	pkg.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	pkg.ErrorResponse	"User not found"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/admin/users/{id}/ban [post]
+func (ac *AdminController) BanUser(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdminController.BanUser called")
+
+	adminID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, service.ErrorUserWithIDDoesNotExists.Error())
+		return
+	}
+
+	if err = ac.userService.Ban(id, adminID); err != nil {
+		ac.handleAdminError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnbanUser godoc
+//
+//	@Summary		Unban user
+//	@Description	Lifts the ban so the user can log in again. Admins only
+//	@Tags			Admin
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"User ID"	format(uuid)
+//	@Success		204
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	pkg.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	pkg.ErrorResponse	"User not found"
+//	@Router			/api/admin/users/{id}/unban [post]
+func (ac *AdminController) UnbanUser(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdminController.UnbanUser called")
+
+	id, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, service.ErrorUserWithIDDoesNotExists.Error())
+		return
+	}
+
+	if err = ac.userService.Unban(id); err != nil {
+		ac.handleAdminError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteUser godoc
+//
+//	@Summary		Delete user
+//	@Description	Deletes the user with their favorites, saved searches, conversations and reviews; their ads are removed shortly afterwards. Access tokens issued to the user stay valid until they expire but can no longer be refreshed. Admins only
+//	@Tags			Admin
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"User ID"	format(uuid)
+//	@Success		204
+//	@Failure		400	{object}	pkg.ErrorResponse	"Admin tried to delete themselves"
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	pkg.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	pkg.ErrorResponse	"User not found"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/admin/users/{id} [delete]
+func (ac *AdminController) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdminController.DeleteUser called")
+
+	adminID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, service.ErrorUserWithIDDoesNotExists.Error())
+		return
+	}
+
+	if err = ac.userService.Delete(id, adminID); err != nil {
+		ac.handleAdminError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteAd godoc
+//
+//	@Summary		Delete any advertisement
+//	@Description	Deletes an ad regardless of its author. Moderators and admins only
+//	@Tags			Admin
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Ad ID"	format(uuid)
+//	@Success		204
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	pkg.ErrorResponse	"Forbidden"
+//	@Failure		404	{object}	pkg.ErrorResponse	"Ad not found"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/admin/ads/{id} [delete]
+func (ac *AdminController) DeleteAd(w http.ResponseWriter, r *http.Request) {
+	log.Print("AdminController.DeleteAd called")
+
+	id, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, ad.ErrorAdNotFound.Error())
+		return
+	}
+
+	if err = ac.adService.DeleteAny(id); err != nil {
+		ac.handleAdminError(w, err)
+		return
+	}
+	removeFavorites(ac.favoriteService, id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ac *AdminController) handleAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrorCannotBanYourself), errors.Is(err, service.ErrorCannotDeleteYourself):
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrorUserWithIDDoesNotExists),
+		errors.Is(err, ad.ErrorAdNotFound):
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+	default:
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func parsePagination(r *http.Request) (int, int, error) {
+	query := r.URL.Query()
+	page, limit := 1, entity.LimitDefaultValue
+
+	if pageStr := query.Get(entity.ParamPage); pageStr != "" {
+		value, err := strconv.Atoi(pageStr)
+		if err != nil {
+			return 0, 0, err
+		}
+		page = value
+	}
+
+	if limitStr := query.Get(entity.ParamLimit); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return 0, 0, err
+		}
+		limit = value
+	}
+
+	if page < 1 || limit < 1 || limit > entity.LimitMaxValue {
+		return 0, 0, fmt.Errorf(reportInvalidPagination, entity.LimitMaxValue)
+	}
+
+	return page, limit, nil
+}
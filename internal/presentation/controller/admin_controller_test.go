@@ -0,0 +1,426 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/middleware"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/pubsub"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/ad"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/user"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type adminControllerTest struct {
+	ctrl             *gomock.Controller
+	userRepo         *service.MockUserRepository
+	adRepo           *service.MockAdRepository
+	refreshTokenRepo *service.MockRefreshTokenRepository
+	revocationRepo   *service.MockRevocationRepository
+	favoriteRepo     *service.MockFavoriteRepository
+	jwtService       *service.JWTService
+	router           *mux.Router
+}
+
+// setUpAdminControllerTest wires the admin routes the same way main does, so
+// that the role checks are exercised together with the handlers.
+func setUpAdminControllerTest(t *testing.T) *adminControllerTest {
+	t.Helper()
+
+	t.Setenv(service.DotEnvJWTExpiration, "21600")
+	t.Setenv(service.DotEnvJWTSecret, "jwt-secret")
+
+	ctrl := gomock.NewController(t)
+
+	mockUserRepo := service.NewMockUserRepository(ctrl)
+	mockAdRepo := service.NewMockAdRepository(ctrl)
+	mockRefreshTokenRepo := service.NewMockRefreshTokenRepository(ctrl)
+	mockRevocationRepo := service.NewMockRevocationRepository(ctrl)
+	mockFavoriteRepo := service.NewMockFavoriteRepository(ctrl)
+
+	jwtService, err := service.NewJWTService()
+	if err != nil {
+		t.Fatalf("error creating JWT service: %v", err)
+	}
+	revocationService := service.NewRevocationService(mockRevocationRepo, time.Hour)
+	tokenService, err := service.NewTokenService(jwtService, mockRefreshTokenRepo, mockUserRepo, revocationService)
+	if err != nil {
+		t.Fatalf("error creating token service: %v", err)
+	}
+
+	adminController := NewAdminController(
+		service.NewUserService(mockUserRepo, tokenService),
+		service.NewAdService(mockAdRepo, pubsub.NewMemoryPubSub()),
+		service.NewFavoriteService(mockFavoriteRepo))
+
+	r := mux.NewRouter()
+	authorized := r.NewRoute().Subrouter()
+	authorized.Use(func(next http.Handler) http.Handler {
+		return middleware.AuthMiddleware(jwtService, revocationService, next)
+	})
+
+	moderation := authorized.PathPrefix("/api/admin/ads").Subrouter()
+	moderation.Use(middleware.RequireRole(entity.RoleModerator, entity.RoleAdmin))
+	moderation.HandleFunc("/{id}", adminController.DeleteAd).Methods(http.MethodDelete)
+
+	admin := authorized.PathPrefix("/api/admin/users").Subrouter()
+	admin.Use(middleware.RequireRole(entity.RoleAdmin))
+	admin.HandleFunc("", adminController.GetUsers).Methods(http.MethodGet)
+	admin.HandleFunc("/{id}/ban", adminController.BanUser).Methods(http.MethodPost)
+	admin.HandleFunc("/{id}/unban", adminController.UnbanUser).Methods(http.MethodPost)
+	admin.HandleFunc("/{id}", adminController.DeleteUser).Methods(http.MethodDelete)
+
+	mockRevocationRepo.EXPECT().
+		IsRevoked(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(false, nil).
+		AnyTimes()
+
+	return &adminControllerTest{
+		ctrl:             ctrl,
+		userRepo:         mockUserRepo,
+		adRepo:           mockAdRepo,
+		refreshTokenRepo: mockRefreshTokenRepo,
+		revocationRepo:   mockRevocationRepo,
+		favoriteRepo:     mockFavoriteRepo,
+		jwtService:       jwtService,
+		router:           r,
+	}
+}
+
+func (test *adminControllerTest) do(t *testing.T, method, target string, actor *entity.User) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, nil)
+	if actor != nil {
+		token, err := test.jwtService.GenerateToken(actor)
+		if err != nil {
+			t.Fatalf("error generating token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+*token)
+	}
+
+	w := httptest.NewRecorder()
+	test.router.ServeHTTP(w, req)
+
+	return w
+}
+
+func newActor(role string) *entity.User {
+	return &entity.User{ID: uuid.New(), Username: role, Role: role}
+}
+
+func TestAdminController_GetUsers(t *testing.T) {
+	test := setUpAdminControllerTest(t)
+	defer test.ctrl.Finish()
+
+	bannedAt := time.Now().UTC().Truncate(time.Second)
+	users := []*entity.User{
+		{ID: uuid.New(), Username: "alice", Password: "hash", Role: entity.RoleAdmin},
+		{ID: uuid.New(), Username: "bob", Password: "hash", Role: entity.RoleUser, BannedAt: &bannedAt},
+	}
+
+	test.userRepo.EXPECT().
+		FindAll(2, 2).
+		Return(users, nil)
+
+	test.userRepo.EXPECT().
+		Count().
+		Return(int64(4), nil)
+
+	w := test.do(t, http.MethodGet, "/api/admin/users?page=2&limit=2", newActor(entity.RoleAdmin))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "hash")
+
+	var resp dto.UserListResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Errorf("error decoding response body: %v", err)
+	}
+
+	assert.Equal(t, int64(4), resp.Total)
+	assert.Equal(t, 2, resp.Page)
+	assert.Equal(t, 2, resp.Limit)
+	if assert.Len(t, resp.Items, 2) {
+		assert.Equal(t, entity.RoleAdmin, resp.Items[0].Role)
+		assert.Nil(t, resp.Items[0].BannedAt)
+		assert.Equal(t, bannedAt, resp.Items[1].BannedAt.UTC())
+	}
+}
+
+func TestAdminController_GetUsers_InvalidPagination(t *testing.T) {
+	test := setUpAdminControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.userRepo.EXPECT().
+		FindAll(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	for _, query := range []string{"page=0", "limit=0", "limit=41", "page=abc"} {
+		w := test.do(t, http.MethodGet, "/api/admin/users?"+query, newActor(entity.RoleAdmin))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestAdminController_RoleChecks(t *testing.T) {
+	test := setUpAdminControllerTest(t)
+	defer test.ctrl.Finish()
+
+	adID := uuid.New().String()
+	userID := uuid.New().String()
+
+	testCases := []struct {
+		name           string
+		method         string
+		target         string
+		actor          *entity.User
+		expectedStatus int
+	}{
+		{
+			name:           "anonymous lists users",
+			method:         http.MethodGet,
+			target:         "/api/admin/users",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "user lists users",
+			method:         http.MethodGet,
+			target:         "/api/admin/users",
+			actor:          newActor(entity.RoleUser),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "moderator bans user",
+			method:         http.MethodPost,
+			target:         "/api/admin/users/" + userID + "/ban",
+			actor:          newActor(entity.RoleModerator),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "moderator deletes user",
+			method:         http.MethodDelete,
+			target:         "/api/admin/users/" + userID,
+			actor:          newActor(entity.RoleModerator),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "token without role deletes ad",
+			method:         http.MethodDelete,
+			target:         "/api/admin/ads/" + adID,
+			actor:          &entity.User{ID: uuid.New(), Username: usernameConst},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := test.do(t, tc.method, tc.target, tc.actor)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestAdminController_BanUser(t *testing.T) {
+	test := setUpAdminControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+
+	test.userRepo.EXPECT().
+		SetBannedAt(userID, gomock.Not(gomock.Nil())).
+		Return(nil)
+
+	test.revocationRepo.EXPECT().
+		RevokeAllForUser(userID, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	test.refreshTokenRepo.EXPECT().
+		RevokeAllForUser(userID).
+		Return(nil)
+
+	w := test.do(t, http.MethodPost, "/api/admin/users/"+userID.String()+"/ban", newActor(entity.RoleAdmin))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestAdminController_BanUser_Errors(t *testing.T) {
+	test := setUpAdminControllerTest(t)
+	defer test.ctrl.Finish()
+
+	admin := newActor(entity.RoleAdmin)
+	missingID := uuid.New()
+
+	test.userRepo.EXPECT().
+		SetBannedAt(missingID, gomock.Any()).
+		Return(user.ErrorUserNotFound)
+
+	w := test.do(t, http.MethodPost, "/api/admin/users/"+admin.ID.String()+"/ban", admin)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), service.ErrorCannotBanYourself.Error())
+
+	w = test.do(t, http.MethodPost, "/api/admin/users/"+missingID.String()+"/ban", admin)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = test.do(t, http.MethodPost, "/api/admin/users/not-a-uuid/ban", admin)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdminController_UnbanUser(t *testing.T) {
+	test := setUpAdminControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+
+	test.userRepo.EXPECT().
+		SetBannedAt(userID, nil).
+		Return(nil)
+
+	w := test.do(t, http.MethodPost, "/api/admin/users/"+userID.String()+"/unban", newActor(entity.RoleAdmin))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestAdminController_DeleteUser(t *testing.T) {
+	test := setUpAdminControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+
+	gomock.InOrder(
+		test.revocationRepo.EXPECT().
+			RevokeAllForUser(userID, gomock.Any(), gomock.Any()).
+			Return(nil),
+		test.refreshTokenRepo.EXPECT().
+			RevokeAllForUser(userID).
+			Return(nil),
+		test.userRepo.EXPECT().
+			Delete(userID).
+			Return(true, nil),
+	)
+
+	w := test.do(t, http.MethodDelete, "/api/admin/users/"+userID.String(), newActor(entity.RoleAdmin))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestAdminController_DeleteUser_TokenRejected(t *testing.T) {
+	test := setUpAdminControllerTest(t)
+	defer test.ctrl.Finish()
+
+	deleted := newActor(entity.RoleAdmin)
+	token, err := test.jwtService.GenerateToken(deleted)
+	if err != nil {
+		t.Fatalf("error generating token: %v", err)
+	}
+
+	test.revocationRepo.EXPECT().
+		RevokeAllForUser(deleted.ID, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	test.refreshTokenRepo.EXPECT().
+		RevokeAllForUser(deleted.ID).
+		Return(nil)
+
+	test.userRepo.EXPECT().
+		Delete(deleted.ID).
+		Return(true, nil)
+
+	test.userRepo.EXPECT().
+		FindAll(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	w := test.do(t, http.MethodDelete, "/api/admin/users/"+deleted.ID.String(), newActor(entity.RoleAdmin))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users", nil)
+	req.Header.Set("Authorization", "Bearer "+*token)
+	w = httptest.NewRecorder()
+	test.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAdminController_DeleteUser_Errors(t *testing.T) {
+	test := setUpAdminControllerTest(t)
+	defer test.ctrl.Finish()
+
+	admin := newActor(entity.RoleAdmin)
+	missingID := uuid.New()
+	failingID := uuid.New()
+	unrevokedID := uuid.New()
+
+	test.revocationRepo.EXPECT().
+		RevokeAllForUser(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(userID uuid.UUID, _, _ time.Time) error {
+			if userID == unrevokedID {
+				return errors.New("connection refused")
+			}
+			return nil
+		}).
+		Times(3)
+
+	test.refreshTokenRepo.EXPECT().
+		RevokeAllForUser(gomock.Any()).
+		Return(nil).
+		Times(2)
+
+	test.userRepo.EXPECT().
+		Delete(missingID).
+		Return(false, nil)
+
+	test.userRepo.EXPECT().
+		Delete(failingID).
+		Return(false, errors.New("connection refused"))
+
+	w := test.do(t, http.MethodDelete, "/api/admin/users/"+admin.ID.String(), admin)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), service.ErrorCannotDeleteYourself.Error())
+
+	w = test.do(t, http.MethodDelete, "/api/admin/users/"+missingID.String(), admin)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = test.do(t, http.MethodDelete, "/api/admin/users/"+failingID.String(), admin)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = test.do(t, http.MethodDelete, "/api/admin/users/"+unrevokedID.String(), admin)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = test.do(t, http.MethodDelete, "/api/admin/users/not-a-uuid", admin)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdminController_DeleteAd(t *testing.T) {
+	test := setUpAdminControllerTest(t)
+	defer test.ctrl.Finish()
+
+	adID := uuid.New()
+	missingID := uuid.New()
+
+	test.adRepo.EXPECT().
+		Delete(adID).
+		Return(nil)
+
+	test.favoriteRepo.EXPECT().
+		RemoveAd(adID).
+		Return(nil)
+
+	test.adRepo.EXPECT().
+		Delete(missingID).
+		Return(ad.ErrorAdNotFound)
+
+	w := test.do(t, http.MethodDelete, "/api/admin/ads/"+adID.String(), newActor(entity.RoleModerator))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = test.do(t, http.MethodDelete, "/api/admin/ads/"+missingID.String(), newActor(entity.RoleAdmin))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/pkg"
+)
+
+type CategoryController struct {
+	categoryService *service.CategoryService
+}
+
+func NewCategoryController(categoryService *service.CategoryService) *CategoryController {
+	return &CategoryController{
+		categoryService: categoryService,
+	}
+}
+
+// GetCategories godoc
+//
+//	@Summary		Get category tree
+//	@Description	Returns root categories with their subcategories nested in children
+//	@Tags			Categories
+//	@Produce		json
+//	@Success		200	{array}		entity.Category
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/categories [get]
+func (cc *CategoryController) GetCategories(w http.ResponseWriter, _ *http.Request) {
+	log.Print("CategoryController.GetCategories called")
+
+	tree, err := cc.categoryService.GetTree()
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	pkg.SendJSON(w, http.StatusOK, tree)
+}
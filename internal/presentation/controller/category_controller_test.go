@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type categoryControllerTest struct {
+	ctrl               *gomock.Controller
+	categoryRepo       *service.MockCategoryRepository
+	categoryController *CategoryController
+}
+
+func setUpCategoryControllerTest(t *testing.T) *categoryControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+
+	mockCategoryRepo := service.NewMockCategoryRepository(ctrl)
+	categoryService := service.NewCategoryService(mockCategoryRepo)
+
+	return &categoryControllerTest{
+		ctrl:               ctrl,
+		categoryRepo:       mockCategoryRepo,
+		categoryController: NewCategoryController(categoryService),
+	}
+}
+
+func TestCategoryController_GetCategories(t *testing.T) {
+	test := setUpCategoryControllerTest(t)
+	defer test.ctrl.Finish()
+
+	electronics, computers := int64(1), int64(3)
+
+	test.categoryRepo.EXPECT().
+		FindAll().
+		Return([]*entity.Category{
+			{ID: computers, ParentID: &electronics, Name: "Computers"},
+			{ID: electronics, Name: "Electronics"},
+			{ID: 4, ParentID: &computers, Name: "Laptops"},
+			{ID: 2, Name: "Vehicles"},
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/categories", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.categoryController.GetCategories)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []entity.Category
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+	assert.Equal(t, "Electronics", resp[0].Name)
+	assert.Equal(t, "Vehicles", resp[1].Name)
+	assert.Len(t, resp[0].Children, 1)
+	assert.Equal(t, "Computers", resp[0].Children[0].Name)
+	assert.Len(t, resp[0].Children[0].Children, 1)
+	assert.Equal(t, "Laptops", resp[0].Children[0].Children[0].Name)
+	assert.Empty(t, resp[1].Children)
+}
+
+func TestCategoryController_GetCategories_Empty(t *testing.T) {
+	test := setUpCategoryControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.categoryRepo.EXPECT().
+		FindAll().
+		Return(nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/categories", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.categoryController.GetCategories)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+}
+
+func TestCategoryController_GetCategories_ServiceError(t *testing.T) {
+	test := setUpCategoryControllerTest(t)
+	defer test.ctrl.Finish()
+
+	bdErr := "database error"
+	test.categoryRepo.EXPECT().
+		FindAll().
+		Return(nil, errors.New(bdErr))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/categories", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.categoryController.GetCategories)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, bdErr, resp.Error)
+}
@@ -0,0 +1,344 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/application/validator"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/ad"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type ConversationController struct {
+	conversationService *service.ConversationService
+	adService           *service.AdService
+	userService         *service.UserService
+	validator           *validator.MessageValidator
+}
+
+func NewConversationController(conversationService *service.ConversationService, adService *service.AdService,
+	userService *service.UserService, validator *validator.MessageValidator) *ConversationController {
+	return &ConversationController{
+		conversationService: conversationService,
+		adService:           adService,
+		userService:         userService,
+		validator:           validator,
+	}
+}
+
+// StartConversation godoc
+//
+//	@Summary		Contact the author of an advertisement
+//	@Description	Sends a message to the author of a listed ad. The first message starts a conversation; later ones are added to it
+//	@Tags			Conversations
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Ad ID"	format(uuid)
+//	@Param			message	body		dto.MessageDTO				true	"Text of the message"
+//	@Success		200		{object}	dto.ConversationResponse	"Message added to the existing conversation"
+//	@Success		201		{object}	dto.ConversationResponse	"Conversation started"
+//	@Failure		400		{object}	pkg.ValidationErrorResponse	"Validation or parsing error, or the ad is your own"
+//	@Failure		401		{object}	pkg.ErrorResponse			"Unauthorized"
+//	@Failure		404		{object}	pkg.ErrorResponse			"Ad not found"
+//	@Failure		500		{object}	pkg.ErrorResponse			"Internal server error"
+//	@Router			/api/ads/{id}/conversations [post]
+func (cc *ConversationController) StartConversation(w http.ResponseWriter, r *http.Request) {
+	log.Print("ConversationController.StartConversation called")
+
+	adID, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, ad.ErrorAdNotFound.Error())
+		return
+	}
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	messageDTO, ok := cc.decodeMessage(w, r)
+	if !ok {
+		return
+	}
+
+	foundAd, err := cc.adService.GetByID(adID)
+	if err != nil {
+		if errors.Is(err, ad.ErrorAdNotFound) {
+			pkg.SendError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if foundAd.Status != entity.AdStatusActive {
+		pkg.SendError(w, http.StatusNotFound, ad.ErrorAdNotFound.Error())
+		return
+	}
+
+	buyer, err := cc.userService.GetByID(userID)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	conversation, created, err := cc.conversationService.Start(foundAd, buyer, messageDTO.Text)
+	if err != nil {
+		cc.handleConversationError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+
+	pkg.SendJSON(w, status, dto.NewConversationResponse(conversation))
+}
+
+// GetConversations godoc
+//
+//	@Summary		Get conversations
+//	@Description	Returns the conversations of the authenticated user as a buyer or an author, the most recent first, with unread counts
+//	@Tags			Conversations
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page	query		int	false	"Page number"				default(1)
+//	@Param			limit	query		int	false	"Conversations per page"	default(10)
+//	@Success		200		{object}	dto.ConversationListResponse
+//	@Failure		400		{object}	pkg.ErrorResponse	"Invalid query params"
+//	@Failure		401		{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/me/conversations [get]
+func (cc *ConversationController) GetConversations(w http.ResponseWriter, r *http.Request) {
+	log.Print("ConversationController.GetConversations called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	page, limit, err := parsePagination(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	conversations, err := cc.conversationService.GetConversations(userID, page, limit)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	total, err := cc.conversationService.Count(userID)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	unread, err := cc.conversationService.CountUnread(userID)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := dto.ConversationListResponse{
+		Items:  make([]*dto.ConversationResponse, 0, len(conversations)),
+		Total:  total,
+		Unread: unread,
+		Page:   page,
+		Limit:  limit,
+	}
+	for _, conversation := range conversations {
+		resp.Items = append(resp.Items, dto.NewConversationResponse(conversation))
+	}
+
+	pkg.SendJSON(w, http.StatusOK, resp)
+}
+
+// GetMessages godoc
+//
+//	@Summary		Get messages of a conversation
+//	@Description	Returns the messages of a conversation of the authenticated user, newest first
+//	@Tags			Conversations
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id		path		string	true	"Conversation ID"	format(uuid)
+//	@Param			page	query		int		false	"Page number"		default(1)
+//	@Param			limit	query		int		false	"Messages per page"	default(10)
+//	@Success		200		{object}	dto.MessageListResponse
+//	@Failure		400		{object}	pkg.ErrorResponse	"Invalid query params"
+//	@Failure		401		{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		404		{object}	pkg.ErrorResponse	"Conversation not found"
+//	@Failure		500		{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/conversations/{id}/messages [get]
+func (cc *ConversationController) GetMessages(w http.ResponseWriter, r *http.Request) {
+	log.Print("ConversationController.GetMessages called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, service.ErrorConversationNotFound.Error())
+		return
+	}
+
+	page, limit, err := parsePagination(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	conversation, err := cc.conversationService.Get(id, userID)
+	if err != nil {
+		cc.handleConversationError(w, err)
+		return
+	}
+
+	messages, err := cc.conversationService.GetMessages(conversation, page, limit)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	total, err := cc.conversationService.CountMessages(conversation)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := dto.MessageListResponse{
+		Items: make([]*dto.MessageResponse, 0, len(messages)),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	for _, message := range messages {
+		resp.Items = append(resp.Items, dto.NewMessageResponse(message))
+	}
+
+	pkg.SendJSON(w, http.StatusOK, resp)
+}
+
+// SendMessage godoc
+//
+//	@Summary		Send a message
+//	@Description	Adds a message of the authenticated user to one of their conversations
+//	@Tags			Conversations
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Conversation ID"	format(uuid)
+//	@Param			message	body		dto.MessageDTO	true	"Text of the message"
+//	@Success		201		{object}	dto.MessageResponse
+//	@Failure		400		{object}	pkg.ValidationErrorResponse	"Validation or parsing error"
+//	@Failure		401		{object}	pkg.ErrorResponse			"Unauthorized"
+//	@Failure		404		{object}	pkg.ErrorResponse			"Conversation not found"
+//	@Failure		500		{object}	pkg.ErrorResponse			"Internal server error"
+//	@Router			/api/conversations/{id}/messages [post]
+func (cc *ConversationController) SendMessage(w http.ResponseWriter, r *http.Request) {
+	log.Print("ConversationController.SendMessage called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, service.ErrorConversationNotFound.Error())
+		return
+	}
+
+	messageDTO, ok := cc.decodeMessage(w, r)
+	if !ok {
+		return
+	}
+
+	message, err := cc.conversationService.Send(id, userID, messageDTO.Text)
+	if err != nil {
+		cc.handleConversationError(w, err)
+		return
+	}
+
+	pkg.SendJSON(w, http.StatusCreated, dto.NewMessageResponse(message))
+}
+
+// MarkConversationRead godoc
+//
+//	@Summary		Mark a conversation as read
+//	@Description	Marks the messages the authenticated user has received in the conversation so far as read
+//	@Tags			Conversations
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Conversation ID"	format(uuid)
+//	@Success		204
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	pkg.ErrorResponse	"Conversation not found"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/conversations/{id}/read [post]
+func (cc *ConversationController) MarkConversationRead(w http.ResponseWriter, r *http.Request) {
+	log.Print("ConversationController.MarkConversationRead called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, service.ErrorConversationNotFound.Error())
+		return
+	}
+
+	if err = cc.conversationService.MarkRead(id, userID); err != nil {
+		cc.handleConversationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeMessage has sent the error response when it reports false.
+func (cc *ConversationController) decodeMessage(w http.ResponseWriter, r *http.Request) (dto.MessageDTO, bool) {
+	messageDTO := dto.MessageDTO{}
+	if err := json.NewDecoder(r.Body).Decode(&messageDTO); err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return messageDTO, false
+	}
+	messageDTO.Text = strings.TrimSpace(messageDTO.Text)
+
+	if errs := cc.validator.Validate(messageDTO); errs != nil {
+		pkg.SendValidationError(w, http.StatusBadRequest, errs)
+		return messageDTO, false
+	}
+
+	return messageDTO, true
+}
+
+func (cc *ConversationController) handleConversationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrorConversationNotFound):
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrorConversationOwnAd):
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+	default:
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
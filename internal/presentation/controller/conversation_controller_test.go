@@ -0,0 +1,500 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/application/validator"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/pubsub"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/ad"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const messageTextConst = "Is it still available?"
+
+type conversationControllerTest struct {
+	ctrl                   *gomock.Controller
+	conversationRepo       *service.MockConversationRepository
+	adRepo                 *service.MockAdRepository
+	userRepo               *service.MockUserRepository
+	events                 *pubsub.MemoryPubSub
+	conversationController *ConversationController
+	buyer                  *entity.User
+	seller                 *entity.User
+}
+
+func setUpConversationControllerTest(t *testing.T) *conversationControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+
+	mockConversationRepo := service.NewMockConversationRepository(ctrl)
+	mockAdRepo := service.NewMockAdRepository(ctrl)
+	mockUserRepo := service.NewMockUserRepository(ctrl)
+	events := pubsub.NewMemoryPubSub()
+
+	conversationController := NewConversationController(
+		service.NewConversationService(mockConversationRepo, events),
+		service.NewAdService(mockAdRepo, events),
+		service.NewUserService(mockUserRepo, nil),
+		validator.NewMessageValidator(),
+	)
+
+	return &conversationControllerTest{
+		ctrl:                   ctrl,
+		conversationRepo:       mockConversationRepo,
+		adRepo:                 mockAdRepo,
+		userRepo:               mockUserRepo,
+		events:                 events,
+		conversationController: conversationController,
+		buyer:                  &entity.User{ID: uuid.New(), Username: "buyer"},
+		seller:                 &entity.User{ID: uuid.New(), Username: usernameConst},
+	}
+}
+
+func (test *conversationControllerTest) newConversation() *entity.Conversation {
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst,
+		categoryIDConst, test.seller)
+
+	return entity.NewConversation(testAd, test.buyer)
+}
+
+func newConversationRequest(method, target, id, body string, userID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"id": id})
+
+	if userID != uuid.Nil {
+		req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+	}
+
+	return req
+}
+
+func newMessageBody(t *testing.T, text string) string {
+	t.Helper()
+
+	body, err := json.Marshal(dto.MessageDTO{Text: text})
+	if err != nil {
+		t.Fatalf("error marshalling message: %v", err)
+	}
+
+	return string(body)
+}
+
+func TestConversationController_StartConversation(t *testing.T) {
+	test := setUpConversationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst,
+		categoryIDConst, test.seller)
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil).
+		Times(2)
+
+	test.userRepo.EXPECT().
+		GetByID(test.buyer.ID).
+		Return(test.buyer, nil).
+		Times(2)
+
+	var conversationID uuid.UUID
+	gomock.InOrder(
+		test.conversationRepo.EXPECT().
+			Start(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(c *entity.Conversation, m *entity.Message) (bool, error) {
+				assert.Equal(t, testAd.ID, c.AdID)
+				assert.Equal(t, titleConst, c.AdTitle)
+				assert.Equal(t, test.seller.ID, c.SellerID)
+				assert.Equal(t, test.buyer.ID, m.SenderID)
+				assert.Equal(t, c.ID, m.ConversationID)
+				assert.Equal(t, messageTextConst, m.Text)
+				conversationID = c.ID
+
+				return true, nil
+			}),
+		test.conversationRepo.EXPECT().
+			Start(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(c *entity.Conversation, m *entity.Message) (bool, error) {
+				c.ID = conversationID
+				m.ConversationID = conversationID
+
+				return false, nil
+			}),
+	)
+
+	handler := http.HandlerFunc(test.conversationController.StartConversation)
+	start := func() (*httptest.ResponseRecorder, dto.ConversationResponse) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newConversationRequest(http.MethodPost, "/api/ads/"+testAd.ID.String()+"/conversations",
+			testAd.ID.String(), newMessageBody(t, "  "+messageTextConst+"\n"), test.buyer.ID))
+
+		var resp dto.ConversationResponse
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(t, err)
+
+		return w, resp
+	}
+
+	w, resp := start()
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, conversationID, resp.ID)
+	assert.Equal(t, "buyer", resp.Buyer.Username)
+	assert.Equal(t, usernameConst, resp.Seller.Username)
+
+	w, resp = start()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, conversationID, resp.ID)
+}
+
+func TestConversationController_StartConversation_OwnAd(t *testing.T) {
+	test := setUpConversationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst,
+		categoryIDConst, test.seller)
+
+	test.adRepo.EXPECT().
+		FindByID(testAd.ID).
+		Return(testAd, nil)
+
+	test.userRepo.EXPECT().
+		GetByID(test.seller.ID).
+		Return(test.seller, nil)
+
+	test.conversationRepo.EXPECT().
+		Start(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.conversationController.StartConversation)
+	handler.ServeHTTP(w, newConversationRequest(http.MethodPost, "/api/ads/"+testAd.ID.String()+"/conversations",
+		testAd.ID.String(), newMessageBody(t, messageTextConst), test.seller.ID))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestConversationController_StartConversation_AdNotListed(t *testing.T) {
+	test := setUpConversationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	pendingAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst, Pending: true}}, 0,
+		priceConst, categoryIDConst, test.seller)
+	missingID := uuid.New()
+
+	test.adRepo.EXPECT().
+		FindByID(pendingAd.ID).
+		Return(pendingAd, nil)
+
+	test.adRepo.EXPECT().
+		FindByID(missingID).
+		Return(nil, ad.ErrorAdNotFound)
+
+	test.conversationRepo.EXPECT().
+		Start(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	handler := http.HandlerFunc(test.conversationController.StartConversation)
+	for _, id := range []string{pendingAd.ID.String(), missingID.String(), "not-a-uuid"} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newConversationRequest(http.MethodPost, "/api/ads/"+id+"/conversations", id,
+			newMessageBody(t, messageTextConst), test.buyer.ID))
+
+		assert.Equal(t, http.StatusNotFound, w.Code, id)
+	}
+}
+
+func TestConversationController_StartConversation_InvalidMessage(t *testing.T) {
+	test := setUpConversationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.adRepo.EXPECT().
+		FindByID(gomock.Any()).
+		Times(0)
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"empty", ""},
+		{"blank", " \n\t "},
+		{"too long", strings.Repeat("a", 2001)},
+	}
+
+	handler := http.HandlerFunc(test.conversationController.StartConversation)
+	adID := uuid.New().String()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, newConversationRequest(http.MethodPost, "/api/ads/"+adID+"/conversations", adID,
+				newMessageBody(t, tt.text), test.buyer.ID))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp pkg.ValidationErrorResponse
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Contains(t, resp.Errors, "Text")
+		})
+	}
+}
+
+func TestConversationController_StartConversation_Unauthorized(t *testing.T) {
+	test := setUpConversationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	adID := uuid.New().String()
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.conversationController.StartConversation)
+	handler.ServeHTTP(w, newConversationRequest(http.MethodPost, "/api/ads/"+adID+"/conversations", adID,
+		newMessageBody(t, messageTextConst), uuid.Nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestConversationController_GetConversations(t *testing.T) {
+	test := setUpConversationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	conversation := test.newConversation()
+	conversation.Unread = 2
+
+	test.conversationRepo.EXPECT().
+		FindByUser(test.seller.ID, 5, 0).
+		Return([]*entity.Conversation{conversation}, nil)
+
+	test.conversationRepo.EXPECT().
+		CountByUser(test.seller.ID).
+		Return(int64(1), nil)
+
+	test.conversationRepo.EXPECT().
+		CountUnread(test.seller.ID).
+		Return(int64(2), nil)
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.conversationController.GetConversations)
+	handler.ServeHTTP(w, newConversationRequest(http.MethodGet, "/api/me/conversations?limit=5", "", "",
+		test.seller.ID))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.ConversationListResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Total)
+	assert.Equal(t, int64(2), resp.Unread)
+	if assert.Len(t, resp.Items, 1) {
+		assert.Equal(t, conversation.ID, resp.Items[0].ID)
+		assert.Equal(t, int64(2), resp.Items[0].Unread)
+	}
+}
+
+func TestConversationController_GetMessages(t *testing.T) {
+	test := setUpConversationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	conversation := test.newConversation()
+	message := entity.NewMessage(conversation.ID, test.buyer.ID, messageTextConst)
+
+	test.conversationRepo.EXPECT().
+		FindByID(conversation.ID).
+		Return(conversation, nil).
+		AnyTimes()
+
+	test.conversationRepo.EXPECT().
+		FindMessages(conversation.ID, entity.LimitDefaultValue, entity.LimitDefaultValue).
+		Return([]*entity.Message{message}, nil).
+		Times(2)
+
+	test.conversationRepo.EXPECT().
+		CountMessages(conversation.ID).
+		Return(int64(11), nil).
+		Times(2)
+
+	handler := http.HandlerFunc(test.conversationController.GetMessages)
+	for _, participant := range []*entity.User{test.buyer, test.seller} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newConversationRequest(http.MethodGet,
+			"/api/conversations/"+conversation.ID.String()+"/messages?page=2", conversation.ID.String(), "",
+			participant.ID))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp dto.MessageListResponse
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), resp.Total)
+		assert.Equal(t, 2, resp.Page)
+		if assert.Len(t, resp.Items, 1) {
+			assert.Equal(t, message.ID, resp.Items[0].ID)
+			assert.Equal(t, messageTextConst, resp.Items[0].Text)
+		}
+	}
+}
+
+func TestConversationController_NotParticipant(t *testing.T) {
+	test := setUpConversationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	conversation := test.newConversation()
+	strangerID := uuid.New()
+
+	test.conversationRepo.EXPECT().
+		FindByID(conversation.ID).
+		Return(conversation, nil).
+		Times(3)
+
+	test.conversationRepo.EXPECT().
+		FindMessages(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(0)
+
+	test.conversationRepo.EXPECT().
+		SaveMessage(gomock.Any()).
+		Times(0)
+
+	test.conversationRepo.EXPECT().
+		MarkRead(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(0)
+
+	id := conversation.ID.String()
+	requests := []struct {
+		handler http.HandlerFunc
+		req     *http.Request
+	}{
+		{test.conversationController.GetMessages, newConversationRequest(http.MethodGet,
+			"/api/conversations/"+id+"/messages", id, "", strangerID)},
+		{test.conversationController.SendMessage, newConversationRequest(http.MethodPost,
+			"/api/conversations/"+id+"/messages", id, newMessageBody(t, messageTextConst), strangerID)},
+		{test.conversationController.MarkConversationRead, newConversationRequest(http.MethodPost,
+			"/api/conversations/"+id+"/read", id, "", strangerID)},
+	}
+
+	for _, r := range requests {
+		w := httptest.NewRecorder()
+		r.handler.ServeHTTP(w, r.req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code, r.req.URL.Path)
+	}
+}
+
+func TestConversationController_GetMessages_NotFound(t *testing.T) {
+	test := setUpConversationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	missingID := uuid.New()
+
+	test.conversationRepo.EXPECT().
+		FindByID(missingID).
+		Return(nil, nil)
+
+	handler := http.HandlerFunc(test.conversationController.GetMessages)
+	for _, id := range []string{missingID.String(), "not-a-uuid"} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newConversationRequest(http.MethodGet, "/api/conversations/"+id+"/messages", id, "",
+			test.buyer.ID))
+
+		assert.Equal(t, http.StatusNotFound, w.Code, id)
+	}
+}
+
+func TestConversationController_SendMessage(t *testing.T) {
+	test := setUpConversationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	conversation := test.newConversation()
+
+	test.conversationRepo.EXPECT().
+		FindByID(conversation.ID).
+		Return(conversation, nil)
+
+	test.conversationRepo.EXPECT().
+		SaveMessage(gomock.Any()).
+		Do(func(m *entity.Message) {
+			assert.Equal(t, conversation.ID, m.ConversationID)
+			assert.Equal(t, test.seller.ID, m.SenderID)
+			assert.Equal(t, "Yes, it is", m.Text)
+		}).
+		Return(nil)
+
+	buyerEvents := test.events.Subscribe(service.UserTopic(test.buyer.ID), 1)
+	defer buyerEvents.Close()
+	sellerEvents := test.events.Subscribe(service.UserTopic(test.seller.ID), 1)
+	defer sellerEvents.Close()
+
+	id := conversation.ID.String()
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.conversationController.SendMessage)
+	handler.ServeHTTP(w, newConversationRequest(http.MethodPost, "/api/conversations/"+id+"/messages", id,
+		newMessageBody(t, "Yes, it is"), test.seller.ID))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp dto.MessageResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, conversation.ID, resp.ConversationID)
+	assert.Equal(t, test.seller.ID, resp.SenderID)
+	assert.Nil(t, resp.ReadAt)
+
+	for _, sub := range []service.Subscription{buyerEvents, sellerEvents} {
+		select {
+		case event := <-sub.Events():
+			assert.Equal(t, entity.EventMessageCreated, event.Type)
+			assert.Equal(t, resp.ID, event.Payload.(*entity.Message).ID)
+		default:
+			t.Error("message not published to a participant")
+		}
+	}
+}
+
+func TestConversationController_SendMessage_InvalidBody(t *testing.T) {
+	test := setUpConversationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.conversationRepo.EXPECT().
+		FindByID(gomock.Any()).
+		Times(0)
+
+	id := uuid.New().String()
+	handler := http.HandlerFunc(test.conversationController.SendMessage)
+	for _, body := range []string{"{", newMessageBody(t, "")} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newConversationRequest(http.MethodPost, "/api/conversations/"+id+"/messages", id, body,
+			test.buyer.ID))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestConversationController_MarkConversationRead(t *testing.T) {
+	test := setUpConversationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	conversation := test.newConversation()
+
+	test.conversationRepo.EXPECT().
+		FindByID(conversation.ID).
+		Return(conversation, nil)
+
+	test.conversationRepo.EXPECT().
+		MarkRead(conversation.ID, test.buyer.ID, gomock.Any()).
+		Return(nil)
+
+	id := conversation.ID.String()
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.conversationController.MarkConversationRead)
+	handler.ServeHTTP(w, newConversationRequest(http.MethodPost, "/api/conversations/"+id+"/read", id, "",
+		test.buyer.ID))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
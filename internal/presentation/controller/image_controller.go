@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/application/validator"
+	"github.com/alishashelby/marketplace/internal/infrastructure/blob"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/image"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// maxUploadBodySize leaves room for the multipart framing.
+	maxUploadBodySize  = 6 * 1024 * 1024
+	mediaCacheControl  = "public, max-age=31536000, immutable"
+	reportMissingImage = "multipart form field image is required"
+	pathParamVariant   = "variant"
+)
+
+type ImageController struct {
+	imageService *service.ImageService
+	validator    *validator.ImageValidator
+}
+
+func NewImageController(imageService *service.ImageService, validator *validator.ImageValidator) *ImageController {
+	return &ImageController{
+		imageService: imageService,
+		validator:    validator,
+	}
+}
+
+// UploadImage godoc
+//
+//	@Summary		Upload an image
+//	@Description	Stores a JPEG, PNG or GIF image of at most 5 MB together with its thumbnail and medium variants. The returned id can be used as image_id of an ad
+//	@Tags			Images
+//	@Security		BearerAuth
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			image	formData	file	true	"Image file"
+//	@Success		201		{object}	dto.ImageResponse
+//	@Failure		400		{object}	pkg.ValidationErrorResponse	"Validation error"
+//	@Failure		401		{object}	pkg.ErrorResponse			"Unauthorized"
+//	@Failure		500		{object}	pkg.ErrorResponse			"Internal server error"
+//	@Router			/api/images [post]
+func (ic *ImageController) UploadImage(w http.ResponseWriter, r *http.Request) {
+	log.Print("ImageController.UploadImage called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBodySize)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			pkg.SendValidationError(w, http.StatusBadRequest,
+				map[string]string{validator.ImageField: reportMissingImage})
+			return
+		}
+		if err != nil {
+			pkg.SendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if part.FormName() != validator.ImageField {
+			continue
+		}
+
+		data, config, errs := ic.validator.Validate(part.Header.Get(validator.ContentTypeKey), part)
+		if errs != nil {
+			pkg.SendValidationError(w, http.StatusBadRequest, errs)
+			return
+		}
+
+		img, err := ic.imageService.Upload(userID, part.Header.Get(validator.ContentTypeKey), data, config)
+		switch {
+		case errors.Is(err, service.ErrorUndecodableImage), errors.Is(err, service.ErrorImageTooLarge):
+			pkg.SendValidationError(w, http.StatusBadRequest, map[string]string{validator.ImageField: err.Error()})
+			return
+		case err != nil:
+			pkg.SendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		pkg.SendJSON(w, http.StatusCreated,
+			dto.NewImageResponse(img, ic.imageService.URL(img.ID), ic.imageService.VariantURLs(img)))
+		return
+	}
+}
+
+// GetMedia godoc
+//
+//	@Summary		Get an uploaded image
+//	@Description	Serves the bytes of an uploaded image
+//	@Tags			Images
+//	@Produce		image/jpeg,image/png,image/gif
+//	@Param			id	path		string	true	"Image ID"	format(uuid)
+//	@Success		200	{file}		binary
+//	@Failure		404	{object}	pkg.ErrorResponse	"Image not found"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/media/{id} [get]
+func (ic *ImageController) GetMedia(w http.ResponseWriter, r *http.Request) {
+	log.Print("ImageController.GetMedia called")
+
+	ic.sendMedia(w, r, "")
+}
+
+// GetMediaVariant godoc
+//
+//	@Summary		Get a downscaled image
+//	@Description	Serves a variant of an uploaded or imported external image
+//	@Tags			Images
+//	@Produce		image/jpeg,image/png
+//	@Param			id		path		string	true	"Image ID"	format(uuid)
+//	@Param			variant	path		string	true	"Variant"	Enums(thumbnail, medium)
+//	@Success		200		{file}		binary
+//	@Failure		404		{object}	pkg.ErrorResponse	"Image not found"
+//	@Failure		500		{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/media/{id}/{variant} [get]
+func (ic *ImageController) GetMediaVariant(w http.ResponseWriter, r *http.Request) {
+	log.Print("ImageController.GetMediaVariant called")
+
+	ic.sendMedia(w, r, mux.Vars(r)[pathParamVariant])
+}
+
+func (ic *ImageController) sendMedia(w http.ResponseWriter, r *http.Request, variant string) {
+	imageID, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, image.ErrorImageNotFound.Error())
+		return
+	}
+
+	img, body, err := ic.imageService.Open(imageID, variant)
+	if err != nil {
+		switch {
+		case errors.Is(err, image.ErrorImageNotFound), errors.Is(err, service.ErrorVariantNotFound),
+			errors.Is(err, blob.ErrorBlobNotFound):
+			pkg.SendError(w, http.StatusNotFound, image.ErrorImageNotFound.Error())
+		default:
+			pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	defer body.Close() //nolint:errcheck
+
+	w.Header().Set(validator.ContentTypeKey, img.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(img.Size, 10))
+	w.Header().Set("Cache-Control", mediaCacheControl)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = io.Copy(w, body); err != nil {
+		log.Printf("ImageController failed to send image %s: %v", imageID, err)
+	}
+}
@@ -0,0 +1,432 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/application/validator"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/blob"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/image"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	goimage "image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+)
+
+type imageControllerTest struct {
+	ctrl            *gomock.Controller
+	imageRepo       *service.MockImageRepository
+	blobStore       *service.MockBlobStore
+	imageController *ImageController
+}
+
+func setUpImageControllerTest(t *testing.T) *imageControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+
+	mockImageRepo := service.NewMockImageRepository(ctrl)
+	mockBlobStore := service.NewMockBlobStore(ctrl)
+	imageService := service.NewImageService(mockImageRepo, mockBlobStore, "")
+
+	return &imageControllerTest{
+		ctrl:            ctrl,
+		imageRepo:       mockImageRepo,
+		blobStore:       mockBlobStore,
+		imageController: NewImageController(imageService, validator.NewImageValidator()),
+	}
+}
+
+func newPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, goimage.NewRGBA(goimage.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("error encoding png: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func newUploadRequest(t *testing.T, field, contentType string, data []byte, userID uuid.UUID) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename="cat.png"`, field))
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatalf("error creating part: %v", err)
+	}
+	if _, err = part.Write(data); err != nil {
+		t.Fatalf("error writing part: %v", err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatalf("error closing writer: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/images", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+}
+
+func TestImageController_UploadImage(t *testing.T) {
+	test := setUpImageControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	data := newPNG(t, 40, 30)
+
+	var stored *entity.Image
+	test.blobStore.EXPECT().
+		Put(gomock.Any(), "image/png", data).
+		Return(nil)
+
+	variants := make(map[string][]byte)
+	test.blobStore.EXPECT().
+		Put(gomock.Any(), "image/png", gomock.Any()).
+		Do(func(key, _ string, variant []byte) {
+			variants[key] = variant
+		}).
+		Times(2).
+		Return(nil)
+
+	test.imageRepo.EXPECT().
+		Save(gomock.Any()).
+		Do(func(saved *entity.Image) {
+			stored = saved
+		}).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	test.imageController.UploadImage(w, newUploadRequest(t, validator.ImageField, "image/png", data, userID))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp dto.ImageResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, stored.ID, resp.ID)
+	assert.Equal(t, userID, stored.OwnerID)
+	assert.Equal(t, service.MediaPathPrefix+stored.ID.String(), resp.URL)
+	assert.Equal(t, "image/png", resp.ContentType)
+	assert.Equal(t, int64(len(data)), resp.Size)
+	assert.Equal(t, 40, resp.Width)
+	assert.Equal(t, 30, resp.Height)
+	assert.Equal(t, &dto.Thumbnails{
+		Thumbnail: service.MediaPathPrefix + stored.ID.String() + "/thumbnail",
+		Medium:    service.MediaPathPrefix + stored.ID.String() + "/medium",
+	}, resp.Thumbnails)
+
+	// The image fits into both variants, so they keep its size.
+	for name, variant := range stored.Variants {
+		assert.Equal(t, stored.VariantKey(name), variant.Key)
+		assert.Equal(t, 40, variant.Width)
+		assert.Equal(t, 30, variant.Height)
+		assert.Contains(t, variants, variant.Key)
+	}
+}
+
+func TestImageController_UploadImage_Downscales(t *testing.T) {
+	test := setUpImageControllerTest(t)
+	defer test.ctrl.Finish()
+
+	src := goimage.NewRGBA(goimage.Rect(0, 0, 1600, 400))
+	for x := 0; x < 1600; x++ {
+		for y := 0; y < 400; y++ {
+			// Alternating columns average out to gray.
+			if x%2 == 0 {
+				src.Set(x, y, color.White)
+			} else {
+				src.Set(x, y, color.Black)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, src, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatalf("error encoding jpeg: %v", err)
+	}
+	data := buf.Bytes()
+
+	variants := make(map[string][]byte)
+	test.blobStore.EXPECT().
+		Put(gomock.Any(), "image/jpeg", gomock.Any()).
+		Do(func(key, _ string, variant []byte) {
+			variants[key] = variant
+		}).
+		Times(3).
+		Return(nil)
+
+	var stored *entity.Image
+	test.imageRepo.EXPECT().
+		Save(gomock.Any()).
+		Do(func(saved *entity.Image) {
+			stored = saved
+		}).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	test.imageController.UploadImage(w, newUploadRequest(t, validator.ImageField, "image/jpeg", data, uuid.New()))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	expected := map[string]goimage.Point{
+		entity.VariantThumbnail: {X: 240, Y: 60},
+		entity.VariantMedium:    {X: 800, Y: 200},
+	}
+	for name, size := range expected {
+		variant := stored.Variants[name]
+		assert.Equal(t, "image/jpeg", variant.ContentType)
+		assert.Equal(t, size, goimage.Pt(variant.Width, variant.Height))
+
+		decoded, err := jpeg.Decode(bytes.NewReader(variants[variant.Key]))
+		if assert.NoError(t, err) {
+			assert.Equal(t, size, decoded.Bounds().Size())
+
+			gray := color.GrayModel.Convert(decoded.At(size.X/2, size.Y/2)).(color.Gray)
+			assert.InDelta(t, 128, int(gray.Y), 24)
+		}
+	}
+}
+
+func TestImageController_UploadImage_SaveErrorDeletesBlob(t *testing.T) {
+	test := setUpImageControllerTest(t)
+	defer test.ctrl.Finish()
+
+	data := newPNG(t, 10, 10)
+
+	var key string
+	test.blobStore.EXPECT().
+		Put(gomock.Any(), "image/png", data).
+		Times(1).
+		Do(func(k, _ string, _ []byte) {
+			key = k
+		}).
+		Return(nil)
+
+	test.blobStore.EXPECT().
+		Put(gomock.Any(), "image/png", gomock.Any()).
+		Times(2).
+		Return(nil)
+
+	test.imageRepo.EXPECT().
+		Save(gomock.Any()).
+		Return(image.ErrorFailedToSaveImage)
+
+	deleted := make(map[string]bool)
+	test.blobStore.EXPECT().
+		Delete(gomock.Any()).
+		Do(func(k string) {
+			deleted[k] = true
+		}).
+		Times(3).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	test.imageController.UploadImage(w, newUploadRequest(t, validator.ImageField, "image/png", data, uuid.New()))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.True(t, deleted[key])
+}
+
+func TestImageController_UploadImage_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		field       string
+		contentType string
+		data        []byte
+		report      string
+	}{
+		{
+			name:        "unsupported content type",
+			field:       validator.ImageField,
+			contentType: "image/webp",
+			data:        []byte("webp"),
+			report:      fmt.Sprintf(validator.ReportErrorUnsupportedContentType, "image/webp"),
+		},
+		{
+			name:        "not an image",
+			field:       validator.ImageField,
+			contentType: "image/png",
+			data:        []byte("definitely not a png"),
+			report:      "error - invalid image format: image: unknown format",
+		},
+		{
+			name:        "missing image field",
+			field:       "file",
+			contentType: "image/png",
+			data:        []byte("data"),
+			report:      reportMissingImage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setUpImageControllerTest(t)
+			defer test.ctrl.Finish()
+
+			test.blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			w := httptest.NewRecorder()
+			test.imageController.UploadImage(w, newUploadRequest(t, tt.field, tt.contentType, tt.data, uuid.New()))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp pkg.ValidationErrorResponse
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.report, resp.Errors[validator.ImageField])
+		})
+	}
+}
+
+func TestImageController_UploadImage_TooLarge(t *testing.T) {
+	test := setUpImageControllerTest(t)
+	defer test.ctrl.Finish()
+
+	data := make([]byte, maxUploadBodySize)
+
+	w := httptest.NewRecorder()
+	test.imageController.UploadImage(w, newUploadRequest(t, validator.ImageField, "image/png", data, uuid.New()))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp pkg.ValidationErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Contains(t, resp.Errors[validator.ImageField], "image too large")
+}
+
+func TestImageController_UploadImage_NotMultipart(t *testing.T) {
+	test := setUpImageControllerTest(t)
+	defer test.ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/images", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, uuid.New()))
+
+	w := httptest.NewRecorder()
+	test.imageController.UploadImage(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestImageController_UploadImage_Unauthorized(t *testing.T) {
+	test := setUpImageControllerTest(t)
+	defer test.ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/images", nil)
+
+	w := httptest.NewRecorder()
+	test.imageController.UploadImage(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestImageController_GetMedia(t *testing.T) {
+	test := setUpImageControllerTest(t)
+	defer test.ctrl.Finish()
+
+	data := newPNG(t, 10, 10)
+	img := entity.NewImage(uuid.New(), "image/png", int64(len(data)), 10, 10)
+
+	test.imageRepo.EXPECT().
+		FindByID(img.ID).
+		Return(img, nil)
+
+	test.blobStore.EXPECT().
+		Get(img.Key).
+		Return(io.NopCloser(bytes.NewReader(data)), nil)
+
+	req := httptest.NewRequest(http.MethodGet, service.MediaPathPrefix+img.ID.String(), nil)
+	req = mux.SetURLVars(req, map[string]string{"id": img.ID.String()})
+
+	w := httptest.NewRecorder()
+	test.imageController.GetMedia(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, fmt.Sprint(len(data)), w.Header().Get("Content-Length"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, data, w.Body.Bytes())
+}
+
+func TestImageController_GetMedia_NotFound(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    string
+		setup func(test *imageControllerTest, id uuid.UUID)
+		code  int
+	}{
+		{
+			name:  "invalid id",
+			id:    "not-a-uuid",
+			setup: func(*imageControllerTest, uuid.UUID) {},
+			code:  http.StatusNotFound,
+		},
+		{
+			name: "unknown image",
+			id:   uuid.New().String(),
+			setup: func(test *imageControllerTest, id uuid.UUID) {
+				test.imageRepo.EXPECT().FindByID(id).Return(nil, image.ErrorImageNotFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name: "missing blob",
+			id:   uuid.New().String(),
+			setup: func(test *imageControllerTest, id uuid.UUID) {
+				test.imageRepo.EXPECT().FindByID(id).Return(&entity.Image{ID: id, Key: "images/" + id.String()}, nil)
+				test.blobStore.EXPECT().Get("images/"+id.String()).Return(nil, blob.ErrorBlobNotFound)
+			},
+			code: http.StatusNotFound,
+		},
+		{
+			name: "repository error",
+			id:   uuid.New().String(),
+			setup: func(test *imageControllerTest, id uuid.UUID) {
+				test.imageRepo.EXPECT().FindByID(id).Return(nil, errors.New("db is down"))
+			},
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setUpImageControllerTest(t)
+			defer test.ctrl.Finish()
+
+			id, _ := uuid.Parse(tt.id)
+			tt.setup(test, id)
+
+			req := httptest.NewRequest(http.MethodGet, service.MediaPathPrefix+tt.id, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+
+			w := httptest.NewRecorder()
+			test.imageController.GetMedia(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
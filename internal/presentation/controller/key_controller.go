@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/pkg"
+)
+
+const jwksCacheControl = "public, max-age=300"
+
+type KeyController struct {
+	jwtService *service.JWTService
+}
+
+func NewKeyController(jwtService *service.JWTService) *KeyController {
+	return &KeyController{
+		jwtService: jwtService,
+	}
+}
+
+// GetJWKS godoc
+//
+//	@Summary		Get token verification keys
+//	@Description	Returns the public keys access tokens are signed with as a JSON Web Key Set. Tokens name their key in the kid header. Empty when tokens are signed with a shared secret
+//	@Tags			users
+//	@Produce		json
+//	@Success		200	{object}	entity.JSONWebKeySet
+//	@Router			/.well-known/jwks.json [get]
+func (kc *KeyController) GetJWKS(w http.ResponseWriter, _ *http.Request) {
+	log.Print("KeyController.GetJWKS called")
+
+	w.Header().Set("Cache-Control", jwksCacheControl)
+	pkg.SendJSON(w, http.StatusOK, kc.jwtService.PublicKeys())
+}
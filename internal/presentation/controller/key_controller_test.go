@@ -0,0 +1,356 @@
+package controller
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	rsaKeyID     = "rsa-2026-09"
+	ed25519KeyID = "ed-2026-10"
+	publicKeyID  = "partner"
+)
+
+func writeKeyFile(t *testing.T, dir, kid, blockType string, der []byte) {
+	t.Helper()
+
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600); err != nil {
+		t.Fatalf("error writing key: %v", err)
+	}
+}
+
+func setUpKeysDir(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating RSA key: %v", err)
+	}
+	writeKeyFile(t, dir, rsaKeyID, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating Ed25519 key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatalf("error marshalling Ed25519 key: %v", err)
+	}
+	writeKeyFile(t, dir, ed25519KeyID, "PRIVATE KEY", der)
+
+	partnerKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating Ed25519 key: %v", err)
+	}
+	der, err = x509.MarshalPKIXPublicKey(partnerKey)
+	if err != nil {
+		t.Fatalf("error marshalling Ed25519 public key: %v", err)
+	}
+	writeKeyFile(t, dir, publicKeyID, "PUBLIC KEY", der)
+
+	return dir
+}
+
+func newJWTServiceWithKeys(t *testing.T, dir, signingKeyID string) *service.JWTService {
+	t.Helper()
+
+	t.Setenv(service.DotEnvJWTExpiration, "3600")
+	t.Setenv(service.DotEnvJWTSecret, "")
+	t.Setenv(service.DotEnvJWTKeysDir, dir)
+	t.Setenv(service.DotEnvJWTSigningKeyID, signingKeyID)
+
+	jwtService, err := service.NewJWTService()
+	if err != nil {
+		t.Fatalf("error creating JWT service: %v", err)
+	}
+
+	return jwtService
+}
+
+func TestKeyController_GetJWKS(t *testing.T) {
+	dir := setUpKeysDir(t)
+	t.Setenv(service.DotEnvJWTRetiredKeys, rsaKeyID+"="+time.Now().Add(-time.Minute).Format(time.RFC3339))
+	jwtService := newJWTServiceWithKeys(t, dir, ed25519KeyID)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(NewKeyController(jwtService).GetJWKS)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Cache-Control"))
+
+	var resp entity.JSONWebKeySet
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Errorf("error decoding response body: %v", err)
+	}
+
+	if assert.Len(t, resp.Keys, 3) {
+		assert.Equal(t, ed25519KeyID, resp.Keys[0].Kid)
+		assert.Equal(t, "OKP", resp.Keys[0].Kty)
+		assert.Equal(t, "EdDSA", resp.Keys[0].Alg)
+		assert.Equal(t, "Ed25519", resp.Keys[0].Crv)
+		assert.NotEmpty(t, resp.Keys[0].X)
+
+		assert.Equal(t, publicKeyID, resp.Keys[1].Kid)
+
+		assert.Equal(t, rsaKeyID, resp.Keys[2].Kid)
+		assert.Equal(t, "RSA", resp.Keys[2].Kty)
+		assert.Equal(t, "RS256", resp.Keys[2].Alg)
+		assert.Equal(t, "AQAB", resp.Keys[2].E)
+		assert.NotEmpty(t, resp.Keys[2].N)
+	}
+}
+
+func TestKeyController_GetJWKS_SharedSecret(t *testing.T) {
+	t.Setenv(service.DotEnvJWTExpiration, "3600")
+	t.Setenv(service.DotEnvJWTSecret, "jwt-secret")
+	t.Setenv(service.DotEnvJWTKeysDir, "")
+
+	jwtService, err := service.NewJWTService()
+	if err != nil {
+		t.Fatalf("error creating JWT service: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(NewKeyController(jwtService).GetJWKS)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"keys":[]}`, w.Body.String())
+}
+
+func TestJWTService_KeyRotation(t *testing.T) {
+	dir := setUpKeysDir(t)
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+
+	oldService := newJWTServiceWithKeys(t, dir, rsaKeyID)
+	oldToken, err := oldService.GenerateToken(user)
+	if err != nil {
+		t.Fatalf("error generating token: %v", err)
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(*oldToken, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("error parsing token: %v", err)
+	}
+	assert.Equal(t, rsaKeyID, parsed.Header["kid"])
+	assert.Equal(t, "RS256", parsed.Header["alg"])
+
+	t.Run("new key verifies old tokens", func(t *testing.T) {
+		newService := newJWTServiceWithKeys(t, dir, ed25519KeyID)
+
+		newToken, err := newService.GenerateToken(user)
+		assert.NoError(t, err)
+
+		_, err = newService.ParseToken(*newToken)
+		assert.NoError(t, err)
+		_, err = newService.ParseToken(*oldToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("retired key within grace period", func(t *testing.T) {
+		t.Setenv(service.DotEnvJWTRetiredKeys, rsaKeyID+"="+time.Now().Format(time.RFC3339))
+		t.Setenv(service.DotEnvJWTKeyGracePeriod, "3600")
+		newService := newJWTServiceWithKeys(t, dir, ed25519KeyID)
+
+		_, err := newService.ParseToken(*oldToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("retired key after grace period", func(t *testing.T) {
+		t.Setenv(service.DotEnvJWTRetiredKeys, rsaKeyID+"="+time.Now().Add(-time.Hour).Format(time.RFC3339))
+		t.Setenv(service.DotEnvJWTKeyGracePeriod, "60")
+		newService := newJWTServiceWithKeys(t, dir, ed25519KeyID)
+
+		_, err := newService.ParseToken(*oldToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		otherService := newJWTServiceWithKeys(t, setUpKeysDir(t), ed25519KeyID)
+		otherToken, err := otherService.GenerateToken(user)
+		assert.NoError(t, err)
+
+		_, err = oldService.ParseToken(*otherToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("shared secret tokens are rejected without JWT_SECRET", func(t *testing.T) {
+		legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("jwt-secret"))
+		assert.NoError(t, err)
+
+		_, err = oldService.ParseToken(legacy)
+		assert.Error(t, err)
+	})
+}
+
+func TestJWTService_RetiredSecret(t *testing.T) {
+	dir := setUpKeysDir(t)
+
+	newLegacyToken := func(t *testing.T, issuedAt time.Time) string {
+		t.Helper()
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"iat": issuedAt.Unix(),
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("jwt-secret"))
+		if err != nil {
+			t.Fatalf("error signing token: %v", err)
+		}
+
+		return token
+	}
+
+	newService := func(t *testing.T, retiredAt string) *service.JWTService {
+		t.Helper()
+
+		t.Setenv(service.DotEnvJWTExpiration, "3600")
+		t.Setenv(service.DotEnvJWTSecret, "jwt-secret")
+		t.Setenv(service.DotEnvJWTKeysDir, dir)
+		t.Setenv(service.DotEnvJWTSigningKeyID, ed25519KeyID)
+		t.Setenv(service.DotEnvJWTSecretRetired, retiredAt)
+
+		jwtService, err := service.NewJWTService()
+		if err != nil {
+			t.Fatalf("error creating JWT service: %v", err)
+		}
+
+		return jwtService
+	}
+
+	now := time.Now().Format(time.RFC3339)
+
+	t.Run("issued before the switch", func(t *testing.T) {
+		jwtService := newService(t, now)
+
+		_, err := jwtService.ParseToken(newLegacyToken(t, time.Now().Add(-time.Minute)))
+		assert.NoError(t, err)
+	})
+
+	t.Run("issued after the switch", func(t *testing.T) {
+		jwtService := newService(t, now)
+
+		_, err := jwtService.ParseToken(newLegacyToken(t, time.Now().Add(time.Minute)))
+		assert.Error(t, err)
+	})
+
+	t.Run("without issue time", func(t *testing.T) {
+		jwtService := newService(t, now)
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("jwt-secret"))
+		assert.NoError(t, err)
+
+		_, err = jwtService.ParseToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("after grace period", func(t *testing.T) {
+		jwtService := newService(t, time.Now().Add(-2*time.Hour).Format(time.RFC3339))
+
+		_, err := jwtService.ParseToken(newLegacyToken(t, time.Now().Add(-3*time.Hour)))
+		assert.Error(t, err)
+	})
+
+	t.Run("restart does not reopen the grace period", func(t *testing.T) {
+		retiredAt := time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+		token := newLegacyToken(t, time.Now().Add(-3*time.Hour))
+
+		for range 2 {
+			jwtService := newService(t, retiredAt)
+
+			_, err := jwtService.ParseToken(token)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("missing retirement time", func(t *testing.T) {
+		t.Setenv(service.DotEnvJWTExpiration, "3600")
+		t.Setenv(service.DotEnvJWTSecret, "jwt-secret")
+		t.Setenv(service.DotEnvJWTKeysDir, dir)
+		t.Setenv(service.DotEnvJWTSigningKeyID, ed25519KeyID)
+		t.Setenv(service.DotEnvJWTSecretRetired, "")
+
+		_, err := service.NewJWTService()
+		assert.Error(t, err)
+	})
+
+	t.Run("malformed retirement time", func(t *testing.T) {
+		t.Setenv(service.DotEnvJWTExpiration, "3600")
+		t.Setenv(service.DotEnvJWTSecret, "jwt-secret")
+		t.Setenv(service.DotEnvJWTKeysDir, dir)
+		t.Setenv(service.DotEnvJWTSigningKeyID, ed25519KeyID)
+		t.Setenv(service.DotEnvJWTSecretRetired, "yesterday")
+
+		_, err := service.NewJWTService()
+		assert.Error(t, err)
+	})
+}
+
+func TestJWTService_InvalidKeyConfiguration(t *testing.T) {
+	dir := setUpKeysDir(t)
+
+	testCases := []struct {
+		name         string
+		signingKeyID string
+		retiredKeys  string
+	}{
+		{
+			name:         "unknown signing key",
+			signingKeyID: "missing",
+		},
+		{
+			name:         "public signing key",
+			signingKeyID: publicKeyID,
+		},
+		{
+			name:         "retired signing key",
+			signingKeyID: ed25519KeyID,
+			retiredKeys:  ed25519KeyID + "=2026-01-01T00:00:00Z",
+		},
+		{
+			name:         "malformed retired keys",
+			signingKeyID: ed25519KeyID,
+			retiredKeys:  rsaKeyID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(service.DotEnvJWTExpiration, "3600")
+			t.Setenv(service.DotEnvJWTKeysDir, dir)
+			t.Setenv(service.DotEnvJWTSigningKeyID, tc.signingKeyID)
+			t.Setenv(service.DotEnvJWTRetiredKeys, tc.retiredKeys)
+
+			jwtService, err := service.NewJWTService()
+
+			assert.Error(t, err)
+			assert.Nil(t, jwtService)
+		})
+	}
+}
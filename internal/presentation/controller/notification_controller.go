@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const paramUnread = "unread"
+
+type NotificationController struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationController(notificationService *service.NotificationService) *NotificationController {
+	return &NotificationController{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications godoc
+//
+//	@Summary		Get notifications
+//	@Description	Returns the inbox of the authenticated user, newest first, with the total and unread counts
+//	@Tags			Notifications
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page	query		int		false	"Page number"				default(1)
+//	@Param			limit	query		int		false	"Notifications per page"	default(10)
+//	@Param			unread	query		bool	false	"Only return unread notifications"
+//	@Success		200		{object}	dto.NotificationListResponse
+//	@Failure		400		{object}	pkg.ErrorResponse	"Invalid query params"
+//	@Failure		401		{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		500		{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/me/notifications [get]
+func (nc *NotificationController) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	log.Print("NotificationController.GetNotifications called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	page, limit, err := parsePagination(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	unreadOnly := false
+	if unreadStr := r.URL.Query().Get(paramUnread); unreadStr != "" {
+		unreadOnly, err = strconv.ParseBool(unreadStr)
+		if err != nil {
+			pkg.SendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	notifications, err := nc.notificationService.GetNotifications(userID, unreadOnly, page, limit)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	total, err := nc.notificationService.Count(userID, unreadOnly)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	unread := total
+	if !unreadOnly {
+		unread, err = nc.notificationService.Count(userID, true)
+		if err != nil {
+			pkg.SendError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	resp := dto.NotificationListResponse{
+		Items:  make([]*dto.NotificationResponse, 0, len(notifications)),
+		Total:  total,
+		Unread: unread,
+		Page:   page,
+		Limit:  limit,
+	}
+	for _, notification := range notifications {
+		resp.Items = append(resp.Items, dto.NewNotificationResponse(notification))
+	}
+
+	pkg.SendJSON(w, http.StatusOK, resp)
+}
+
+// MarkNotificationRead godoc
+//
+//	@Summary		Mark a notification as read
+//	@Description	Marks a notification of the authenticated user as read; marking it again keeps the original read time
+//	@Tags			Notifications
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Notification ID"	format(uuid)
+//	@Success		204
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	pkg.ErrorResponse	"Notification not found"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/me/notifications/{id}/read [post]
+func (nc *NotificationController) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	log.Print("NotificationController.MarkNotificationRead called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, service.ErrorNotificationNotFound.Error())
+		return
+	}
+
+	if err = nc.notificationService.MarkRead(id, userID); err != nil {
+		if errors.Is(err, service.ErrorNotificationNotFound) {
+			pkg.SendError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllNotificationsRead godoc
+//
+//	@Summary		Mark all notifications as read
+//	@Description	Marks every unread notification of the authenticated user as read
+//	@Tags			Notifications
+//	@Security		BearerAuth
+//	@Success		204
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/me/notifications/read [post]
+func (nc *NotificationController) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	log.Print("NotificationController.MarkAllNotificationsRead called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	if err := nc.notificationService.MarkAllRead(userID); err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
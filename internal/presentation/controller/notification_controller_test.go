@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/pubsub"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type notificationControllerTest struct {
+	ctrl                   *gomock.Controller
+	notificationRepo       *service.MockNotificationRepository
+	notificationController *NotificationController
+}
+
+func setUpNotificationControllerTest(t *testing.T) *notificationControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockNotificationRepo := service.NewMockNotificationRepository(ctrl)
+
+	return &notificationControllerTest{
+		ctrl:             ctrl,
+		notificationRepo: mockNotificationRepo,
+		notificationController: NewNotificationController(service.NewNotificationService(mockNotificationRepo,
+			pubsub.NewMemoryPubSub())),
+	}
+}
+
+func newNotificationRequest(method, target string, userID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+
+	return req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+}
+
+func TestNotificationController_GetNotifications(t *testing.T) {
+	test := setUpNotificationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	search := &entity.SavedSearch{ID: uuid.New(), UserID: userID, Name: "bikes"}
+	ad := &entity.Ad{ID: uuid.New(), Title: "Red bike"}
+	notification := entity.NewSearchMatchNotification(search, ad)
+
+	test.notificationRepo.EXPECT().
+		FindByUser(userID, false, 5, 5).
+		Return([]*entity.Notification{notification}, nil)
+
+	test.notificationRepo.EXPECT().
+		CountByUser(userID, false).
+		Return(int64(6), nil)
+
+	test.notificationRepo.EXPECT().
+		CountByUser(userID, true).
+		Return(int64(2), nil)
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.notificationController.GetNotifications)
+	handler.ServeHTTP(w, newNotificationRequest(http.MethodGet, "/api/me/notifications?page=2&limit=5", userID))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.NotificationListResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), resp.Total)
+	assert.Equal(t, int64(2), resp.Unread)
+	assert.Equal(t, 2, resp.Page)
+	if assert.Len(t, resp.Items, 1) {
+		assert.Equal(t, notification.ID, resp.Items[0].ID)
+		assert.Equal(t, entity.NotificationSearchMatch, resp.Items[0].Type)
+		assert.Equal(t, "bikes", resp.Items[0].SearchName)
+		assert.Equal(t, "Red bike", resp.Items[0].AdTitle)
+	}
+}
+
+func TestNotificationController_GetNotifications_UnreadOnly(t *testing.T) {
+	test := setUpNotificationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+
+	test.notificationRepo.EXPECT().
+		FindByUser(userID, true, entity.LimitDefaultValue, 0).
+		Return([]*entity.Notification{}, nil)
+
+	test.notificationRepo.EXPECT().
+		CountByUser(userID, true).
+		Return(int64(0), nil).
+		Times(1)
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.notificationController.GetNotifications)
+	handler.ServeHTTP(w, newNotificationRequest(http.MethodGet, "/api/me/notifications?unread=true", userID))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.NotificationListResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.Items)
+	assert.Empty(t, resp.Items)
+}
+
+func TestNotificationController_GetNotifications_InvalidQuery(t *testing.T) {
+	test := setUpNotificationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.notificationRepo.EXPECT().
+		FindByUser(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(0)
+
+	handler := http.HandlerFunc(test.notificationController.GetNotifications)
+	for _, query := range []string{"page=0", "limit=1000", "unread=maybe"} {
+		t.Run(query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, newNotificationRequest(http.MethodGet, "/api/me/notifications?"+query, uuid.New()))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestNotificationController_GetNotifications_Unauthorized(t *testing.T) {
+	test := setUpNotificationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me/notifications", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.notificationController.GetNotifications)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestNotificationController_MarkNotificationRead(t *testing.T) {
+	test := setUpNotificationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	id, missingID := uuid.New(), uuid.New()
+
+	test.notificationRepo.EXPECT().
+		MarkRead(id, userID, gomock.Any()).
+		Return(true, nil)
+
+	test.notificationRepo.EXPECT().
+		MarkRead(missingID, userID, gomock.Any()).
+		Return(false, nil)
+
+	handler := http.HandlerFunc(test.notificationController.MarkNotificationRead)
+	markRead := func(notificationID string) *httptest.ResponseRecorder {
+		req := newNotificationRequest(http.MethodPost, "/api/me/notifications/"+notificationID+"/read", userID)
+		req = mux.SetURLVars(req, map[string]string{"id": notificationID})
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	assert.Equal(t, http.StatusNoContent, markRead(id.String()).Code)
+	assert.Equal(t, http.StatusNotFound, markRead(missingID.String()).Code)
+	assert.Equal(t, http.StatusNotFound, markRead("not-a-uuid").Code)
+}
+
+func TestNotificationController_MarkAllNotificationsRead(t *testing.T) {
+	test := setUpNotificationControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+
+	test.notificationRepo.EXPECT().
+		MarkAllRead(userID, gomock.Any()).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.notificationController.MarkAllNotificationsRead)
+	handler.ServeHTTP(w, newNotificationRequest(http.MethodPost, "/api/me/notifications/read", userID))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
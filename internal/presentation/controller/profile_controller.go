@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/gorilla/mux"
+)
+
+type ProfileController struct {
+	userService   *service.UserService
+	adService     *service.AdService
+	reviewService *service.ReviewService
+}
+
+func NewProfileController(userService *service.UserService, adService *service.AdService,
+	reviewService *service.ReviewService) *ProfileController {
+	return &ProfileController{
+		userService:   userService,
+		adService:     adService,
+		reviewService: reviewService,
+	}
+}
+
+// GetProfile godoc
+//
+//	@Summary		Get the public profile of a user
+//	@Description	Returns the join date of a user, the number of their published ads and their rating as a seller
+//	@Tags			users
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Success		200			{object}	dto.ProfileResponse
+//	@Failure		404			{object}	pkg.ErrorResponse	"User not found"
+//	@Failure		500			{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/users/{username} [get]
+func (pc *ProfileController) GetProfile(w http.ResponseWriter, r *http.Request) {
+	log.Print("ProfileController.GetProfile called")
+
+	user, err := pc.userService.GetByUsername(mux.Vars(r)[pathParamUsername])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	adCount, err := pc.adService.CountAds(&entity.Options{AuthorID: user.ID})
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	rating, err := pc.reviewService.Rating(user.ID)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	pkg.SendJSON(w, http.StatusOK, dto.NewProfileResponse(user, adCount, rating))
+}
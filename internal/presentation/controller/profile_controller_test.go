@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/pubsub"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type profileControllerTest struct {
+	ctrl              *gomock.Controller
+	userRepo          *service.MockUserRepository
+	adRepo            *service.MockAdRepository
+	reviewRepo        *service.MockReviewRepository
+	profileController *ProfileController
+}
+
+func setUpProfileControllerTest(t *testing.T) *profileControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+
+	mockUserRepo := service.NewMockUserRepository(ctrl)
+	mockAdRepo := service.NewMockAdRepository(ctrl)
+	mockReviewRepo := service.NewMockReviewRepository(ctrl)
+
+	profileController := NewProfileController(
+		service.NewUserService(mockUserRepo, nil),
+		service.NewAdService(mockAdRepo, pubsub.NewMemoryPubSub()),
+		service.NewReviewService(mockReviewRepo, nil),
+	)
+
+	return &profileControllerTest{
+		ctrl:              ctrl,
+		userRepo:          mockUserRepo,
+		adRepo:            mockAdRepo,
+		reviewRepo:        mockReviewRepo,
+		profileController: profileController,
+	}
+}
+
+func (test *profileControllerTest) getProfile(username string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/users/"+username, nil)
+	req = mux.SetURLVars(req, map[string]string{"username": username})
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.profileController.GetProfile)
+	handler.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestProfileController_GetProfile(t *testing.T) {
+	test := setUpProfileControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{
+		ID:        uuid.New(),
+		Username:  usernameConst,
+		Password:  "hash",
+		Role:      entity.RoleUser,
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	test.userRepo.EXPECT().
+		GetByUsername(usernameConst).
+		Return(user, nil)
+
+	test.adRepo.EXPECT().
+		Count(gomock.Any()).
+		DoAndReturn(func(ops *entity.Options) (int64, error) {
+			assert.Equal(t, user.ID, ops.AuthorID)
+
+			return 7, nil
+		})
+
+	test.reviewRepo.EXPECT().
+		FindRatings([]uuid.UUID{user.ID}).
+		Return([]*entity.Rating{{UserID: user.ID, Count: 4, Sum: 18}}, nil)
+
+	w := test.getProfile(usernameConst)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "password")
+
+	var resp dto.ProfileResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, resp.ID)
+	assert.Equal(t, usernameConst, resp.Username)
+	assert.Equal(t, user.CreatedAt, resp.JoinedAt)
+	assert.Equal(t, int64(7), resp.AdCount)
+	assert.Equal(t, 4.5, resp.Rating)
+	assert.Equal(t, int64(4), resp.ReviewCount)
+}
+
+func TestProfileController_GetProfile_WithoutReviews(t *testing.T) {
+	test := setUpProfileControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst, CreatedAt: time.Now()}
+
+	test.userRepo.EXPECT().
+		GetByUsername(usernameConst).
+		Return(user, nil)
+
+	test.adRepo.EXPECT().
+		Count(gomock.Any()).
+		Return(int64(0), nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings([]uuid.UUID{user.ID}).
+		Return(nil, nil)
+
+	w := test.getProfile(usernameConst)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.ProfileResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Zero(t, resp.AdCount)
+	assert.Zero(t, resp.Rating)
+	assert.Zero(t, resp.ReviewCount)
+}
+
+func TestProfileController_GetProfile_NotFound(t *testing.T) {
+	test := setUpProfileControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.userRepo.EXPECT().
+		GetByUsername("nobody").
+		Return(nil, errors.New("no rows in result set"))
+
+	w := test.getProfile("nobody")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, service.ErrorUserWithUsernameDoesNotExists.Error(), resp.Error)
+}
+
+func TestProfileController_GetProfile_CountError(t *testing.T) {
+	test := setUpProfileControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.userRepo.EXPECT().
+		GetByUsername(usernameConst).
+		Return(&entity.User{ID: uuid.New(), Username: usernameConst}, nil)
+
+	test.adRepo.EXPECT().
+		Count(gomock.Any()).
+		Return(int64(0), errors.New("test error"))
+
+	w := test.getProfile(usernameConst)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
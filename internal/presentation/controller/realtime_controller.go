@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/application/validator"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/presentation/realtime"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/google/uuid"
+)
+
+const headerLastEventID = "Last-Event-ID"
+
+type RealtimeController struct {
+	hub       *realtime.Hub
+	adStream  *realtime.AdStream
+	validator *validator.AdValidator
+}
+
+func NewRealtimeController(hub *realtime.Hub, adStream *realtime.AdStream,
+	validator *validator.AdValidator) *RealtimeController {
+	return &RealtimeController{
+		hub:       hub,
+		adStream:  adStream,
+		validator: validator,
+	}
+}
+
+// Connect godoc
+//
+//	@Summary		Receive events over WebSocket
+//	@Description	Upgrades to a WebSocket that pushes dto.EventResponse JSON messages: new messages of the user's conversations, new notifications and status changes of the user's ads. Browsers, which cannot set the Authorization header, pass the subprotocols access_token and the token instead, and the server selects access_token. The server pings every 54 seconds and drops clients that stop answering. It closes the socket with 1013 when the client falls too far behind, 1008 when the token expires and 1001 on shutdown; clients should reconnect and reload what they missed
+//	@Tags			Realtime
+//	@Security		BearerAuth
+//	@Param			Sec-WebSocket-Protocol	header	string	false	"access_token, followed by the access token"
+//	@Success		101
+//	@Failure		400	{string}	string				"Not a WebSocket handshake"
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Router			/api/ws [get]
+func (rc *RealtimeController) Connect(w http.ResponseWriter, r *http.Request) {
+	log.Print("RealtimeController.Connect called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	expiresAt, ok := r.Context().Value(service.ExpiryKey).(time.Time)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	rc.hub.Serve(w, r, userID, expiresAt)
+}
+
+// StreamAds godoc
+//
+//	@Summary		Stream newly listed ads
+//	@Description	Sends every ad as soon as it is listed as a Server-Sent Event of type ad.created with a dto.AdResponse as data, optionally only those within the price filters. A client reconnecting with the Last-Event-ID header first receives the ads it missed, as long as they are among the latest 256. The stream ends when the client falls too far behind; EventSource clients then reconnect and resume by themselves
+//	@Tags			Ads
+//	@Produce		text/event-stream
+//	@Param			minPrice		query		number	false	"Minimum price"
+//	@Param			maxPrice		query		number	false	"Maximum price"
+//	@Param			Last-Event-ID	header		string	false	"ID of the last event received"
+//	@Success		200				{object}	dto.AdResponse
+//	@Failure		400				{object}	pkg.ErrorResponse	"Invalid query params"
+//	@Failure		503				{string}	string				"Server is shutting down"
+//	@Router			/api/ads/stream [get]
+func (rc *RealtimeController) StreamAds(w http.ResponseWriter, r *http.Request) {
+	log.Print("RealtimeController.StreamAds called")
+
+	// The stream has no pages, only the price filters apply to it.
+	ops := &entity.Options{
+		Page:  1,
+		Limit: entity.LimitDefaultValue,
+	}
+	if err := parsePrices(r.URL.Query(), ops); err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := rc.validator.ValidateOptions(ops); err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rc.adStream.Serve(w, r, ops, r.Header.Get(headerLastEventID))
+}
@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/middleware"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/application/validator"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/pubsub"
+	"github.com/alishashelby/marketplace/internal/presentation/realtime"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type realtimeControllerTest struct {
+	ctrl               *gomock.Controller
+	adRepo             *service.MockAdRepository
+	adService          *service.AdService
+	realtimeController *RealtimeController
+}
+
+func setUpRealtimeControllerTest(t *testing.T) *realtimeControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+
+	events := pubsub.NewMemoryPubSub()
+	mockAdRepo := service.NewMockAdRepository(ctrl)
+	adStream := realtime.NewAdStream(events)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go adStream.Run(ctx)
+	t.Cleanup(func() {
+		adStream.Shutdown()
+		cancel()
+	})
+
+	realtimeController := NewRealtimeController(realtime.NewHub(events), adStream,
+		validator.NewAdValidator(nil, nil, nil))
+
+	return &realtimeControllerTest{
+		ctrl:               ctrl,
+		adRepo:             mockAdRepo,
+		adService:          service.NewAdService(mockAdRepo, events),
+		realtimeController: realtimeController,
+	}
+}
+
+func TestRealtimeController_StreamAds(t *testing.T) {
+	test := setUpRealtimeControllerTest(t)
+	defer test.ctrl.Finish()
+
+	server := httptest.NewServer(http.HandlerFunc(test.realtimeController.StreamAds))
+	defer server.Close()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(server.URL + "/api/ads/stream?min_price=100&max_price=200")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst}
+	cheap := entity.NewAd(titleConst, textConst, nil, 0, 50, categoryIDConst, user)
+	pending := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst, Pending: true}}, 0, 150,
+		categoryIDConst, user)
+	listed := entity.NewAd(titleConst, textConst, nil, 0, 150, categoryIDConst, user)
+
+	test.adRepo.EXPECT().
+		Save(gomock.Any()).
+		Times(3).
+		Return(nil)
+
+	for _, a := range []*entity.Ad{cheap, pending, listed} {
+		assert.NoError(t, test.adService.Create(a))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line == "\n" {
+			break
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "id: "))
+	assert.Equal(t, "event: "+entity.EventAdCreated, lines[1])
+
+	var adResp dto.AdResponse
+	err = json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &adResp)
+	assert.NoError(t, err)
+	assert.Equal(t, listed.ID, adResp.ID)
+	assert.Equal(t, 150.0, adResp.Price)
+}
+
+func TestRealtimeController_StreamAds_InvalidPrices(t *testing.T) {
+	test := setUpRealtimeControllerTest(t)
+	defer test.ctrl.Finish()
+
+	for _, query := range []string{"min_price=cheap", "max_price=-1", "min_price=300&max_price=200"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/ads/stream?"+query, nil)
+		w := httptest.NewRecorder()
+
+		handler := http.HandlerFunc(test.realtimeController.StreamAds)
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestRealtimeController_Connect_Unauthorized(t *testing.T) {
+	test := setUpRealtimeControllerTest(t)
+	defer test.ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ws", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.realtimeController.Connect)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRealtimeController_Connect_TokenProtocol(t *testing.T) {
+	test := setUpRealtimeControllerTest(t)
+	defer test.ctrl.Finish()
+
+	t.Setenv(service.DotEnvJWTExpiration, "3600")
+	t.Setenv(service.DotEnvJWTSecret, "jwt-secret")
+
+	jwtService, err := service.NewJWTService()
+	if err != nil {
+		t.Fatalf("error creating JWT service: %v", err)
+	}
+	mockRevocationRepo := service.NewMockRevocationRepository(test.ctrl)
+	revocationService := service.NewRevocationService(mockRevocationRepo, time.Hour)
+
+	server := httptest.NewServer(middleware.WebSocketAuthMiddleware(jwtService, revocationService,
+		http.HandlerFunc(test.realtimeController.Connect)))
+	defer server.Close()
+
+	token, err := jwtService.GenerateToken(&entity.User{ID: uuid.New(), Role: entity.RoleUser})
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/ws"
+
+	t.Run("Protocol", func(t *testing.T) {
+		mockRevocationRepo.EXPECT().
+			IsRevoked(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(false, nil)
+
+		dialer := websocket.Dialer{Subprotocols: []string{middleware.WebSocketTokenProtocol, *token}}
+		conn, _, err := dialer.Dial(url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close() //nolint:errcheck
+
+		assert.Equal(t, middleware.WebSocketTokenProtocol, conn.Subprotocol())
+	})
+
+	t.Run("Query parameter", func(t *testing.T) {
+		conn, resp, err := websocket.DefaultDialer.Dial(url+"?access_token="+*token, nil)
+		if conn != nil {
+			conn.Close() //nolint:errcheck
+		}
+
+		assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/application/validator"
+	"github.com/alishashelby/marketplace/internal/infrastructure/repository/ad"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type ReviewController struct {
+	reviewService *service.ReviewService
+	userService   *service.UserService
+	validator     *validator.ReviewValidator
+}
+
+func NewReviewController(reviewService *service.ReviewService, userService *service.UserService,
+	validator *validator.ReviewValidator) *ReviewController {
+	return &ReviewController{
+		reviewService: reviewService,
+		userService:   userService,
+		validator:     validator,
+	}
+}
+
+// CreateReview godoc
+//
+//	@Summary		Review the author of an advertisement
+//	@Description	Rates the author of an ad with 1 to 5 stars. Only a buyer who has messaged the author about the ad can review it, once; the ad does not have to be listed anymore
+//	@Tags			Reviews
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"Ad ID"	format(uuid)
+//	@Param			review	body		dto.ReviewDTO	true	"Rating and text"
+//	@Success		201		{object}	dto.ReviewResponse
+//	@Failure		400		{object}	pkg.ValidationErrorResponse	"Validation or parsing error, or the ad is your own"
+//	@Failure		401		{object}	pkg.ErrorResponse			"Unauthorized"
+//	@Failure		403		{object}	pkg.ErrorResponse			"No conversation about the ad"
+//	@Failure		404		{object}	pkg.ErrorResponse			"Ad not found"
+//	@Failure		409		{object}	pkg.ErrorResponse			"Ad already reviewed"
+//	@Failure		500		{object}	pkg.ErrorResponse			"Internal server error"
+//	@Router			/api/ads/{id}/reviews [post]
+func (rc *ReviewController) CreateReview(w http.ResponseWriter, r *http.Request) {
+	log.Print("ReviewController.CreateReview called")
+
+	adID, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, ad.ErrorAdNotFound.Error())
+		return
+	}
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	reviewDTO := dto.ReviewDTO{}
+	if err = json.NewDecoder(r.Body).Decode(&reviewDTO); err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	reviewDTO.Text = strings.TrimSpace(reviewDTO.Text)
+
+	if errs := rc.validator.Validate(reviewDTO); errs != nil {
+		pkg.SendValidationError(w, http.StatusBadRequest, errs)
+		return
+	}
+
+	review, err := rc.reviewService.Create(adID, userID, reviewDTO.Rating, reviewDTO.Text)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrorReviewNotAllowed):
+			pkg.SendError(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, service.ErrorSelfReview):
+			pkg.SendError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrorReviewExists):
+			pkg.SendError(w, http.StatusConflict, err.Error())
+		default:
+			pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	pkg.SendJSON(w, http.StatusCreated, dto.NewReviewResponse(review))
+}
+
+// GetUserReviews godoc
+//
+//	@Summary		Get reviews of a user
+//	@Description	Returns the reviews a user has got as the author of ads, newest first, with their average rating
+//	@Tags			Reviews
+//	@Produce		json
+//	@Param			id		path		string	true	"User ID"			format(uuid)
+//	@Param			page	query		int		false	"Page number"		default(1)
+//	@Param			limit	query		int		false	"Reviews per page"	default(10)
+//	@Success		200		{object}	dto.ReviewListResponse
+//	@Failure		400		{object}	pkg.ErrorResponse	"Invalid query params"
+//	@Failure		404		{object}	pkg.ErrorResponse	"User not found"
+//	@Failure		500		{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/users/{id}/reviews [get]
+func (rc *ReviewController) GetUserReviews(w http.ResponseWriter, r *http.Request) {
+	log.Print("ReviewController.GetUserReviews called")
+
+	id, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, service.ErrorUserWithIDDoesNotExists.Error())
+		return
+	}
+
+	page, limit, err := parsePagination(r)
+	if err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err = rc.userService.GetByID(id); err != nil {
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	reviews, err := rc.reviewService.GetBySeller(id, page, limit)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	rating, err := rc.reviewService.Rating(id)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := dto.ReviewListResponse{
+		Items:  make([]*dto.ReviewResponse, 0, len(reviews)),
+		Total:  rating.Count,
+		Rating: dto.RoundRating(rating.Average()),
+		Page:   page,
+		Limit:  limit,
+	}
+	for _, review := range reviews {
+		resp.Items = append(resp.Items, dto.NewReviewResponse(review))
+	}
+
+	pkg.SendJSON(w, http.StatusOK, resp)
+}
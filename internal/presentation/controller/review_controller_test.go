@@ -0,0 +1,327 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/application/validator"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/pubsub"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const reviewTextConst = "Friendly seller, the bike was just as described."
+
+type reviewControllerTest struct {
+	ctrl             *gomock.Controller
+	reviewRepo       *service.MockReviewRepository
+	conversationRepo *service.MockConversationRepository
+	userRepo         *service.MockUserRepository
+	reviewController *ReviewController
+	buyer            *entity.User
+	seller           *entity.User
+}
+
+func setUpReviewControllerTest(t *testing.T) *reviewControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+
+	mockReviewRepo := service.NewMockReviewRepository(ctrl)
+	mockConversationRepo := service.NewMockConversationRepository(ctrl)
+	mockUserRepo := service.NewMockUserRepository(ctrl)
+
+	reviewController := NewReviewController(
+		service.NewReviewService(mockReviewRepo,
+			service.NewConversationService(mockConversationRepo, pubsub.NewMemoryPubSub())),
+		service.NewUserService(mockUserRepo, nil),
+		validator.NewReviewValidator(),
+	)
+
+	return &reviewControllerTest{
+		ctrl:             ctrl,
+		reviewRepo:       mockReviewRepo,
+		conversationRepo: mockConversationRepo,
+		userRepo:         mockUserRepo,
+		reviewController: reviewController,
+		buyer:            &entity.User{ID: uuid.New(), Username: "buyer"},
+		seller:           &entity.User{ID: uuid.New(), Username: usernameConst},
+	}
+}
+
+func (test *reviewControllerTest) newConversation() *entity.Conversation {
+	testAd := entity.NewAd(titleConst, textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, priceConst,
+		categoryIDConst, test.seller)
+
+	return entity.NewConversation(testAd, test.buyer)
+}
+
+func newReviewBody(t *testing.T, rating int, text string) string {
+	t.Helper()
+
+	body, err := json.Marshal(dto.ReviewDTO{Rating: rating, Text: text})
+	if err != nil {
+		t.Fatalf("error marshalling review: %v", err)
+	}
+
+	return string(body)
+}
+
+func (test *reviewControllerTest) createReview(t *testing.T, adID uuid.UUID, body string,
+	userID uuid.UUID) *httptest.ResponseRecorder {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.reviewController.CreateReview)
+	handler.ServeHTTP(w, newConversationRequest(http.MethodPost, "/api/ads/"+adID.String()+"/reviews",
+		adID.String(), body, userID))
+
+	return w
+}
+
+func TestReviewController_CreateReview(t *testing.T) {
+	test := setUpReviewControllerTest(t)
+	defer test.ctrl.Finish()
+
+	conversation := test.newConversation()
+
+	test.conversationRepo.EXPECT().
+		FindByAd(conversation.AdID, test.buyer.ID).
+		Return(conversation, nil)
+
+	test.reviewRepo.EXPECT().
+		Save(gomock.Any()).
+		DoAndReturn(func(review *entity.Review) (bool, error) {
+			assert.Equal(t, conversation.AdID, review.AdID)
+			assert.Equal(t, test.buyer.ID, review.ReviewerID)
+			assert.Equal(t, test.seller.ID, review.SellerID)
+			assert.Equal(t, 4, review.Rating)
+			assert.Equal(t, reviewTextConst, review.Text)
+
+			return true, nil
+		})
+
+	w := test.createReview(t, conversation.AdID, newReviewBody(t, 4, " "+reviewTextConst+"\n"), test.buyer.ID)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp dto.ReviewResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, conversation.AdID, resp.AdID)
+	assert.Equal(t, titleConst, resp.AdTitle)
+	assert.Equal(t, test.buyer.ID, resp.Reviewer.ID)
+	assert.Equal(t, test.buyer.Username, resp.Reviewer.Username)
+	assert.Equal(t, 4, resp.Rating)
+	assert.Equal(t, reviewTextConst, resp.Text)
+}
+
+func TestReviewController_CreateReview_WithoutConversation(t *testing.T) {
+	test := setUpReviewControllerTest(t)
+	defer test.ctrl.Finish()
+
+	adID := uuid.New()
+
+	test.conversationRepo.EXPECT().
+		FindByAd(adID, test.buyer.ID).
+		Return(nil, nil)
+
+	w := test.createReview(t, adID, newReviewBody(t, 5, reviewTextConst), test.buyer.ID)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, service.ErrorReviewNotAllowed.Error(), resp.Error)
+}
+
+func TestReviewController_CreateReview_Self(t *testing.T) {
+	test := setUpReviewControllerTest(t)
+	defer test.ctrl.Finish()
+
+	conversation := test.newConversation()
+	conversation.SellerID = test.buyer.ID
+
+	test.conversationRepo.EXPECT().
+		FindByAd(conversation.AdID, test.buyer.ID).
+		Return(conversation, nil)
+
+	w := test.createReview(t, conversation.AdID, newReviewBody(t, 5, reviewTextConst), test.buyer.ID)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, service.ErrorSelfReview.Error(), resp.Error)
+}
+
+func TestReviewController_CreateReview_Duplicate(t *testing.T) {
+	test := setUpReviewControllerTest(t)
+	defer test.ctrl.Finish()
+
+	conversation := test.newConversation()
+
+	test.conversationRepo.EXPECT().
+		FindByAd(conversation.AdID, test.buyer.ID).
+		Return(conversation, nil)
+
+	test.reviewRepo.EXPECT().
+		Save(gomock.Any()).
+		Return(false, nil)
+
+	w := test.createReview(t, conversation.AdID, newReviewBody(t, 5, reviewTextConst), test.buyer.ID)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, service.ErrorReviewExists.Error(), resp.Error)
+}
+
+func TestReviewController_CreateReview_ValidationErrors(t *testing.T) {
+	test := setUpReviewControllerTest(t)
+	defer test.ctrl.Finish()
+
+	rangeError := fmt.Sprintf(validator.ReportRatingRange, "Rating", entity.RatingMin, entity.RatingMax)
+
+	tests := []struct {
+		name   string
+		rating int
+		text   string
+		field  string
+		report string
+	}{
+		{"MissingRating", 0, reviewTextConst, "Rating", rangeError},
+		{"RatingTooHigh", 6, reviewTextConst, "Rating", rangeError},
+		{"BlankText", 3, "   ", "Text", fmt.Sprintf(validator.ReportIsRequired, "Text")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := test.createReview(t, uuid.New(), newReviewBody(t, tt.rating, tt.text), test.buyer.ID)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp pkg.ValidationErrorResponse
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.report, resp.Errors[tt.field])
+		})
+	}
+}
+
+func TestReviewController_CreateReview_Unauthorized(t *testing.T) {
+	test := setUpReviewControllerTest(t)
+	defer test.ctrl.Finish()
+
+	w := test.createReview(t, uuid.New(), newReviewBody(t, 5, reviewTextConst), uuid.Nil)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestReviewController_GetUserReviews(t *testing.T) {
+	test := setUpReviewControllerTest(t)
+	defer test.ctrl.Finish()
+
+	review := entity.NewReview(test.newConversation(), 5, reviewTextConst)
+	review.CreatedAt = time.Now().UTC().Truncate(time.Second)
+
+	test.userRepo.EXPECT().
+		GetByID(test.seller.ID).
+		Return(test.seller, nil)
+
+	test.reviewRepo.EXPECT().
+		FindBySeller(test.seller.ID, 1, 1).
+		Return([]*entity.Review{review}, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings([]uuid.UUID{test.seller.ID}).
+		Return([]*entity.Rating{{UserID: test.seller.ID, Count: 3, Sum: 14}}, nil)
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.reviewController.GetUserReviews)
+	handler.ServeHTTP(w, newConversationRequest(http.MethodGet,
+		"/api/users/"+test.seller.ID.String()+"/reviews?page=2&limit=1", test.seller.ID.String(), "", uuid.Nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.ReviewListResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), resp.Total)
+	assert.Equal(t, 4.67, resp.Rating)
+	assert.Equal(t, 2, resp.Page)
+	assert.Equal(t, 1, resp.Limit)
+	if assert.Len(t, resp.Items, 1) {
+		assert.Equal(t, review.ID, resp.Items[0].ID)
+		assert.Equal(t, test.buyer.Username, resp.Items[0].Reviewer.Username)
+		assert.Equal(t, review.CreatedAt, resp.Items[0].CreatedAt.UTC())
+	}
+}
+
+func TestReviewController_GetUserReviews_Empty(t *testing.T) {
+	test := setUpReviewControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.userRepo.EXPECT().
+		GetByID(test.seller.ID).
+		Return(test.seller, nil)
+
+	test.reviewRepo.EXPECT().
+		FindBySeller(test.seller.ID, entity.LimitDefaultValue, 0).
+		Return(nil, nil)
+
+	test.reviewRepo.EXPECT().
+		FindRatings([]uuid.UUID{test.seller.ID}).
+		Return(nil, nil)
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.reviewController.GetUserReviews)
+	handler.ServeHTTP(w, newConversationRequest(http.MethodGet, "/api/users/"+test.seller.ID.String()+"/reviews",
+		test.seller.ID.String(), "", uuid.Nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.ReviewListResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.Items)
+	assert.Empty(t, resp.Items)
+	assert.Zero(t, resp.Total)
+	assert.Zero(t, resp.Rating)
+}
+
+func TestReviewController_GetUserReviews_UserNotFound(t *testing.T) {
+	test := setUpReviewControllerTest(t)
+	defer test.ctrl.Finish()
+
+	id := uuid.New()
+
+	test.userRepo.EXPECT().
+		GetByID(id).
+		Return(nil, errors.New("no rows in result set"))
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.reviewController.GetUserReviews)
+	handler.ServeHTTP(w, newConversationRequest(http.MethodGet, "/api/users/"+id.String()+"/reviews",
+		id.String(), "", uuid.Nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var resp pkg.ErrorResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, service.ErrorUserWithIDDoesNotExists.Error(), resp.Error)
+}
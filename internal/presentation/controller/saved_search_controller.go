@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/application/validator"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type SavedSearchController struct {
+	savedSearchService *service.SavedSearchService
+	validator          *validator.AdValidator
+}
+
+func NewSavedSearchController(savedSearchService *service.SavedSearchService,
+	validator *validator.AdValidator) *SavedSearchController {
+	return &SavedSearchController{
+		savedSearchService: savedSearchService,
+		validator:          validator,
+	}
+}
+
+// CreateSavedSearch godoc
+//
+//	@Summary		Save a search
+//	@Description	Saves listing filters; the ads created from now on that match them are reported in /api/me/notifications
+//	@Tags			Saved searches
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			search	body		dto.SavedSearchDTO	true	"Name and filters of the search"
+//	@Success		201		{object}	dto.SavedSearchResponse
+//	@Failure		400		{object}	pkg.ValidationErrorResponse	"Validation or parsing error"
+//	@Failure		401		{object}	pkg.ErrorResponse			"Unauthorized"
+//	@Failure		409		{object}	pkg.ErrorResponse			"Too many saved searches"
+//	@Failure		500		{object}	pkg.ErrorResponse			"Internal server error"
+//	@Router			/api/me/searches [post]
+func (sc *SavedSearchController) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	log.Print("SavedSearchController.CreateSavedSearch called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	searchDTO := dto.SavedSearchDTO{}
+	if err := json.NewDecoder(r.Body).Decode(&searchDTO); err != nil {
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errs := sc.validator.ValidateSearch(searchDTO); errs != nil {
+		pkg.SendValidationError(w, http.StatusBadRequest, errs)
+		return
+	}
+
+	search := entity.NewSavedSearch(userID, searchDTO.Name, searchDTO.Options())
+	if err := sc.savedSearchService.Create(search); err != nil {
+		sc.handleSavedSearchError(w, err)
+		return
+	}
+
+	pkg.SendJSON(w, http.StatusCreated, dto.NewSavedSearchResponse(search))
+}
+
+// GetSavedSearches godoc
+//
+//	@Summary		Get saved searches
+//	@Description	Returns the saved searches of the authenticated user, newest first
+//	@Tags			Saved searches
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{array}		dto.SavedSearchResponse
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/me/searches [get]
+func (sc *SavedSearchController) GetSavedSearches(w http.ResponseWriter, r *http.Request) {
+	log.Print("SavedSearchController.GetSavedSearches called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	searches, err := sc.savedSearchService.GetByUser(userID)
+	if err != nil {
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := make([]*dto.SavedSearchResponse, 0, len(searches))
+	for _, search := range searches {
+		resp = append(resp, dto.NewSavedSearchResponse(search))
+	}
+
+	pkg.SendJSON(w, http.StatusOK, resp)
+}
+
+// DeleteSavedSearch godoc
+//
+//	@Summary		Delete a saved search
+//	@Description	Stops the notifications about new matches; the ones already delivered are kept
+//	@Tags			Saved searches
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Saved search ID"	format(uuid)
+//	@Success		204
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	pkg.ErrorResponse	"Saved search not found"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/me/searches/{id} [delete]
+func (sc *SavedSearchController) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	log.Print("SavedSearchController.DeleteSavedSearch called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)[pathParamID])
+	if err != nil {
+		pkg.SendError(w, http.StatusNotFound, service.ErrorSavedSearchNotFound.Error())
+		return
+	}
+
+	if err = sc.savedSearchService.Delete(id, userID); err != nil {
+		sc.handleSavedSearchError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (sc *SavedSearchController) handleSavedSearchError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrorSavedSearchNotFound):
+		pkg.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrorTooManySavedSearches):
+		pkg.SendError(w, http.StatusConflict, err.Error())
+	default:
+		pkg.SendError(w, http.StatusInternalServerError, err.Error())
+	}
+}
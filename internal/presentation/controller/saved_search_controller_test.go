@@ -0,0 +1,423 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/application/validator"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/pubsub"
+	"github.com/alishashelby/marketplace/pkg"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type savedSearchControllerTest struct {
+	ctrl                  *gomock.Controller
+	savedSearchRepo       *service.MockSavedSearchRepository
+	notificationRepo      *service.MockNotificationRepository
+	adRepo                *service.MockAdRepository
+	categoryRepo          *service.MockCategoryRepository
+	events                *pubsub.MemoryPubSub
+	savedSearchService    *service.SavedSearchService
+	savedSearchController *SavedSearchController
+}
+
+func setUpSavedSearchControllerTest(t *testing.T) *savedSearchControllerTest {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+
+	mockSavedSearchRepo := service.NewMockSavedSearchRepository(ctrl)
+	mockNotificationRepo := service.NewMockNotificationRepository(ctrl)
+	mockAdRepo := service.NewMockAdRepository(ctrl)
+	mockCategoryRepo := service.NewMockCategoryRepository(ctrl)
+
+	categoryService := service.NewCategoryService(mockCategoryRepo)
+	events := pubsub.NewMemoryPubSub()
+	savedSearchService := service.NewSavedSearchService(mockSavedSearchRepo, mockAdRepo, categoryService,
+		service.NewNotificationService(mockNotificationRepo, events))
+	adValidator := validator.NewAdValidator(categoryService, nil, nil)
+
+	return &savedSearchControllerTest{
+		ctrl:                  ctrl,
+		savedSearchRepo:       mockSavedSearchRepo,
+		notificationRepo:      mockNotificationRepo,
+		adRepo:                mockAdRepo,
+		categoryRepo:          mockCategoryRepo,
+		events:                events,
+		savedSearchService:    savedSearchService,
+		savedSearchController: NewSavedSearchController(savedSearchService, adValidator),
+	}
+}
+
+func newSavedSearchRequest(t *testing.T, searchDTO dto.SavedSearchDTO, userID uuid.UUID) *http.Request {
+	t.Helper()
+
+	body, err := json.Marshal(searchDTO)
+	if err != nil {
+		t.Fatalf("error marshalling saved search: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/me/searches", bytes.NewReader(body))
+
+	return req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+}
+
+func TestSavedSearchController_CreateSavedSearch(t *testing.T) {
+	test := setUpSavedSearchControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	searchDTO := dto.SavedSearchDTO{
+		Name:     "Cheap bikes",
+		Query:    "bike",
+		MinPrice: 100,
+		MaxPrice: 500,
+		Category: categoryIDConst,
+		SortBy:   entity.SortByPrice,
+	}
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(categoryIDConst).
+		Return(true, nil)
+
+	test.savedSearchRepo.EXPECT().
+		CountByUser(userID).
+		Return(int64(2), nil)
+
+	test.savedSearchRepo.EXPECT().
+		Save(gomock.Any()).
+		Do(func(search *entity.SavedSearch) {
+			assert.Equal(t, userID, search.UserID)
+			assert.Equal(t, "bike", search.Query)
+			assert.Equal(t, categoryIDConst, search.CategoryID)
+			assert.Equal(t, entity.OrderByDesc, search.OrderBy)
+			assert.False(t, search.LastRunAt.IsZero())
+		}).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.savedSearchController.CreateSavedSearch)
+	handler.ServeHTTP(w, newSavedSearchRequest(t, searchDTO, userID))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp dto.SavedSearchResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, resp.ID)
+	assert.Equal(t, searchDTO.Name, resp.Name)
+	assert.Equal(t, entity.SortByPrice, resp.SortBy)
+	assert.Equal(t, 500.0, resp.MaxPrice)
+}
+
+func TestSavedSearchController_CreateSavedSearch_ValidationErrors(t *testing.T) {
+	test := setUpSavedSearchControllerTest(t)
+	defer test.ctrl.Finish()
+
+	test.categoryRepo.EXPECT().
+		ExistsByID(int64(99)).
+		Return(false, nil)
+
+	test.savedSearchRepo.EXPECT().
+		Save(gomock.Any()).
+		Times(0)
+
+	tests := []struct {
+		name      string
+		searchDTO dto.SavedSearchDTO
+		field     string
+	}{
+		{"missing name", dto.SavedSearchDTO{MaxPrice: 10}, "Name"},
+		{"prices", dto.SavedSearchDTO{Name: "bikes", MinPrice: 50, MaxPrice: 10}, validator.SearchFiltersField},
+		{"sort", dto.SavedSearchDTO{Name: "bikes", SortBy: "title"}, validator.SearchFiltersField},
+		{"relevance", dto.SavedSearchDTO{Name: "bikes", SortBy: entity.SortByRelevance}, validator.SearchFiltersField},
+		{"category", dto.SavedSearchDTO{Name: "bikes", Category: 99}, entity.ParamCategory},
+	}
+
+	handler := http.HandlerFunc(test.savedSearchController.CreateSavedSearch)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, newSavedSearchRequest(t, tt.searchDTO, uuid.New()))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp pkg.ValidationErrorResponse
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Contains(t, resp.Errors, tt.field)
+		})
+	}
+}
+
+func TestSavedSearchController_CreateSavedSearch_TooMany(t *testing.T) {
+	test := setUpSavedSearchControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+
+	test.savedSearchRepo.EXPECT().
+		CountByUser(userID).
+		Return(int64(service.MaxSavedSearches), nil)
+
+	test.savedSearchRepo.EXPECT().
+		Save(gomock.Any()).
+		Times(0)
+
+	w := httptest.NewRecorder()
+	handler := http.HandlerFunc(test.savedSearchController.CreateSavedSearch)
+	handler.ServeHTTP(w, newSavedSearchRequest(t, dto.SavedSearchDTO{Name: "bikes"}, userID))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestSavedSearchController_CreateSavedSearch_Unauthorized(t *testing.T) {
+	test := setUpSavedSearchControllerTest(t)
+	defer test.ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/me/searches", bytes.NewReader([]byte(`{"name":"bikes"}`)))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.savedSearchController.CreateSavedSearch)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSavedSearchController_GetSavedSearches(t *testing.T) {
+	test := setUpSavedSearchControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	search := entity.NewSavedSearch(userID, "bikes", &entity.Options{SortBy: entity.SortByCreatedAt, OrderBy: entity.OrderByDesc})
+
+	test.savedSearchRepo.EXPECT().
+		FindByUser(userID).
+		Return([]*entity.SavedSearch{search}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me/searches", nil)
+	req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.savedSearchController.GetSavedSearches)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []dto.SavedSearchResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, search.ID, resp[0].ID)
+}
+
+func TestSavedSearchController_DeleteSavedSearch(t *testing.T) {
+	test := setUpSavedSearchControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	id, missingID := uuid.New(), uuid.New()
+
+	test.savedSearchRepo.EXPECT().
+		Delete(id, userID).
+		Return(true, nil)
+
+	test.savedSearchRepo.EXPECT().
+		Delete(missingID, userID).
+		Return(false, nil)
+
+	handler := http.HandlerFunc(test.savedSearchController.DeleteSavedSearch)
+	del := func(searchID uuid.UUID) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/api/me/searches/"+searchID.String(), nil)
+		req = mux.SetURLVars(req, map[string]string{"id": searchID.String()})
+		req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	assert.Equal(t, http.StatusNoContent, del(id).Code)
+	assert.Equal(t, http.StatusNotFound, del(missingID).Code)
+}
+
+func TestSavedSearchService_RunOnce(t *testing.T) {
+	test := setUpSavedSearchControllerTest(t)
+	defer test.ctrl.Finish()
+
+	now := time.Now()
+	until := now.Add(-time.Minute)
+	lastRunAt := now.Add(-11 * time.Minute)
+
+	buyer := &entity.User{ID: uuid.New(), Username: "buyer"}
+	seller := &entity.User{ID: uuid.New(), Username: "seller"}
+
+	search := &entity.SavedSearch{
+		ID: uuid.New(), UserID: buyer.ID, Name: "bikes", Query: "bike", MaxPrice: 500,
+		CategoryID: categoryIDConst, SortBy: entity.SortByPrice, OrderBy: entity.OrderByAsc, LastRunAt: lastRunAt,
+	}
+	failing := &entity.SavedSearch{ID: uuid.New(), UserID: buyer.ID, Name: "cars", LastRunAt: lastRunAt}
+
+	match := entity.NewAd("Red bike", textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, 200, categoryIDConst, seller)
+	own := entity.NewAd("My bike", textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, 300, categoryIDConst, buyer)
+
+	test.savedSearchRepo.EXPECT().
+		FindDue(until, uuid.Nil, gomock.Any()).
+		Return([]*entity.SavedSearch{search, failing}, nil)
+
+	test.categoryRepo.EXPECT().
+		FindSubtreeIDs(categoryIDConst).
+		Return([]int64{categoryIDConst, 8}, nil)
+
+	test.adRepo.EXPECT().
+		FindMatches(gomock.Any()).
+		Do(func(ops *entity.Options) {
+			assert.Equal(t, "bike", ops.Query)
+			assert.Equal(t, 500.0, ops.MaxPrice)
+			assert.Equal(t, []int64{categoryIDConst, 8}, ops.CategoryIDs)
+			assert.Equal(t, lastRunAt, ops.ListedAfter)
+			assert.Equal(t, until, ops.ListedBefore)
+			assert.Equal(t, entity.SortByCreatedAt, ops.SortBy)
+			assert.Equal(t, entity.OrderByDesc, ops.OrderBy)
+		}).
+		Return([]*entity.Ad{match, own}, nil)
+
+	test.adRepo.EXPECT().
+		FindMatches(gomock.Any()).
+		Return(nil, errors.New("connection refused"))
+
+	test.notificationRepo.EXPECT().
+		SaveAll(gomock.Any()).
+		Do(func(notifications []*entity.Notification) {
+			if assert.Len(t, notifications, 1) {
+				assert.Equal(t, buyer.ID, notifications[0].UserID)
+				assert.Equal(t, entity.NotificationSearchMatch, notifications[0].Type)
+				assert.Equal(t, match.ID, *notifications[0].AdID)
+				assert.Equal(t, search.ID, *notifications[0].SavedSearchID)
+				assert.Equal(t, "Red bike", notifications[0].AdTitle)
+			}
+		}).
+		Return(nil)
+
+	// The failed search keeps its window for the next run.
+	test.savedSearchRepo.EXPECT().
+		SetLastRunAt(search.ID, until).
+		Return(nil)
+
+	sub := test.events.Subscribe(service.UserTopic(buyer.ID), 2)
+	defer sub.Close()
+
+	test.savedSearchService.RunOnce(now)
+
+	if assert.Len(t, sub.Events(), 1) {
+		event := <-sub.Events()
+		assert.Equal(t, entity.EventNotificationCreated, event.Type)
+		assert.Equal(t, match.ID, *event.Payload.(*entity.Notification).AdID)
+	}
+}
+
+func TestSavedSearchService_RunOnce_ListedLate(t *testing.T) {
+	test := setUpSavedSearchControllerTest(t)
+	defer test.ctrl.Finish()
+
+	now := time.Now().Add(5 * time.Minute)
+	until := now.Add(-time.Minute)
+	lastRunAt := now.Add(-11 * time.Minute)
+
+	buyer := &entity.User{ID: uuid.New(), Username: "buyer"}
+	seller := &entity.User{ID: uuid.New(), Username: "seller"}
+	search := &entity.SavedSearch{ID: uuid.New(), UserID: buyer.ID, Name: "bikes", LastRunAt: lastRunAt}
+
+	// Created long before the previous run, listed only after its images
+	// have been verified.
+	late := entity.NewAd("Red bike", textConst, []entity.AdImage{{URL: imageUrlConst, Pending: true}},
+		0, 200, categoryIDConst, seller)
+	late.CreatedAt = lastRunAt.Add(-time.Hour)
+	assert.Equal(t, entity.AdStatusPending, late.Status)
+	late.Images[0].Pending = false
+	late.Activate()
+
+	early := entity.NewAd("Blue bike", textConst, []entity.AdImage{{URL: imageUrlConst}}, 0, 300, categoryIDConst, seller)
+	early.ListedAt = lastRunAt.Add(-time.Minute)
+
+	test.savedSearchRepo.EXPECT().
+		FindDue(until, uuid.Nil, gomock.Any()).
+		Return([]*entity.SavedSearch{search}, nil)
+
+	test.adRepo.EXPECT().
+		FindMatches(gomock.Any()).
+		DoAndReturn(func(ops *entity.Options) ([]*entity.Ad, error) {
+			var ads []*entity.Ad
+			for _, ad := range []*entity.Ad{late, early} {
+				if ad.ListedAt.After(ops.ListedAfter) && !ad.ListedAt.After(ops.ListedBefore) {
+					ads = append(ads, ad)
+				}
+			}
+			return ads, nil
+		})
+
+	test.notificationRepo.EXPECT().
+		SaveAll(gomock.Any()).
+		Do(func(notifications []*entity.Notification) {
+			if assert.Len(t, notifications, 1) {
+				assert.Equal(t, late.ID, *notifications[0].AdID)
+			}
+		}).
+		Return(nil)
+
+	test.savedSearchRepo.EXPECT().
+		SetLastRunAt(search.ID, until).
+		Return(nil)
+
+	test.savedSearchService.RunOnce(now)
+}
+
+func TestSavedSearchService_RunOnce_Batches(t *testing.T) {
+	test := setUpSavedSearchControllerTest(t)
+	defer test.ctrl.Finish()
+
+	now := time.Now()
+	until := now.Add(-time.Minute)
+
+	batch := make([]*entity.SavedSearch, 100)
+	for i := range batch {
+		batch[i] = &entity.SavedSearch{ID: uuid.New(), UserID: uuid.New(), LastRunAt: until.Add(-time.Hour)}
+	}
+	last := batch[len(batch)-1]
+
+	gomock.InOrder(
+		test.savedSearchRepo.EXPECT().
+			FindDue(until, uuid.Nil, len(batch)).
+			Return(batch, nil),
+		test.savedSearchRepo.EXPECT().
+			FindDue(until, last.ID, len(batch)).
+			Return([]*entity.SavedSearch{}, nil),
+	)
+
+	test.adRepo.EXPECT().
+		FindMatches(gomock.Any()).
+		Return([]*entity.Ad{}, nil).
+		Times(len(batch))
+
+	test.notificationRepo.EXPECT().
+		SaveAll(gomock.Any()).
+		Times(0)
+
+	test.savedSearchRepo.EXPECT().
+		SetLastRunAt(gomock.Any(), until).
+		Return(nil).
+		Times(len(batch))
+
+	test.savedSearchService.RunOnce(now)
+}
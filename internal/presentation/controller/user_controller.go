@@ -4,13 +4,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/alishashelby/marketplace/internal/application/dto"
 	"github.com/alishashelby/marketplace/internal/application/service"
 	"github.com/alishashelby/marketplace/internal/application/validator"
 	"github.com/alishashelby/marketplace/pkg"
+	"github.com/google/uuid"
 )
 
 type UserController struct {
@@ -29,12 +32,12 @@ func NewUserController(userService *service.UserService,
 // Register godoc
 //
 //	@Summary		Register a new user
-//	@Description	Creates a new user account and returns a JWT token in the Authorization header
+//	@Description	Creates a new user account and returns an access token (also in the Authorization header) and a refresh token
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
 //	@Param			user	body		dto.UserDTO	true	"User credentials"
-//	@Success		201		{object}	dto.UserDTO
+//	@Success		201		{object}	dto.TokenResponse
 //	@Header			201		{string}	Authorization				"Bearer token"
 //	@Failure		400		{object}	pkg.ValidationErrorResponse	"Validation or parsing error"
 //	@Failure		409		{object}	pkg.ErrorResponse			"User already exists"
@@ -56,30 +59,31 @@ func (s *UserController) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := s.userService.Register(userDTO.Username, userDTO.Password)
+	tokens, err := s.userService.Register(userDTO.Username, userDTO.Password)
 	if err != nil {
 		log.Print("UserController.Register service error:", err)
 		s.handleUserError(w, err)
 		return
 	}
 
-	s.setToken(w, *token)
-	pkg.SendJSON(w, http.StatusCreated, userDTO)
+	s.setToken(w, tokens.AccessToken)
+	pkg.SendJSON(w, http.StatusCreated, dto.NewTokenResponse(tokens))
 }
 
 // Login godoc
 //
 //	@Summary		Authenticate user
-//	@Description	Authenticates the user and returns JWT token
+//	@Description	Authenticates the user and returns an access token and a refresh token
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			user	body		dto.UserDTO				true	"User credentials"
-//	@Success		200		{object}	map[string]interface{}	"token"
-//	@Header			200		{string}	Authorization			"Bearer token"
-//	@Failure		400		{object}	pkg.ErrorResponse		"Invalid request"
-//	@Failure		404		{object}	pkg.ErrorResponse		"User not found"
-//	@Failure		500		{object}	pkg.ErrorResponse		"Internal server error"
+//	@Param			user	body		dto.UserDTO	true	"User credentials"
+//	@Success		200		{object}	dto.TokenResponse
+//	@Header			200		{string}	Authorization		"Bearer token"
+//	@Failure		400		{object}	pkg.ErrorResponse	"Invalid request"
+//	@Failure		403		{object}	pkg.ErrorResponse	"User is banned"
+//	@Failure		404		{object}	pkg.ErrorResponse	"User not found"
+//	@Failure		500		{object}	pkg.ErrorResponse	"Internal server error"
 //	@Router			/api/login [post]
 func (s *UserController) Login(w http.ResponseWriter, r *http.Request) {
 	log.Println("UserController.Login called")
@@ -91,23 +95,186 @@ func (s *UserController) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := s.userService.Login(userDTO.Username, userDTO.Password)
+	tokens, err := s.userService.Login(userDTO.Username, userDTO.Password)
 	if err != nil {
 		log.Print("UserController.Login service error:", err)
 		s.handleUserError(w, err)
 		return
 	}
 
-	s.setToken(w, *token)
-	pkg.SendJSON(w, http.StatusOK, map[string]interface{}{"token": *token})
+	s.setToken(w, tokens.AccessToken)
+	pkg.SendJSON(w, http.StatusOK, dto.NewTokenResponse(tokens))
+}
+
+// RefreshToken godoc
+//
+//	@Summary		Refresh access token
+//	@Description	Exchanges a refresh token for a new access token and a new refresh token. Each refresh token can be used once; reusing one revokes every token issued from the same login
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	body		dto.RefreshTokenDTO	true	"Refresh token"
+//	@Success		200		{object}	dto.TokenResponse
+//	@Header			200		{string}	Authorization				"Bearer token"
+//	@Failure		400		{object}	pkg.ValidationErrorResponse	"Validation or parsing error"
+//	@Failure		401		{object}	pkg.ErrorResponse			"Invalid, expired or reused refresh token"
+//	@Failure		403		{object}	pkg.ErrorResponse			"User is banned"
+//	@Failure		500		{object}	pkg.ErrorResponse			"Internal server error"
+//	@Router			/api/token/refresh [post]
+func (s *UserController) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	log.Println("UserController.RefreshToken called")
+
+	refreshDTO := dto.RefreshTokenDTO{}
+	if err := json.NewDecoder(r.Body).Decode(&refreshDTO); err != nil {
+		log.Print("UserController.RefreshToken parsing error:", err)
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	errs := s.validator.ValidateRefreshToken(refreshDTO)
+	if errs != nil {
+		pkg.SendValidationError(w, http.StatusBadRequest, errs)
+		return
+	}
+
+	tokens, err := s.userService.RefreshToken(refreshDTO.RefreshToken)
+	if err != nil {
+		log.Print("UserController.RefreshToken service error:", err)
+		s.handleUserError(w, err)
+		return
+	}
+
+	s.setToken(w, tokens.AccessToken)
+	pkg.SendJSON(w, http.StatusOK, dto.NewTokenResponse(tokens))
+}
+
+// Logout godoc
+//
+//	@Summary		Log out
+//	@Description	Revokes the access token used for the request. If a refresh token is passed, the refresh tokens issued from the same login are revoked too
+//	@Tags			users
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Param			token	body	dto.LogoutDTO	false	"Refresh token to revoke"
+//	@Success		204
+//	@Failure		400	{object}	pkg.ErrorResponse	"Invalid request"
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/logout [post]
+func (s *UserController) Logout(w http.ResponseWriter, r *http.Request) {
+	log.Println("UserController.Logout called")
+
+	userID, userOK := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	tokenID, tokenOK := r.Context().Value(service.TokenIDKey).(uuid.UUID)
+	expiresAt, expiryOK := r.Context().Value(service.ExpiryKey).(time.Time)
+	if !userOK || !tokenOK || !expiryOK {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	logoutDTO := dto.LogoutDTO{}
+	if err := json.NewDecoder(r.Body).Decode(&logoutDTO); err != nil && !errors.Is(err, io.EOF) {
+		log.Print("UserController.Logout parsing error:", err)
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.userService.Logout(userID, tokenID, expiresAt, logoutDTO.RefreshToken); err != nil {
+		log.Print("UserController.Logout service error:", err)
+		s.handleUserError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogoutAll godoc
+//
+//	@Summary		Log out of all sessions
+//	@Description	Revokes every access and refresh token issued to the user so far
+//	@Tags			users
+//	@Security		BearerAuth
+//	@Success		204
+//	@Failure		401	{object}	pkg.ErrorResponse	"Unauthorized"
+//	@Failure		500	{object}	pkg.ErrorResponse	"Internal server error"
+//	@Router			/api/logout/all [post]
+func (s *UserController) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	log.Println("UserController.LogoutAll called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	if err := s.userService.LogoutAll(userID); err != nil {
+		log.Print("UserController.LogoutAll service error:", err)
+		s.handleUserError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateMe godoc
+//
+//	@Summary		Change username
+//	@Description	Renames the current user. The ads of the user show the new username shortly afterwards
+//	@Tags			users
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			user	body		dto.UsernameDTO	true	"New username"
+//	@Success		200		{object}	dto.UserResponse
+//	@Failure		400		{object}	pkg.ValidationErrorResponse	"Validation or parsing error"
+//	@Failure		401		{object}	pkg.ErrorResponse			"Unauthorized"
+//	@Failure		409		{object}	pkg.ErrorResponse			"Username is already taken"
+//	@Failure		500		{object}	pkg.ErrorResponse			"Internal server error"
+//	@Router			/api/me [patch]
+func (s *UserController) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	log.Println("UserController.UpdateMe called")
+
+	userID, ok := r.Context().Value(service.UserIDKey).(uuid.UUID)
+	if !ok {
+		pkg.SendError(w, http.StatusUnauthorized, unauthorizedError)
+		return
+	}
+
+	usernameDTO := dto.UsernameDTO{}
+	if err := json.NewDecoder(r.Body).Decode(&usernameDTO); err != nil {
+		log.Print("UserController.UpdateMe parsing error:", err)
+		pkg.SendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	errs := s.validator.ValidateUsername(usernameDTO)
+	if errs != nil {
+		pkg.SendValidationError(w, http.StatusBadRequest, errs)
+		return
+	}
+
+	user, err := s.userService.ChangeUsername(userID, usernameDTO.Username)
+	if err != nil {
+		log.Print("UserController.UpdateMe service error:", err)
+		s.handleUserError(w, err)
+		return
+	}
+
+	pkg.SendJSON(w, http.StatusOK, dto.NewUserResponse(user))
 }
 
 func (s *UserController) handleUserError(w http.ResponseWriter, err error) {
 	switch {
-	case errors.Is(err, service.ErrorUserExists):
+	case errors.Is(err, service.ErrorUserExists), errors.Is(err, service.ErrorUsernameTaken):
 		pkg.SendError(w, http.StatusConflict, err.Error())
 	case errors.Is(err, service.ErrorUserWithUsernameDoesNotExists):
 		pkg.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrorInvalidRefreshToken),
+		errors.Is(err, service.ErrorRefreshTokenExpired),
+		errors.Is(err, service.ErrorRefreshTokenReused):
+		pkg.SendError(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrorUserBanned):
+		pkg.SendError(w, http.StatusForbidden, err.Error())
 	default:
 		pkg.SendError(w, http.StatusInternalServerError, err.Error())
 	}
@@ -2,12 +2,18 @@ package controller
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/middleware"
 	"github.com/alishashelby/marketplace/internal/application/service"
 	"github.com/alishashelby/marketplace/internal/application/validator"
 	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/pkg"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -15,6 +21,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 const (
@@ -23,9 +30,13 @@ const (
 )
 
 type userControllerTest struct {
-	ctrl           *gomock.Controller
-	userRepo       *service.MockUserRepository
-	userController *UserController
+	ctrl             *gomock.Controller
+	userRepo         *service.MockUserRepository
+	refreshTokenRepo *service.MockRefreshTokenRepository
+	revocationRepo   *service.MockRevocationRepository
+	jwtService       *service.JWTService
+	revocations      *service.RevocationService
+	userController   *UserController
 }
 
 func setUpUserControllerTest(t *testing.T) *userControllerTest {
@@ -41,15 +52,26 @@ func setUpUserControllerTest(t *testing.T) *userControllerTest {
 	if err != nil {
 		t.Log("Failed to create JWT service")
 	}
-	userService := service.NewUserService(mockUserRepo, JWTService)
+	mockRefreshTokenRepo := service.NewMockRefreshTokenRepository(ctrl)
+	mockRevocationRepo := service.NewMockRevocationRepository(ctrl)
+	revocationService := service.NewRevocationService(mockRevocationRepo, time.Hour)
+	tokenService, err := service.NewTokenService(JWTService, mockRefreshTokenRepo, mockUserRepo, revocationService)
+	if err != nil {
+		t.Log("Failed to create token service")
+	}
+	userService := service.NewUserService(mockUserRepo, tokenService)
 	userValidator := validator.NewUserValidator()
 
 	userController := NewUserController(userService, userValidator)
 
 	return &userControllerTest{
-		ctrl:           ctrl,
-		userRepo:       mockUserRepo,
-		userController: userController,
+		ctrl:             ctrl,
+		userRepo:         mockUserRepo,
+		refreshTokenRepo: mockRefreshTokenRepo,
+		revocationRepo:   mockRevocationRepo,
+		jwtService:       JWTService,
+		revocations:      revocationService,
+		userController:   userController,
 	}
 }
 
@@ -70,6 +92,10 @@ func TestUserController_Register(t *testing.T) {
 		}).
 		Return(nil)
 
+	test.refreshTokenRepo.EXPECT().
+		Save(gomock.Any()).
+		Return(nil)
+
 	userDTO := bytes.NewBufferString(`{"username":"` + usernameConst + `","password":"` + passwordConst + `"}`)
 	req := httptest.NewRequest(http.MethodPost, "/api/register", userDTO)
 	w := httptest.NewRecorder()
@@ -79,18 +105,16 @@ func TestUserController_Register(t *testing.T) {
 
 	assert.Equal(t, http.StatusCreated, w.Code)
 
-	var resp dto.UserDTO
+	var resp dto.TokenResponse
 	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 		t.Errorf("error decoding response body: %v", err)
 	}
 
-	assert.Equal(t, dto.UserDTO{
-		Username: usernameConst,
-		Password: passwordConst,
-	}, resp)
+	assert.NotEmpty(t, resp.Token)
+	assert.NotEmpty(t, resp.RefreshToken)
 	authHeader := w.Header().Get("Authorization")
 	assert.NotEmpty(t, authHeader)
-	assert.Contains(t, authHeader, "Bearer ")
+	assert.Equal(t, "Bearer "+resp.Token, authHeader)
 }
 
 func TestUserController_Register_BadJSON(t *testing.T) {
@@ -216,6 +240,14 @@ func TestUserController_Login(t *testing.T) {
 			Password: string(hashedPassword),
 		}, nil)
 
+	test.refreshTokenRepo.EXPECT().
+		Save(gomock.Any()).
+		Do(func(token *entity.RefreshToken) {
+			assert.Equal(t, userID, token.UserID)
+			assert.Len(t, token.TokenHash, 64)
+		}).
+		Return(nil)
+
 	userDTO := bytes.NewBufferString(fmt.Sprintf(`{"username":"%s","password":"%s"}`,
 		usernameConst, passwordConst))
 	req := httptest.NewRequest(http.MethodPost, "/api/login", userDTO)
@@ -232,6 +264,7 @@ func TestUserController_Login(t *testing.T) {
 	}
 
 	assert.NotEmpty(t, resp["token"])
+	assert.NotEmpty(t, resp["refresh_token"])
 	assert.Equal(t, "Bearer "+resp["token"].(string), w.Header().Get("Authorization"))
 }
 
@@ -310,3 +343,525 @@ func TestUserController_Login_InternalServerError(t *testing.T) {
 
 	assert.Equal(t, service.ErrorInvalidPassword.Error(), resp["error"].(string))
 }
+
+func hashRefreshTokenForTest(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUserController_RefreshToken(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	refreshToken := "refresh-token"
+	current := &entity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		TokenHash: hashRefreshTokenForTest(refreshToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	test.refreshTokenRepo.EXPECT().
+		GetByHash(current.TokenHash).
+		Return(current, nil)
+
+	test.userRepo.EXPECT().
+		GetByID(current.UserID).
+		Return(&entity.User{ID: current.UserID, Username: usernameConst}, nil)
+
+	var next *entity.RefreshToken
+	test.refreshTokenRepo.EXPECT().
+		Rotate(current.ID, gomock.Any()).
+		Do(func(_ uuid.UUID, token *entity.RefreshToken) {
+			next = token
+		}).
+		Return(true, nil)
+
+	body := bytes.NewBufferString(`{"refresh_token":"` + refreshToken + `"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/token/refresh", body)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.userController.RefreshToken)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.TokenResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Errorf("error decoding response body: %v", err)
+	}
+
+	assert.NotEmpty(t, resp.Token)
+	assert.NotEqual(t, refreshToken, resp.RefreshToken)
+	assert.Equal(t, "Bearer "+resp.Token, w.Header().Get("Authorization"))
+	assert.Equal(t, current.FamilyID, next.FamilyID)
+	assert.Equal(t, hashRefreshTokenForTest(resp.RefreshToken), next.TokenHash)
+}
+
+func TestUserController_RefreshToken_Reused(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	refreshToken := "refresh-token"
+	revokedAt := time.Now().Add(-time.Minute)
+	current := &entity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		TokenHash: hashRefreshTokenForTest(refreshToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+		RevokedAt: &revokedAt,
+	}
+
+	test.refreshTokenRepo.EXPECT().
+		GetByHash(current.TokenHash).
+		Return(current, nil)
+
+	test.refreshTokenRepo.EXPECT().
+		RevokeFamily(current.FamilyID).
+		Return(nil)
+
+	test.refreshTokenRepo.EXPECT().
+		Rotate(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	body := bytes.NewBufferString(`{"refresh_token":"` + refreshToken + `"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/token/refresh", body)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.userController.RefreshToken)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), service.ErrorRefreshTokenReused.Error())
+}
+
+func TestUserController_RefreshToken_RotationRace(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	refreshToken := "refresh-token"
+	current := &entity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		TokenHash: hashRefreshTokenForTest(refreshToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	test.refreshTokenRepo.EXPECT().
+		GetByHash(current.TokenHash).
+		Return(current, nil)
+
+	test.userRepo.EXPECT().
+		GetByID(current.UserID).
+		Return(&entity.User{ID: current.UserID, Username: usernameConst}, nil)
+
+	test.refreshTokenRepo.EXPECT().
+		Rotate(current.ID, gomock.Any()).
+		Return(false, nil)
+
+	test.refreshTokenRepo.EXPECT().
+		RevokeFamily(current.FamilyID).
+		Return(nil)
+
+	body := bytes.NewBufferString(`{"refresh_token":"` + refreshToken + `"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/token/refresh", body)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.userController.RefreshToken)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestUserController_RefreshToken_Errors(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	testCases := []struct {
+		name           string
+		payload        string
+		setup          func()
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "bad json",
+			payload:        `{ id: 123 }`,
+			setup:          func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid character 'i'",
+		},
+		{
+			name:           "missing token",
+			payload:        `{}`,
+			setup:          func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "RefreshToken",
+		},
+		{
+			name:    "unknown token",
+			payload: `{"refresh_token":"unknown"}`,
+			setup: func() {
+				test.refreshTokenRepo.EXPECT().
+					GetByHash(hashRefreshTokenForTest("unknown")).
+					Return(nil, errors.New("no rows in result set"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  service.ErrorInvalidRefreshToken.Error(),
+		},
+		{
+			name:    "expired token",
+			payload: `{"refresh_token":"expired"}`,
+			setup: func() {
+				test.refreshTokenRepo.EXPECT().
+					GetByHash(hashRefreshTokenForTest("expired")).
+					Return(&entity.RefreshToken{
+						ID:        uuid.New(),
+						ExpiresAt: time.Now().Add(-time.Hour),
+					}, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  service.ErrorRefreshTokenExpired.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/token/refresh", bytes.NewBufferString(tc.payload))
+			w := httptest.NewRecorder()
+
+			handler := http.HandlerFunc(test.userController.RefreshToken)
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tc.expectedError)
+		})
+	}
+}
+
+func (test *userControllerTest) authorizedHandler(handler http.HandlerFunc) http.Handler {
+	return middleware.AuthMiddleware(test.jwtService, test.revocations, handler)
+}
+
+func (test *userControllerTest) newAccessToken(t *testing.T, userID uuid.UUID) string {
+	t.Helper()
+
+	token, err := test.jwtService.GenerateToken(&entity.User{ID: userID, Username: usernameConst})
+	if err != nil {
+		t.Fatalf("error generating token: %v", err)
+	}
+
+	return *token
+}
+
+func TestUserController_Logout(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	accessToken := test.newAccessToken(t, userID)
+	refreshToken := "refresh-token"
+	familyID := uuid.New()
+
+	test.revocationRepo.EXPECT().
+		IsRevoked(gomock.Any(), userID, gomock.Any()).
+		Return(false, nil)
+
+	test.revocationRepo.EXPECT().
+		Revoke(gomock.Any(), userID, gomock.Any()).
+		Return(nil)
+
+	test.refreshTokenRepo.EXPECT().
+		GetByHash(hashRefreshTokenForTest(refreshToken)).
+		Return(&entity.RefreshToken{UserID: userID, FamilyID: familyID}, nil)
+
+	test.refreshTokenRepo.EXPECT().
+		RevokeFamily(familyID).
+		Return(nil)
+
+	handler := test.authorizedHandler(test.userController.Logout)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/logout",
+		bytes.NewBufferString(`{"refresh_token":"`+refreshToken+`"}`))
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "revoked")
+}
+
+func TestUserController_Logout_ForeignRefreshToken(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	refreshToken := "refresh-token"
+
+	test.revocationRepo.EXPECT().
+		IsRevoked(gomock.Any(), userID, gomock.Any()).
+		Return(false, nil)
+
+	test.revocationRepo.EXPECT().
+		Revoke(gomock.Any(), userID, gomock.Any()).
+		Return(nil)
+
+	test.refreshTokenRepo.EXPECT().
+		GetByHash(hashRefreshTokenForTest(refreshToken)).
+		Return(&entity.RefreshToken{UserID: uuid.New(), FamilyID: uuid.New()}, nil)
+
+	test.refreshTokenRepo.EXPECT().
+		RevokeFamily(gomock.Any()).
+		Times(0)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/logout",
+		bytes.NewBufferString(`{"refresh_token":"`+refreshToken+`"}`))
+	req.Header.Set("Authorization", "Bearer "+test.newAccessToken(t, userID))
+	w := httptest.NewRecorder()
+	test.authorizedHandler(test.userController.Logout).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestUserController_Logout_Unauthorized(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/logout", nil)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.userController.Logout)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestUserController_LogoutAll(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+	accessToken := test.newAccessToken(t, userID)
+	otherAccessToken := test.newAccessToken(t, userID)
+
+	test.revocationRepo.EXPECT().
+		IsRevoked(gomock.Any(), userID, gomock.Any()).
+		Return(false, nil)
+
+	test.revocationRepo.EXPECT().
+		RevokeAllForUser(userID, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	test.refreshTokenRepo.EXPECT().
+		RevokeAllForUser(userID).
+		Return(nil)
+
+	handler := test.authorizedHandler(test.userController.LogoutAll)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/logout/all", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/logout/all", nil)
+	req.Header.Set("Authorization", "Bearer "+otherAccessToken)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "revoked")
+}
+
+func TestUserController_LogoutAll_ServiceError(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	userID := uuid.New()
+
+	test.revocationRepo.EXPECT().
+		IsRevoked(gomock.Any(), userID, gomock.Any()).
+		Return(false, nil)
+
+	test.revocationRepo.EXPECT().
+		RevokeAllForUser(userID, gomock.Any(), gomock.Any()).
+		Return(errors.New("db is down"))
+
+	test.refreshTokenRepo.EXPECT().
+		RevokeAllForUser(gomock.Any()).
+		Times(0)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/logout/all", nil)
+	req.Header.Set("Authorization", "Bearer "+test.newAccessToken(t, userID))
+	w := httptest.NewRecorder()
+	test.authorizedHandler(test.userController.LogoutAll).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestUserController_Login_Banned(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(passwordConst), bcrypt.DefaultCost)
+	if err != nil {
+		t.Errorf("error hashing password: %v", err)
+	}
+	bannedAt := time.Now()
+
+	test.userRepo.EXPECT().
+		GetByUsername(usernameConst).
+		Return(&entity.User{
+			ID:       uuid.New(),
+			Username: usernameConst,
+			Password: string(hashedPassword),
+			Role:     entity.RoleUser,
+			BannedAt: &bannedAt,
+		}, nil)
+
+	test.refreshTokenRepo.EXPECT().
+		Save(gomock.Any()).
+		Times(0)
+
+	userDTO := bytes.NewBufferString(fmt.Sprintf(`{"username":"%s","password":"%s"}`,
+		usernameConst, passwordConst))
+	req := httptest.NewRequest(http.MethodPost, "/api/login", userDTO)
+	w := httptest.NewRecorder()
+
+	handler := http.HandlerFunc(test.userController.Login)
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), service.ErrorUserBanned.Error())
+}
+
+func (test *userControllerTest) updateMe(body string, userID uuid.UUID) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPatch, "/api/me", bytes.NewBufferString(body))
+	if userID != uuid.Nil {
+		req = req.WithContext(context.WithValue(req.Context(), service.UserIDKey, userID))
+	}
+
+	w := httptest.NewRecorder()
+	http.HandlerFunc(test.userController.UpdateMe).ServeHTTP(w, req)
+
+	return w
+}
+
+func TestUserController_UpdateMe(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst, Role: entity.RoleUser}
+
+	test.userRepo.EXPECT().
+		GetByID(user.ID).
+		Return(user, nil)
+
+	test.userRepo.EXPECT().
+		UpdateUsername(user.ID, "renamed").
+		Return(true, nil)
+
+	w := test.updateMe(`{"username":"renamed"}`, user.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.UserResponse
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, resp.ID)
+	assert.Equal(t, "renamed", resp.Username)
+}
+
+func TestUserController_UpdateMe_Unchanged(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst, Role: entity.RoleUser}
+
+	test.userRepo.EXPECT().
+		GetByID(user.ID).
+		Return(user, nil)
+
+	test.userRepo.EXPECT().
+		UpdateUsername(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	w := test.updateMe(`{"username":"`+usernameConst+`"}`, user.ID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestUserController_UpdateMe_Taken(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	user := &entity.User{ID: uuid.New(), Username: usernameConst, Role: entity.RoleUser}
+
+	test.userRepo.EXPECT().
+		GetByID(user.ID).
+		Return(user, nil)
+
+	test.userRepo.EXPECT().
+		UpdateUsername(user.ID, "taken").
+		Return(false, nil)
+
+	w := test.updateMe(`{"username":"taken"}`, user.ID)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), service.ErrorUsernameTaken.Error())
+}
+
+func TestUserController_UpdateMe_ValidationErrors(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	testCases := []struct {
+		name    string
+		payload string
+		report  string
+	}{
+		{"empty dto", `{}`, fmt.Sprintf(validator.ReportIsRequired, "Username")},
+		{"too short", `{"username":"ab"}`, fmt.Sprintf(validator.ReportNeedMoreCharacters, "Username", "3")},
+		{"not only letters", `{"username":"user1"}`, fmt.Sprintf(validator.ReportMustBeOnlyLetters, "Username")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.userRepo.EXPECT().
+				UpdateUsername(gomock.Any(), gomock.Any()).
+				Times(0)
+
+			w := test.updateMe(tc.payload, uuid.New())
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp pkg.ValidationErrorResponse
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.report, resp.Errors["Username"])
+		})
+	}
+}
+
+func TestUserController_UpdateMe_Unauthorized(t *testing.T) {
+	test := setUpUserControllerTest(t)
+	defer test.ctrl.Finish()
+
+	w := test.updateMe(`{"username":"renamed"}`, uuid.Nil)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
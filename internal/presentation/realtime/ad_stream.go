@@ -0,0 +1,243 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+)
+
+const (
+	// replaySize bounds the ads kept for clients resuming with Last-Event-ID.
+	replaySize      = 256
+	keepAlivePeriod = 30 * time.Second
+	eventIDSep      = "-"
+)
+
+// Event IDs are prefixed with the start time to tell earlier runs apart.
+type AdStream struct {
+	events    service.PubSub
+	sub       service.Subscription
+	epoch     string
+	keepAlive time.Duration
+
+	mu      sync.Mutex
+	seq     uint64
+	replay  []*streamedAd
+	clients map[*streamClient]struct{}
+	closing bool
+}
+
+type streamedAd struct {
+	seq   uint64
+	price float64
+	data  []byte
+}
+
+type streamClient struct {
+	events chan *streamedAd
+}
+
+func NewAdStream(events service.PubSub) *AdStream {
+	return &AdStream{
+		events:    events,
+		sub:       events.Subscribe(service.AdsTopic, sendBuffer),
+		epoch:     strconv.FormatInt(time.Now().UnixNano(), 36),
+		keepAlive: keepAlivePeriod,
+		clients:   make(map[*streamClient]struct{}),
+	}
+}
+
+func (s *AdStream) Run(ctx context.Context) {
+	defer func() {
+		s.sub.Close()
+	}()
+
+	for s.consume(ctx) {
+		log.Print("AdStream fell behind the ads topic, some ads are not streamed")
+		s.sub = s.events.Subscribe(service.AdsTopic, sendBuffer)
+	}
+}
+
+func (s *AdStream) consume(ctx context.Context) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-s.sub.Events():
+			if !ok {
+				return true
+			}
+
+			if ad, ok := event.Payload.(*entity.Ad); ok && event.Type == entity.EventAdCreated {
+				s.dispatch(ad)
+			}
+		}
+	}
+}
+
+func (s *AdStream) dispatch(ad *entity.Ad) {
+	data, err := json.Marshal(dto.NewAdResponse(ad))
+	if err != nil {
+		log.Print("AdStream.dispatch error: ", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	streamed := &streamedAd{
+		seq:   s.seq,
+		price: ad.Price,
+		data:  data,
+	}
+
+	if len(s.replay) == replaySize {
+		s.replay = append(s.replay[1:], streamed)
+	} else {
+		s.replay = append(s.replay, streamed)
+	}
+
+	for c := range s.clients {
+		select {
+		case c.events <- streamed:
+		default:
+			s.drop(c)
+		}
+	}
+}
+
+func (s *AdStream) Serve(w http.ResponseWriter, r *http.Request, ops *entity.Options, lastEventID string) {
+	rc := http.NewResponseController(w)
+
+	c := &streamClient{events: make(chan *streamedAd, sendBuffer)}
+	backlog, ok := s.subscribe(c, lastEventID)
+	if !ok {
+		http.Error(w, reportShuttingDown, http.StatusServiceUnavailable)
+		return
+	}
+	defer s.unsubscribe(c)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Keeps reverse proxies from buffering the stream.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, streamed := range backlog {
+		if ops.MatchesPrice(streamed.price) {
+			if err := s.write(w, rc, streamed); err != nil {
+				return
+			}
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(s.keepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case streamed, ok := <-c.events:
+			if !ok {
+				return
+			}
+			if !ops.MatchesPrice(streamed.price) {
+				continue
+			}
+
+			if err := s.write(w, rc, streamed); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			// A comment line keeps idle connections from being timed out.
+			_ = rc.SetWriteDeadline(time.Now().Add(writeWait))
+			if _, err := fmt.Fprint(w, ":\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *AdStream) Shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closing = true
+	for c := range s.clients {
+		s.drop(c)
+	}
+}
+
+func (s *AdStream) write(w http.ResponseWriter, rc *http.ResponseController, streamed *streamedAd) error {
+	_ = rc.SetWriteDeadline(time.Now().Add(writeWait))
+	_, err := fmt.Fprintf(w, "id: %s%s%d\nevent: %s\ndata: %s\n\n",
+		s.epoch, eventIDSep, streamed.seq, entity.EventAdCreated, streamed.data)
+
+	return err
+}
+
+// Registering and replaying under one lock keeps ads from being lost or doubled.
+func (s *AdStream) subscribe(c *streamClient, lastEventID string) ([]*streamedAd, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closing {
+		return nil, false
+	}
+	s.clients[c] = struct{}{}
+
+	if lastEventID == "" {
+		return nil, true
+	}
+
+	var after uint64
+	epoch, seqStr, _ := strings.Cut(lastEventID, eventIDSep)
+	if seq, err := strconv.ParseUint(seqStr, 10, 64); err == nil && epoch == s.epoch {
+		after = seq
+	}
+
+	backlog := make([]*streamedAd, 0)
+	for _, streamed := range s.replay {
+		if streamed.seq > after {
+			backlog = append(backlog, streamed)
+		}
+	}
+
+	return backlog, true
+}
+
+func (s *AdStream) unsubscribe(c *streamClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.drop(c)
+}
+
+// drop must be called with the lock held.
+func (s *AdStream) drop(c *streamClient) {
+	if _, ok := s.clients[c]; !ok {
+		return
+	}
+
+	delete(s.clients, c)
+	close(c.events)
+}
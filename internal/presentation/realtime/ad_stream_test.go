@@ -0,0 +1,249 @@
+package realtime
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/pubsub"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+func startAdStream(t *testing.T, stream *AdStream, ops *entity.Options) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream.Serve(w, r, ops, r.Header.Get("Last-Event-ID"))
+	}))
+	t.Cleanup(func() {
+		stream.Shutdown()
+		server.Close()
+	})
+
+	return server.URL
+}
+
+func openAdStream(t *testing.T, url, lastEventID string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		resp.Body.Close() //nolint:errcheck
+	})
+
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func readEvent(t *testing.T, reader *bufio.Reader) *sseEvent {
+	t.Helper()
+
+	event := &sseEvent{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && event.ID != "":
+			return event
+		case strings.HasPrefix(line, "id: "):
+			event.ID = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.Event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.Data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func readAd(t *testing.T, reader *bufio.Reader) *dto.AdResponse {
+	t.Helper()
+
+	event := readEvent(t, reader)
+	assert.Equal(t, entity.EventAdCreated, event.Event)
+
+	var resp dto.AdResponse
+	if err := json.Unmarshal([]byte(event.Data), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	return &resp
+}
+
+func waitForStreamClients(t *testing.T, stream *AdStream, count int) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		stream.mu.Lock()
+		defer stream.mu.Unlock()
+
+		return len(stream.clients) == count
+	}, time.Second, 5*time.Millisecond)
+}
+
+func newStreamedAd(price float64) *entity.Ad {
+	ad := entity.NewAd("Bike", "A blue bike", nil, 0, price, 1,
+		&entity.User{ID: uuid.New(), Username: "seller"})
+	ad.Status = entity.AdStatusActive
+
+	return ad
+}
+
+func TestAdStream_StreamsListedAds(t *testing.T) {
+	events := pubsub.NewMemoryPubSub()
+	stream := NewAdStream(events)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go stream.Run(ctx)
+
+	url := startAdStream(t, stream, &entity.Options{MinPrice: 100, MaxPrice: 200})
+	resp, reader := openAdStream(t, url, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	waitForStreamClients(t, stream, 1)
+
+	cheap, matching := newStreamedAd(50), newStreamedAd(150)
+	events.Publish(service.AdsTopic, &entity.Event{Type: entity.EventAdCreated, Payload: cheap})
+	events.Publish(service.AdsTopic, &entity.Event{Type: entity.EventAdStatusChanged, Payload: matching})
+	events.Publish(service.AdsTopic, &entity.Event{Type: entity.EventAdCreated, Payload: matching})
+
+	ad := readAd(t, reader)
+	assert.Equal(t, matching.ID, ad.ID)
+	assert.Equal(t, matching.Title, ad.Title)
+	assert.Equal(t, matching.Price, ad.Price)
+}
+
+func TestAdStream_Resume(t *testing.T) {
+	stream := NewAdStream(pubsub.NewMemoryPubSub())
+	url := startAdStream(t, stream, &entity.Options{})
+
+	ads := []*entity.Ad{newStreamedAd(10), newStreamedAd(20), newStreamedAd(30)}
+	for _, ad := range ads {
+		stream.dispatch(ad)
+	}
+
+	t.Run("AfterLastEventID", func(t *testing.T) {
+		_, reader := openAdStream(t, url, stream.epoch+"-1")
+
+		first := readEvent(t, reader)
+		assert.Equal(t, stream.epoch+"-2", first.ID)
+		assert.Contains(t, first.Data, ads[1].ID.String())
+		assert.Equal(t, ads[2].ID, readAd(t, reader).ID)
+	})
+
+	t.Run("EarlierRun", func(t *testing.T) {
+		_, reader := openAdStream(t, url, "earlier-7")
+
+		for _, ad := range ads {
+			assert.Equal(t, ad.ID, readAd(t, reader).ID)
+		}
+	})
+
+	t.Run("WithoutLastEventID", func(t *testing.T) {
+		_, reader := openAdStream(t, url, "")
+		waitForStreamClients(t, stream, 1)
+
+		next := newStreamedAd(40)
+		stream.dispatch(next)
+
+		event := readEvent(t, reader)
+		assert.Equal(t, stream.epoch+"-4", event.ID)
+		assert.Contains(t, event.Data, next.ID.String())
+	})
+}
+
+func TestAdStream_ReplayBounded(t *testing.T) {
+	stream := NewAdStream(pubsub.NewMemoryPubSub())
+
+	for range replaySize + 2 {
+		stream.dispatch(newStreamedAd(10))
+	}
+
+	assert.Len(t, stream.replay, replaySize)
+	assert.Equal(t, uint64(3), stream.replay[0].seq)
+	assert.Equal(t, uint64(replaySize+2), stream.replay[replaySize-1].seq)
+}
+
+func TestAdStream_SlowClient(t *testing.T) {
+	stream := NewAdStream(pubsub.NewMemoryPubSub())
+
+	c := &streamClient{events: make(chan *streamedAd, sendBuffer)}
+	_, ok := stream.subscribe(c, "")
+	assert.True(t, ok)
+
+	for range sendBuffer + 1 {
+		stream.dispatch(newStreamedAd(10))
+	}
+
+	received := 0
+	for range c.events {
+		received++
+	}
+	assert.Equal(t, sendBuffer, received)
+	assert.Empty(t, stream.clients)
+
+	// The dropped client resumes from the last ad it got.
+	c = &streamClient{events: make(chan *streamedAd, sendBuffer)}
+	backlog, ok := stream.subscribe(c, stream.epoch+"-"+strconv.Itoa(sendBuffer))
+	assert.True(t, ok)
+	assert.Len(t, backlog, 1)
+}
+
+func TestAdStream_KeepAlive(t *testing.T) {
+	stream := NewAdStream(pubsub.NewMemoryPubSub())
+	stream.keepAlive = 10 * time.Millisecond
+	url := startAdStream(t, stream, &entity.Options{})
+
+	_, reader := openAdStream(t, url, "")
+
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, ":\n", line)
+}
+
+func TestAdStream_Shutdown(t *testing.T) {
+	stream := NewAdStream(pubsub.NewMemoryPubSub())
+	url := startAdStream(t, stream, &entity.Options{})
+
+	_, reader := openAdStream(t, url, "")
+	waitForStreamClients(t, stream, 1)
+
+	stream.Shutdown()
+
+	_, err := reader.ReadString('\n')
+	assert.ErrorIs(t, err, io.EOF)
+
+	resp, _ := openAdStream(t, url, "")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
@@ -0,0 +1,202 @@
+package realtime
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/middleware"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	closeWait      = time.Second
+	maxMessageSize = 512
+	sendBuffer     = 64
+)
+
+const (
+	reportTooSlow      = "too many pending events"
+	reportTokenExpired = "token expired"
+	reportShuttingDown = "server is shutting down"
+)
+
+type Hub struct {
+	events     service.PubSub
+	upgrader   websocket.Upgrader
+	pingPeriod time.Duration
+	pongWait   time.Duration
+
+	mu      sync.Mutex
+	clients map[*client]struct{}
+	closing bool
+	wg      sync.WaitGroup
+}
+
+// The token protocol is selected, since browsers drop a socket without one.
+func NewHub(events service.PubSub) *Hub {
+	return &Hub{
+		events:     events,
+		upgrader:   websocket.Upgrader{Subprotocols: []string{middleware.WebSocketTokenProtocol}},
+		pingPeriod: pingPeriod,
+		pongWait:   pongWait,
+		clients:    make(map[*client]struct{}),
+	}
+}
+
+func (h *Hub) Serve(w http.ResponseWriter, r *http.Request, userID uuid.UUID, expiresAt time.Time) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Print("Hub.Serve upgrade error: ", err)
+		return
+	}
+
+	c := &client{
+		conn:     conn,
+		sub:      h.events.Subscribe(service.UserTopic(userID), sendBuffer),
+		shutdown: make(chan struct{}),
+		readDone: make(chan struct{}),
+	}
+	if !h.register(c) {
+		c.sub.Close()
+		go c.read(h.pongWait)
+		c.close(websocket.CloseGoingAway, reportShuttingDown)
+		return
+	}
+	defer h.unregister(c)
+
+	c.run(expiresAt, h.pingPeriod, h.pongWait)
+}
+
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	if !h.closing {
+		h.closing = true
+		for c := range h.clients {
+			close(c.shutdown)
+		}
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *Hub) register(c *client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closing {
+		return false
+	}
+	h.clients[c] = struct{}{}
+	h.wg.Add(1)
+
+	return true
+}
+
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.clients, c)
+	h.wg.Done()
+}
+
+// Only run writes data frames; read answers pings and close frames.
+type client struct {
+	conn     *websocket.Conn
+	sub      service.Subscription
+	shutdown chan struct{}
+	readDone chan struct{}
+}
+
+func (c *client) run(expiresAt time.Time, pingPeriod, pongWait time.Duration) {
+	defer c.conn.Close() //nolint:errcheck
+	defer c.sub.Close()
+
+	go c.read(pongWait)
+
+	ping := time.NewTicker(pingPeriod)
+	defer ping.Stop()
+	expiry := time.NewTimer(time.Until(expiresAt))
+	defer expiry.Stop()
+
+	for {
+		select {
+		case event, ok := <-c.sub.Events():
+			if !ok {
+				c.close(websocket.CloseTryAgainLater, reportTooSlow)
+				return
+			}
+
+			resp := dto.NewEventResponse(event)
+			if resp == nil {
+				continue
+			}
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(resp); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		case <-expiry.C:
+			c.close(websocket.ClosePolicyViolation, reportTokenExpired)
+			return
+		case <-c.shutdown:
+			c.close(websocket.CloseGoingAway, reportShuttingDown)
+			return
+		case <-c.readDone:
+			return
+		}
+	}
+}
+
+func (c *client) read(pongWait time.Duration) {
+	defer close(c.readDone)
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (c *client) close(code int, text string) {
+	err := c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text),
+		time.Now().Add(writeWait))
+	if err == nil {
+		select {
+		case <-c.readDone:
+		case <-time.After(closeWait):
+		}
+	}
+
+	_ = c.conn.Close()
+}
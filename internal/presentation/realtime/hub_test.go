@@ -0,0 +1,216 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"github.com/alishashelby/marketplace/internal/application/dto"
+	"github.com/alishashelby/marketplace/internal/application/service"
+	"github.com/alishashelby/marketplace/internal/domain/entity"
+	"github.com/alishashelby/marketplace/internal/infrastructure/pubsub"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type stubSubscription struct {
+	events chan *entity.Event
+}
+
+func (s *stubSubscription) Events() <-chan *entity.Event {
+	return s.events
+}
+
+func (s *stubSubscription) Close() {}
+
+// stubPubSub hands out subscriptions the test can end as if the subscriber
+// had fallen behind.
+type stubPubSub struct {
+	subscriptions chan *stubSubscription
+}
+
+func (p *stubPubSub) Publish(string, *entity.Event) {}
+
+func (p *stubPubSub) Subscribe(string, int) service.Subscription {
+	sub := &stubSubscription{events: make(chan *entity.Event)}
+	p.subscriptions <- sub
+
+	return sub
+}
+
+func startHub(t *testing.T, hub *Hub, userID uuid.UUID, expiresAt time.Time) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hub.Serve(w, r, userID, expiresAt)
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close() //nolint:errcheck
+	})
+
+	return conn
+}
+
+func waitForClients(t *testing.T, hub *Hub, count int) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+
+		return len(hub.clients) == count
+	}, time.Second, 5*time.Millisecond)
+}
+
+func assertClosedWith(t *testing.T, conn *websocket.Conn, code int) {
+	t.Helper()
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, _, err := conn.ReadMessage()
+		if err == nil {
+			continue
+		}
+
+		var closeErr *websocket.CloseError
+		if assert.True(t, errors.As(err, &closeErr), err) {
+			assert.Equal(t, code, closeErr.Code)
+		}
+
+		return
+	}
+}
+
+func TestHub_PushesEvents(t *testing.T) {
+	events := pubsub.NewMemoryPubSub()
+	hub := NewHub(events)
+	userID := uuid.New()
+
+	conn := dial(t, startHub(t, hub, userID, time.Now().Add(time.Hour)))
+	waitForClients(t, hub, 1)
+
+	message := entity.NewMessage(uuid.New(), uuid.New(), "Is it still available?")
+	events.Publish(service.UserTopic(uuid.New()), &entity.Event{Type: entity.EventNotificationCreated})
+	events.Publish(service.UserTopic(userID), &entity.Event{Type: entity.EventMessageCreated, Payload: message})
+
+	var resp struct {
+		Type string              `json:"type"`
+		Data dto.MessageResponse `json:"data"`
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	err := conn.ReadJSON(&resp)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.EventMessageCreated, resp.Type)
+	assert.Equal(t, message.ID, resp.Data.ID)
+	assert.Equal(t, message.Text, resp.Data.Text)
+}
+
+func TestHub_Heartbeat(t *testing.T) {
+	hub := NewHub(pubsub.NewMemoryPubSub())
+	hub.pingPeriod = 10 * time.Millisecond
+	hub.pongWait = 50 * time.Millisecond
+
+	conn := dial(t, startHub(t, hub, uuid.New(), time.Now().Add(time.Hour)))
+
+	var pings atomic.Int32
+	conn.SetPingHandler(func(data string) error {
+		pings.Add(1)
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Answered pings keep the connection open well past pongWait.
+	time.Sleep(200 * time.Millisecond)
+	assert.GreaterOrEqual(t, pings.Load(), int32(5))
+	waitForClients(t, hub, 1)
+}
+
+func TestHub_DropsUnresponsiveClient(t *testing.T) {
+	hub := NewHub(pubsub.NewMemoryPubSub())
+	hub.pingPeriod = 10 * time.Millisecond
+	hub.pongWait = 50 * time.Millisecond
+
+	// The client never reads, so it never answers the pings.
+	dial(t, startHub(t, hub, uuid.New(), time.Now().Add(time.Hour)))
+
+	waitForClients(t, hub, 1)
+	waitForClients(t, hub, 0)
+}
+
+func TestHub_SlowClient(t *testing.T) {
+	events := &stubPubSub{subscriptions: make(chan *stubSubscription, 1)}
+	hub := NewHub(events)
+
+	conn := dial(t, startHub(t, hub, uuid.New(), time.Now().Add(time.Hour)))
+	close((<-events.subscriptions).events)
+
+	assertClosedWith(t, conn, websocket.CloseTryAgainLater)
+	waitForClients(t, hub, 0)
+}
+
+func TestHub_TokenExpiry(t *testing.T) {
+	hub := NewHub(pubsub.NewMemoryPubSub())
+
+	conn := dial(t, startHub(t, hub, uuid.New(), time.Now().Add(50*time.Millisecond)))
+
+	assertClosedWith(t, conn, websocket.ClosePolicyViolation)
+	waitForClients(t, hub, 0)
+}
+
+func TestHub_Shutdown(t *testing.T) {
+	hub := NewHub(pubsub.NewMemoryPubSub())
+	url := startHub(t, hub, uuid.New(), time.Now().Add(time.Hour))
+
+	conn := dial(t, url)
+	waitForClients(t, hub, 1)
+
+	// The client answers the close frame while reading it.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		assertClosedWith(t, conn, websocket.CloseGoingAway)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	assert.NoError(t, hub.Shutdown(ctx))
+	<-closed
+
+	assertClosedWith(t, dial(t, url), websocket.CloseGoingAway)
+}
+
+func TestHub_NotWebSocket(t *testing.T) {
+	hub := NewHub(pubsub.NewMemoryPubSub())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ws", nil)
+	w := httptest.NewRecorder()
+	hub.Serve(w, req, uuid.New(), time.Now().Add(time.Hour))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	waitForClients(t, hub, 0)
+}
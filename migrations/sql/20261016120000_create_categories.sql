@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    parent_id INTEGER REFERENCES categories (id) ON DELETE RESTRICT,
+    name VARCHAR(50) NOT NULL,
+    UNIQUE (parent_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx ON categories (parent_id);
+
+INSERT INTO categories (name)
+VALUES ('Electronics'), ('Home and Garden'), ('Vehicles'), ('Clothing'), ('Hobbies');
+
+INSERT INTO categories (parent_id, name)
+SELECT c.id, child.name
+FROM categories c
+JOIN (VALUES
+    ('Electronics', 'Phones'),
+    ('Electronics', 'Computers'),
+    ('Electronics', 'Audio'),
+    ('Home and Garden', 'Furniture'),
+    ('Home and Garden', 'Appliances'),
+    ('Vehicles', 'Cars'),
+    ('Vehicles', 'Bicycles'),
+    ('Vehicles', 'Spare parts'),
+    ('Clothing', 'Women'),
+    ('Clothing', 'Men'),
+    ('Clothing', 'Kids'),
+    ('Hobbies', 'Books'),
+    ('Hobbies', 'Sports'),
+    ('Hobbies', 'Music instruments')
+) AS child (parent_name, name) ON c.name = child.parent_name AND c.parent_id IS NULL;
+
+INSERT INTO categories (parent_id, name)
+SELECT c.id, child.name
+FROM categories c
+JOIN (VALUES
+    ('Computers', 'Laptops'),
+    ('Computers', 'Desktops'),
+    ('Computers', 'Components')
+) AS child (parent_name, name) ON c.name = child.parent_name;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS categories;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ads live in MongoDB, so ad_id here and in the later tables that refer to ads
+-- is not a foreign key.
+CREATE TABLE IF NOT EXISTS favorites (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    ad_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, ad_id)
+);
+
+CREATE INDEX IF NOT EXISTS favorites_ad_id_idx ON favorites (ad_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS favorites;
+-- +goose StatementEnd
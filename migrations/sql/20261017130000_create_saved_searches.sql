@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    query VARCHAR(100) NOT NULL DEFAULT '',
+    min_price DOUBLE PRECISION NOT NULL DEFAULT 0,
+    max_price DOUBLE PRECISION NOT NULL DEFAULT 0,
+    category_id BIGINT NOT NULL DEFAULT 0,
+    sort_by VARCHAR(20) NOT NULL DEFAULT 'created_at',
+    order_by SMALLINT NOT NULL DEFAULT -1,
+    last_run_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS saved_searches_user_id_idx ON saved_searches (user_id);
+CREATE INDEX IF NOT EXISTS saved_searches_last_run_at_idx ON saved_searches (last_run_at);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    saved_search_id UUID REFERENCES saved_searches (id) ON DELETE SET NULL,
+    search_name VARCHAR(50) NOT NULL DEFAULT '',
+    ad_id UUID,
+    ad_title TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    read_at TIMESTAMPTZ,
+    UNIQUE (saved_search_id, ad_id)
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_created_at_idx ON notifications (user_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS saved_searches;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS conversations (
+    id UUID PRIMARY KEY,
+    ad_id UUID NOT NULL,
+    ad_title TEXT NOT NULL DEFAULT '',
+    buyer_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_message_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (ad_id, buyer_id)
+);
+
+CREATE INDEX IF NOT EXISTS conversations_buyer_id_idx ON conversations (buyer_id, last_message_at DESC);
+CREATE INDEX IF NOT EXISTS conversations_seller_id_idx ON conversations (seller_id, last_message_at DESC);
+
+CREATE TABLE IF NOT EXISTS messages (
+    id UUID PRIMARY KEY,
+    conversation_id UUID NOT NULL REFERENCES conversations (id) ON DELETE CASCADE,
+    sender_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    read_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS messages_conversation_id_created_at_idx ON messages (conversation_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS messages_unread_idx ON messages (conversation_id, sender_id) WHERE read_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS messages;
+DROP TABLE IF EXISTS conversations;
+-- +goose StatementEnd
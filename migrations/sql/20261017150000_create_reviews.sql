@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reviews (
+    id UUID PRIMARY KEY,
+    ad_id UUID NOT NULL,
+    ad_title TEXT NOT NULL DEFAULT '',
+    reviewer_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (ad_id, reviewer_id),
+    CHECK (reviewer_id <> seller_id)
+);
+
+CREATE INDEX IF NOT EXISTS reviews_seller_id_idx ON reviews (seller_id, created_at DESC);
+
+-- Kept in step with reviews by the trigger below.
+CREATE TABLE IF NOT EXISTS seller_ratings (
+    user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    review_count BIGINT NOT NULL DEFAULT 0,
+    rating_sum BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE OR REPLACE FUNCTION update_seller_rating() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO seller_ratings (user_id, review_count, rating_sum) VALUES (NEW.seller_id, 1, NEW.rating)
+        ON CONFLICT (user_id) DO UPDATE SET review_count = seller_ratings.review_count + 1,
+            rating_sum = seller_ratings.rating_sum + EXCLUDED.rating_sum;
+        RETURN NEW;
+    END IF;
+
+    UPDATE seller_ratings SET review_count = review_count - 1, rating_sum = rating_sum - OLD.rating
+    WHERE user_id = OLD.seller_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reviews_seller_rating AFTER INSERT OR DELETE ON reviews
+    FOR EACH ROW EXECUTE FUNCTION update_seller_rating();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reviews;
+DROP FUNCTION IF EXISTS update_seller_rating();
+DROP TABLE IF EXISTS seller_ratings;
+-- +goose StatementEnd
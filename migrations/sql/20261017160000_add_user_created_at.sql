@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Users registered before the column existed get the time of the migration.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN IF EXISTS created_at;
+-- +goose StatementEnd